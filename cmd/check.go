@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+
+	"github.com/crabest/envguard/internal/compose"
+	"github.com/crabest/envguard/internal/parser"
+	"github.com/crabest/envguard/internal/report"
+	"github.com/crabest/envguard/internal/validator"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var checkCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Check a docker compose file against .env and .env.example",
+	Long: `Check the variables a docker compose file reads against .env and
+.env.example. Every ${VAR} in the file counts, as do variables passed to
+containers by name ("- VAR" or "VAR:" in environment blocks).
+
+Reported:
+  • variables compose needs that .env does not define (errors; warnings for
+    pass-through variables, none for ${VAR:-default})
+  • variables compose reads that .env.example does not declare
+  • keys of .env and .env.example no service consumes, unless a service
+    loads .env through env_file
+
+Like docker compose, .env and .env.example are looked up next to the
+compose file unless given explicitly.
+
+Examples:
+  envguard check --compose docker-compose.yml
+  envguard check --compose deploy/compose.yaml -e deploy/.env.production`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		composePath, _ := cmd.Flags().GetString("compose")
+		envPath, _ := cmd.Flags().GetString("env")
+		examplePath, _ := cmd.Flags().GetString("example")
+
+		if composePath == "" {
+			found, err := compose.Find(".")
+			if err != nil {
+				failUsage(err, "envguard check --compose <file>")
+			}
+			composePath = found
+		}
+
+		file, err := compose.Load(composePath)
+		if err != nil {
+			fail(fmt.Errorf("failed to read %s: %w", composePath, err))
+		}
+
+		projectDir := filepath.Dir(composePath)
+		if envPath == "" {
+			envPath = filepath.Join(projectDir, ".env")
+		}
+		if examplePath == "" {
+			examplePath = filepath.Join(projectDir, ".env.example")
+		}
+
+		// Compose runs without a .env; every variable is then undefined.
+		envEntries, err := parser.ParseEnvFileEntries(envPath)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			fail(fmt.Errorf("failed to parse %s: %w", envPath, err))
+		}
+
+		exampleEntries, err := parser.ParseEnvFileEntries(examplePath)
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			examplePath = ""
+		case err != nil:
+			fail(fmt.Errorf("failed to parse %s: %w", examplePath, err))
+		}
+
+		issues := validator.CheckCompose(file, envPath, envEntries, examplePath, exampleEntries)
+
+		switch {
+		case jsonOutput():
+			printJSON(map[string]interface{}{"compose": composePath, "services": file.Services, "issues": issues})
+		case len(issues) > 0:
+			color.Cyan("🔎 %s (%d services)", composePath, len(file.Services))
+			validator.PrintIssues(issues)
+		default:
+			emit(report.LevelSuccess, "check.ok", "✅", report.Fields{"compose": composePath, "services": file.Services},
+				"%s and %s agree on every variable", composePath, envPath)
+		}
+
+		if validator.HasErrors(issues) {
+			fail(fmt.Errorf("check failed: %s needs variables %s does not define", composePath, envPath))
+		}
+	},
+}
+
+func init() {
+	checkCmd.Flags().StringP("compose", "c", "", "Path to the compose file (default: compose.yaml or docker-compose.yml)")
+	checkCmd.Flags().StringP("env", "e", "", "Path to the .env file (default: next to the compose file)")
+	checkCmd.Flags().StringP("example", "x", "", "Path to the .env.example file (default: next to the compose file)")
+	rootCmd.AddCommand(checkCmd)
+}
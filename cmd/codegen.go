@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/crabest/envguard/internal/codegen"
+	"github.com/crabest/envguard/internal/report"
+	"github.com/crabest/envguard/internal/validator"
+
+	"github.com/spf13/cobra"
+)
+
+var codegenCmd = &cobra.Command{
+	Use:   "codegen",
+	Short: "Generate a typed config module from .env.example",
+	Long: `Generate a typed configuration module from the keys declared in .env.example.
+Types come from "# @type" annotations (string, int, float, bool, url,
+duration, list) and allowed values from "# @enum a,b,c"; keys without a
+@type are strings. Comments above a key become doc comments.
+
+Languages:
+  go          struct with env tags and a Load function using pkg/envguard
+  typescript  env.d.ts typing process.env
+  zod         zod schema parsing process.env into typed values
+  python      frozen dataclass with a from_env constructor
+
+Examples:
+  envguard codegen --lang go --package config --out config/env.go
+  envguard codegen --lang typescript --out env.d.ts
+  envguard codegen --lang python > settings_env.py`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		lang, _ := cmd.Flags().GetString("lang")
+		example, _ := cmd.Flags().GetString("example")
+		out, _ := cmd.Flags().GetString("out")
+		pkg, _ := cmd.Flags().GetString("package")
+		typeName, _ := cmd.Flags().GetString("type")
+
+		if _, err := os.Stat(example); err != nil {
+			fail(fmt.Errorf("failed to read %s: %w", example, err))
+		}
+
+		schema, err := validator.LoadSchema(example)
+		if err != nil {
+			fail(fmt.Errorf("failed to parse %s: %w", example, err))
+		}
+
+		code, err := codegen.Generate(lang, schema, codegen.Options{PackageName: pkg, TypeName: typeName, Source: example})
+		if err != nil {
+			fail(err)
+		}
+
+		if out == "" || out == "-" {
+			fmt.Print(code)
+			return
+		}
+
+		if err := os.WriteFile(out, []byte(code), 0644); err != nil {
+			fail(fmt.Errorf("failed to write %s: %w", out, err))
+		}
+		emit(report.LevelSuccess, "codegen.written", "✅", report.Fields{"lang": lang, "path": out, "keys": len(schema.Keys)},
+			"Generated %s config for %d variables in %s", lang, len(schema.Keys), out)
+	},
+}
+
+func init() {
+	codegenCmd.Flags().StringP("lang", "l", "", "Target language: "+strings.Join(codegen.Languages(), ", "))
+	codegenCmd.Flags().StringP("example", "x", ".env.example", "Path to the .env.example file")
+	codegenCmd.Flags().StringP("out", "O", "", "Write to this file instead of stdout")
+	codegenCmd.Flags().String("package", "config", "Package name for Go output")
+	codegenCmd.Flags().String("type", "Config", "Name of the generated type")
+	codegenCmd.MarkFlagRequired("lang")
+	rootCmd.AddCommand(codegenCmd)
+}
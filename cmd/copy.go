@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var copyCmd = &cobra.Command{
+	Use:     "copy <src> <dst>",
+	Aliases: []string{"clone"},
+	Short:   "Copy an environment to a new name",
+	Long: `Copy an environment file in the .envguard/ directory to a new environment.
+Use --only and --exclude to copy a subset of keys. Both accept
+comma-separated key names or patterns such as FEATURE_*.
+
+Examples:
+  envguard copy staging staging-2
+  envguard copy production perf --exclude DATABASE_URL
+  envguard copy staging flags --only FEATURE_*,LOG_LEVEL`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		only, _ := cmd.Flags().GetStringSlice("only")
+		exclude, _ := cmd.Flags().GetStringSlice("exclude")
+
+		manager, err := newManager()
+		if err != nil {
+			fail(err)
+		}
+
+		// Auto-sync .env changes so the copy includes them
+		if err := manager.AutoSync(); err != nil {
+			fail(err)
+		}
+
+		if err := manager.CopyEnvironment(args[0], args[1], only, exclude); err != nil {
+			fail(err)
+		}
+	},
+}
+
+func init() {
+	copyCmd.Flags().StringSlice("only", nil, "Only copy these keys (comma-separated, patterns allowed)")
+	copyCmd.Flags().StringSlice("exclude", nil, "Skip these keys (comma-separated, patterns allowed)")
+	rootCmd.AddCommand(copyCmd)
+}
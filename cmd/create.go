@@ -1,11 +1,8 @@
 package cmd
 
 import (
-	"os"
+	"fmt"
 
-	"github.com/crabest/envguard/internal/envmanager"
-
-	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 )
 
@@ -22,34 +19,31 @@ Examples:
 	Run: func(cmd *cobra.Command, args []string) {
 		envName, _ := cmd.Flags().GetString("env")
 		if envName == "" {
-			color.Red("Error: environment name is required")
-			color.Yellow("Usage: envguard create -e <environment>")
-			os.Exit(1)
+			failUsage(fmt.Errorf("environment name is required"), "envguard create -e <environment>")
 		}
 
 		fromCurrent, _ := cmd.Flags().GetBool("from-current")
 
-		manager, err := envmanager.NewManager()
+		manager, err := newManager()
 		if err != nil {
-			color.Red("Error: %v", err)
-			os.Exit(1)
+			fail(err)
 		}
 
 		// Auto-sync .env changes to active environment before creating new one
-		manager.SyncActiveEnvironment()
+		if err := manager.AutoSync(); err != nil {
+			fail(err)
+		}
 
 		if !fromCurrent {
 			shouldBase, err := manager.PromptForCurrentEnv()
 			if err != nil {
-				color.Red("Error: %v", err)
-				os.Exit(1)
+				fail(err)
 			}
 			fromCurrent = shouldBase
 		}
 
 		if err := manager.CreateEnvironment(envName, fromCurrent); err != nil {
-			color.Red("Error: %v", err)
-			os.Exit(1)
+			fail(err)
 		}
 	},
 }
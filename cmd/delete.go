@@ -1,11 +1,8 @@
 package cmd
 
 import (
-	"os"
+	"fmt"
 
-	"github.com/crabest/envguard/internal/envmanager"
-
-	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 )
 
@@ -22,23 +19,19 @@ Examples:
 	Run: func(cmd *cobra.Command, args []string) {
 		envName, _ := cmd.Flags().GetString("env")
 		if envName == "" {
-			color.Red("Error: environment name is required")
-			color.Yellow("Usage: envguard delete -e <environment>")
-			os.Exit(1)
+			failUsage(fmt.Errorf("environment name is required"), "envguard delete -e <environment>")
 		}
 
 		noConfirm, _ := cmd.Flags().GetBool("no-confirm")
 		confirm := !noConfirm
 
-		manager, err := envmanager.NewManager()
+		manager, err := newManager()
 		if err != nil {
-			color.Red("Error: %v", err)
-			os.Exit(1)
+			fail(err)
 		}
 
 		if err := manager.DeleteEnvironment(envName, confirm); err != nil {
-			color.Red("Error: %v", err)
-			os.Exit(1)
+			fail(err)
 		}
 	},
 }
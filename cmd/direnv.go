@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/crabest/envguard/internal/shell"
+
+	"github.com/spf13/cobra"
+)
+
+var direnvCmd = &cobra.Command{
+	Use:   "direnv [environment]",
+	Short: "Print direnv code loading an environment from .envguard/",
+	Long: `Print bash for direnv that exports the variables of the active (or the
+named) environment. The active environment is read from the project's
+.env, the same file the shell hook loads; a named one from its file in
+.envguard/. The output watches .envguard/.active, .env and the stored
+file, so direnv reloads after "envguard use" switches, .env is edited or
+a sync updates the environment.
+
+In .envrc:
+  eval "$(envguard direnv)"            # active environment
+  eval "$(envguard direnv staging)"    # always staging
+
+Or define "use envguard" once in ~/.config/direnv/direnvrc:
+  eval "$(envguard direnv --stdlib)"
+
+and write "use envguard" or "use envguard staging" in .envrc.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		stdlib, _ := cmd.Flags().GetBool("stdlib")
+
+		if stdlib {
+			executable, err := os.Executable()
+			if err != nil {
+				executable = "envguard"
+			}
+			fmt.Print(shell.DirenvStdlib(executable))
+			return
+		}
+
+		manager, err := newManager()
+		if err != nil {
+			fail(err)
+		}
+		activePath := manager.GetActivePath()
+
+		envName := ""
+		if len(args) == 1 {
+			envName = args[0]
+		} else if envName, err = manager.GetActiveEnvironment(); err != nil {
+			fmt.Print(shell.DirenvError(activePath, "no active environment; run 'envguard use <env>'"))
+			return
+		}
+
+		if !manager.EnvironmentExists(envName) {
+			fmt.Print(shell.DirenvError(activePath, fmt.Sprintf("environment '%s' does not exist", envName)))
+			return
+		}
+
+		// The active environment comes from .env, as in the shell hook, so
+		// unsynced edits load the same values in both
+		envFile := manager.GetEnvPath(envName)
+		watched := []string{activePath, envFile}
+		if len(args) == 0 {
+			envFile = manager.GetRootEnvPath()
+			watched = append(watched, envFile)
+		}
+
+		script, err := shell.Direnv(envFile, envName, shell.IsProductionLike(".", envName), watched...)
+		if err != nil {
+			fmt.Print(shell.DirenvError(activePath, err.Error()))
+			return
+		}
+		fmt.Print(script)
+	},
+}
+
+func init() {
+	direnvCmd.Flags().Bool("stdlib", false, "Print the use_envguard function for direnvrc instead")
+	rootCmd.AddCommand(direnvCmd)
+}
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/crabest/envguard/internal/exporter"
+	"github.com/crabest/envguard/internal/parser"
+	"github.com/crabest/envguard/internal/report"
+	"github.com/crabest/envguard/internal/validator"
+
+	"github.com/spf13/cobra"
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export a stored environment as Kubernetes manifests or a Docker env file",
+	Long: `Render an environment from .envguard/ in a format other tools consume,
+keeping the order of keys in the file.
+
+Targets:
+  k8s-secret     Secret with base64-encoded data
+  k8s-configmap  ConfigMap with quoted string data
+  docker-env     Literal KEY=value lines for docker run --env-file
+
+With --split, keys classified as secret go into a Secret and the rest into a
+ConfigMap with the same name. Keys are secret when annotated "# @secret" in
+.env.example, when their name looks secret (JWT_SECRET, DB_PASSWORD,
+API_KEY, ...) or when their value is a URL with a password. Use
+"# @secret false" to override the name check.
+
+Examples:
+  envguard export -e production --to k8s-secret --name api-env --namespace prod
+  envguard export -e staging --to k8s-configmap --name api-env --split | kubectl apply -f -
+  envguard export --to k8s-secret --out secret.yaml
+  envguard export -e production --to docker-env --out production.docker.env`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		envName, _ := cmd.Flags().GetString("env")
+		target, _ := cmd.Flags().GetString("to")
+		name, _ := cmd.Flags().GetString("name")
+		namespace, _ := cmd.Flags().GetString("namespace")
+		split, _ := cmd.Flags().GetBool("split")
+		example, _ := cmd.Flags().GetString("example")
+		out, _ := cmd.Flags().GetString("out")
+
+		manager, err := newManager()
+		if err != nil {
+			fail(err)
+		}
+
+		if envName == "" {
+			envName, err = manager.GetActiveEnvironment()
+			if err != nil {
+				failUsage(fmt.Errorf("no environment given and %w", err), "envguard export -e <environment> --to <target>")
+			}
+		}
+		if !manager.EnvironmentExists(envName) {
+			fail(fmt.Errorf("environment '%s' does not exist", envName))
+		}
+
+		if name == "" {
+			name = envName
+		}
+
+		entries, err := parser.ParseEnvFileEntries(manager.GetEnvPath(envName))
+		if err != nil {
+			fail(fmt.Errorf("failed to parse environment '%s': %w", envName, err))
+		}
+
+		schema, err := validator.LoadSchema(example)
+		if err != nil {
+			fail(fmt.Errorf("failed to parse %s: %w", example, err))
+		}
+
+		output, err := exporter.Export(target, entries, exporter.Options{
+			Name:        name,
+			Namespace:   namespace,
+			Environment: envName,
+			Split:       split,
+			IsSecret:    schema.IsSecret,
+		})
+		if err != nil {
+			fail(err)
+		}
+
+		if out == "" || out == "-" {
+			fmt.Print(output)
+			return
+		}
+
+		if err := os.WriteFile(out, []byte(output), 0600); err != nil {
+			fail(fmt.Errorf("failed to write %s: %w", out, err))
+		}
+		emit(report.LevelSuccess, "export.written", "✅", report.Fields{"environment": envName, "target": target, "path": out},
+			"Exported '%s' as %s to %s", envName, target, out)
+	},
+}
+
+func init() {
+	exportCmd.Flags().StringP("env", "e", "", "Environment to export (default: the active environment)")
+	exportCmd.Flags().String("to", "", "Export target: "+strings.Join(exporter.Targets(), ", "))
+	exportCmd.Flags().String("name", "", "Name of the generated object (default: the environment name)")
+	exportCmd.Flags().String("namespace", "", "Kubernetes namespace")
+	exportCmd.Flags().Bool("split", false, "Put secret keys in a Secret and the rest in a ConfigMap")
+	exportCmd.Flags().StringP("example", "x", ".env.example", "Path to the .env.example file with @secret annotations")
+	exportCmd.Flags().StringP("out", "O", "", "Write to this file instead of stdout")
+	exportCmd.MarkFlagRequired("to")
+	rootCmd.AddCommand(exportCmd)
+}
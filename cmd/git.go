@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/crabest/envguard/internal/envmanager"
+	"github.com/crabest/envguard/internal/gitcheck"
+	"github.com/crabest/envguard/internal/report"
+
+	"github.com/spf13/cobra"
+)
+
+var gitCmd = &cobra.Command{
+	Use:   "git",
+	Short: "Keep .env files and secrets out of git",
+}
+
+var gitCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Check .gitignore, staged files and history for committed .env files",
+	Long: `Check that no real .env file ends up in git:
+
+  • .gitignore must cover .env and .envguard/ (offers to add them)
+  • a staged .env or .envguard/*.env is an error, as is any other staged
+    dotenv file with secrets; other staged dotenv files are warnings
+  • every commit reachable from a branch or tag is scanned for dotenv
+    files with secret values
+
+Templates such as .env.example are allowed. Values that are empty,
+placeholders like "change-me" or references like ${API_KEY} do not count
+as secrets. --allow-staged lets listed paths through as warnings; the
+pre-commit hook reads them from $ENVGUARD_ALLOW_STAGED.
+
+Examples:
+  envguard git check
+  envguard git check --staged    # what the pre-commit hook runs
+  ENVGUARD_ALLOW_STAGED=.envguard/ci.env git commit`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		stagedOnly, _ := cmd.Flags().GetBool("staged")
+		allowStaged, _ := cmd.Flags().GetStringSlice("allow-staged")
+
+		allowed := make(map[string]bool, len(allowStaged))
+		for _, p := range allowStaged {
+			allowed[filepath.ToSlash(filepath.Clean(p))] = true
+		}
+
+		repo, err := gitcheck.Open(".")
+		if err != nil {
+			fail(err)
+		}
+
+		unignored, err := repo.Unignored()
+		if err != nil {
+			fail(err)
+		}
+		if len(unignored) > 0 {
+			emit(report.LevelWarning, "git.unignored", "⚠️ ", report.Fields{"paths": unignored},
+				".gitignore does not cover %s", strings.Join(unignored, " and "))
+			if !stagedOnly {
+				fixGitignore(repo, unignored)
+			}
+		}
+
+		problems := 0
+
+		staged, err := repo.Staged()
+		if err != nil {
+			fail(err)
+		}
+		for _, finding := range staged {
+			fields := report.Fields{"path": finding.Path, "secret_keys": finding.SecretKeys}
+			secrets := ""
+			if len(finding.SecretKeys) > 0 {
+				secrets = fmt.Sprintf(" (secrets: %s)", strings.Join(finding.SecretKeys, ", "))
+			}
+
+			switch {
+			case allowed[finding.Path]:
+				emit(report.LevelWarning, "git.staged", "⚠️ ", fields, "%s is staged and allowed by --allow-staged%s", finding.Path, secrets)
+				continue
+			case gitcheck.IsEnvironmentFile(finding.Path):
+				emit(report.LevelError, "git.staged", "❌", fields, "%s is staged; it holds real values and must not be committed%s", finding.Path, secrets)
+			case len(finding.SecretKeys) > 0:
+				emit(report.LevelError, "git.staged", "❌", fields, "%s is staged with secrets: %s", finding.Path, strings.Join(finding.SecretKeys, ", "))
+			default:
+				emit(report.LevelWarning, "git.staged", "⚠️ ", fields, "%s is staged; dotenv files are usually kept out of git", finding.Path)
+				continue
+			}
+			problems++
+			hint("Unstage it with: git rm --cached %s", finding.Path)
+			if stagedOnly {
+				hint("Commit it anyway with: ENVGUARD_ALLOW_STAGED=%s git commit", finding.Path)
+			}
+		}
+
+		if !stagedOnly {
+			history, err := repo.History()
+			if err != nil {
+				fail(err)
+			}
+			for _, finding := range history {
+				problems++
+				emit(report.LevelError, "git.history", "❌", report.Fields{"path": finding.Path, "commit": finding.Commit, "secret_keys": finding.SecretKeys},
+					"%s was committed in %s with secrets: %s", finding.Path, finding.Commit, strings.Join(finding.SecretKeys, ", "))
+			}
+			if len(history) > 0 {
+				hint("Rotate these secrets; removing the file now does not remove it from history")
+			}
+		}
+
+		if problems > 0 {
+			fail(fmt.Errorf("git check failed: %d dotenv files must not be committed", problems))
+		}
+		emit(report.LevelSuccess, "git.ok", "✅", nil, "No dotenv files that must not be committed")
+	},
+}
+
+// fixGitignore offers to add the uncovered paths to .gitignore.
+func fixGitignore(repo *gitcheck.Repo, unignored []string) {
+	manager, err := newManager()
+	if err != nil {
+		fail(err)
+	}
+
+	ok, err := manager.Confirm(fmt.Sprintf("Add %s to .gitignore?", strings.Join(unignored, " and ")), true)
+	if errors.Is(err, envmanager.ErrNonInteractive) {
+		hint("Run 'envguard git check --yes' to add them")
+		return
+	}
+	if err != nil {
+		fail(err)
+	}
+	if !ok {
+		return
+	}
+
+	if err := repo.AddToGitignore(unignored); err != nil {
+		fail(err)
+	}
+	emit(report.LevelSuccess, "git.gitignore_updated", "✅", report.Fields{"paths": unignored},
+		"Added %s to .gitignore", strings.Join(unignored, " and "))
+}
+
+var gitInstallHookCmd = &cobra.Command{
+	Use:   "install-hook",
+	Short: "Install a pre-commit hook running git check and validation",
+	Long: `Install a git pre-commit hook that runs "envguard git check --staged" and,
+when .env and .env.example exist, validates .env. Commits staging .env, a stored environment or another
+dotenv file with secrets, or leaving .env invalid, are rejected. Set
+ENVGUARD_ALLOW_STAGED to a comma-separated list of paths to let them
+through.
+
+An existing pre-commit hook that envguard did not install is only
+replaced with --force.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		force, _ := cmd.Flags().GetBool("force")
+
+		repo, err := gitcheck.Open(".")
+		if err != nil {
+			fail(err)
+		}
+
+		hook, err := repo.InstallHook(force)
+		if err != nil {
+			fail(err)
+		}
+		emit(report.LevelSuccess, "git.hook_installed", "✅", report.Fields{"path": hook}, "Installed pre-commit hook at %s", hook)
+	},
+}
+
+func init() {
+	gitCheckCmd.Flags().Bool("staged", false, "Only check .gitignore and staged files, without prompting")
+	gitCheckCmd.Flags().StringSlice("allow-staged", nil, "Staged paths to report as warnings instead of errors")
+	gitInstallHookCmd.Flags().Bool("force", false, "Replace an existing pre-commit hook")
+	gitCmd.AddCommand(gitCheckCmd)
+	gitCmd.AddCommand(gitInstallHookCmd)
+	rootCmd.AddCommand(gitCmd)
+}
@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/crabest/envguard/internal/envmanager"
+	"github.com/crabest/envguard/internal/importer"
+	"github.com/crabest/envguard/internal/parser"
+	"github.com/crabest/envguard/internal/report"
+
+	"github.com/spf13/cobra"
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Create an environment from a JSON, YAML, TOML or .properties file",
+	Long: `Convert a structured config file into a stored environment in .envguard/.
+
+Nested keys are flattened with underscores and upper-cased (db.host and
+{"db": {"host": ...}} both become DB_HOST, apiKey becomes API_KEY). Lists of
+plain values are joined with commas, lists of objects are indexed
+(servers[0].host → SERVERS_0_HOST), and numbers, booleans and dates are
+written as strings. A preview with masked values is shown before writing.
+
+Examples:
+  envguard import -e staging --from config/staging.json
+  envguard import -e production --from config.yaml --prefix APP_
+  envguard import -e legacy --from app.properties --dry-run --show-values
+  envguard import -e staging --from settings.toml --force --yes`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		envName, _ := cmd.Flags().GetString("env")
+		from, _ := cmd.Flags().GetString("from")
+		format, _ := cmd.Flags().GetString("format")
+		prefix, _ := cmd.Flags().GetString("prefix")
+		force, _ := cmd.Flags().GetBool("force")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		showValues, _ := cmd.Flags().GetBool("show-values")
+
+		if envName == "" || from == "" {
+			failUsage(fmt.Errorf("environment name and source file are required"), "envguard import -e <environment> --from <file>")
+		}
+		if err := envmanager.ValidateEnvName(envName); err != nil {
+			fail(err)
+		}
+
+		vars, err := importer.ImportFile(from, importer.Options{Format: format, Prefix: prefix})
+		if err != nil {
+			fail(err)
+		}
+
+		manager, err := newManager()
+		if err != nil {
+			fail(err)
+		}
+
+		if manager.EnvironmentExists(envName) && !force {
+			fail(fmt.Errorf("environment '%s' already exists (use --force to replace it)", envName))
+		}
+
+		previewImport(vars, from, envName, showValues, !dryRun && len(vars) > 0)
+
+		if dryRun {
+			hint("Dry run: nothing was written")
+			return
+		}
+
+		if len(vars) == 0 {
+			fail(fmt.Errorf("%s does not contain any values", from))
+		}
+
+		ok, err := manager.Confirm(fmt.Sprintf("Write %d variables to %s/%s.env?", len(vars), envmanager.EnvGuardDir, envName), true)
+		if err != nil {
+			fail(err)
+		}
+		if !ok {
+			emit(report.LevelInfo, "import.cancelled", "ℹ️ ", nil, "Import cancelled")
+			return
+		}
+
+		if err := manager.ImportEnvironment(envName, importer.Render(vars), from, force); err != nil {
+			fail(err)
+		}
+	},
+}
+
+// previewImport lists what will be written; when a confirmation follows
+// (pending), the lines stay visible under --quiet.
+func previewImport(vars []importer.Variable, from, envName string, showValues, pending bool) {
+	p := report.Preview{Reporter: reporter, Pending: pending}
+	p.Heading("import.preview", "📥", report.Fields{"source": from, "environment": envName},
+		"%s → %s/%s.env:", from, envmanager.EnvGuardDir, envName)
+
+	for _, v := range vars {
+		value := envmanager.MaskValue(v.Value)
+		if showValues {
+			value = parser.FormatValue(v.Value)
+		}
+
+		message := fmt.Sprintf("%s=%s", v.Key, value)
+		if !strings.EqualFold(strings.ReplaceAll(v.Path, ".", "_"), v.Key) {
+			message += fmt.Sprintf("  (%s)", v.Path)
+		}
+		p.Item("", "import.variable", report.Fields{"key": v.Key, "path": v.Path, "value": value}, "%s", message)
+	}
+
+	p.Note("import.summary", "📊", report.Fields{"count": len(vars)}, "%d variables", len(vars))
+}
+
+func init() {
+	importCmd.Flags().StringP("env", "e", "", "Environment name to create (required)")
+	importCmd.Flags().String("from", "", "File to import: .json, .yaml, .yml, .toml or .properties (required)")
+	importCmd.Flags().String("format", "", "Input format when it cannot be detected from the extension: "+strings.Join(importer.Formats, ", "))
+	importCmd.Flags().String("prefix", "", "Prefix added to every key, e.g. APP_")
+	importCmd.Flags().Bool("force", false, "Replace the environment if it already exists")
+	importCmd.Flags().Bool("dry-run", false, "Show the preview without writing")
+	importCmd.Flags().Bool("show-values", false, "Show values in the preview instead of masking them")
+	importCmd.MarkFlagRequired("env")
+	importCmd.MarkFlagRequired("from")
+	rootCmd.AddCommand(importCmd)
+}
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/crabest/envguard/internal/parser"
+	"github.com/crabest/envguard/internal/report"
+	"github.com/crabest/envguard/internal/validator"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var lintCmd = &cobra.Command{
+	Use:   "lint [file...]",
+	Short: "Check that a runtime reads env files the same way envguard does",
+	Long: `Check env files against the rules of the runtime that will load them and
+report every line it rejects or reads differently from envguard, such as
+quotes Docker keeps or interpolation systemd does not perform.
+
+Dialects:
+  godotenv  envguard's own rules (default)
+  docker    docker run --env-file: literal values, no quotes, no multi-line
+  compose   docker compose env_file: quotes and ${VAR:-default} interpolation
+  shell     sourced by a POSIX shell (set -a; . ./.env)
+  systemd   systemd EnvironmentFile=
+
+Without arguments the root .env is checked; -e checks stored environments.
+
+Examples:
+  envguard lint --dialect docker
+  envguard lint -e production --dialect systemd
+  envguard lint deploy/api.env --dialect compose`,
+	Run: func(cmd *cobra.Command, args []string) {
+		dialectFlag, _ := cmd.Flags().GetString("dialect")
+		envNames, _ := cmd.Flags().GetStringSlice("env")
+
+		dialect, err := parser.ParseDialect(dialectFlag)
+		if err != nil {
+			fail(err)
+		}
+
+		files := args
+		if len(envNames) > 0 {
+			manager, err := newManager()
+			if err != nil {
+				fail(err)
+			}
+			for _, envName := range envNames {
+				if !manager.EnvironmentExists(envName) {
+					fail(fmt.Errorf("environment '%s' does not exist", envName))
+				}
+				files = append(files, manager.GetEnvPath(envName))
+			}
+		}
+		if len(files) == 0 {
+			files = []string{".env"}
+		}
+
+		issues := []validator.Issue{}
+		for _, file := range files {
+			content, err := os.ReadFile(file)
+			if err != nil {
+				fail(fmt.Errorf("failed to read %s: %w", file, err))
+			}
+			found, err := validator.CheckDialect(file, content, dialect)
+			if err != nil {
+				fail(fmt.Errorf("failed to parse %s: %w", file, err))
+			}
+			issues = append(issues, found...)
+		}
+
+		switch {
+		case jsonOutput():
+			printJSON(map[string]interface{}{"dialect": dialect, "files": files, "issues": issues})
+		case len(issues) > 0:
+			color.Red("🔎 %s reads %d lines differently:", dialect, len(issues))
+			validator.PrintIssues(issues)
+		default:
+			emit(report.LevelSuccess, "lint.ok", "✅", report.Fields{"dialect": string(dialect), "files": files},
+				"%s reads %d files exactly as envguard does", dialect, len(files))
+		}
+
+		if validator.HasErrors(issues) {
+			fail(fmt.Errorf("lint failed: %d issues for %s", len(issues), dialect))
+		}
+	},
+}
+
+func init() {
+	lintCmd.Flags().String("dialect", string(parser.DialectGodotenv), "Runtime to check against: godotenv, docker, compose, shell or systemd")
+	lintCmd.Flags().StringSliceP("env", "e", nil, "Stored environments to check instead of files")
+	rootCmd.AddCommand(lintCmd)
+}
@@ -1,10 +1,15 @@
 package cmd
 
 import (
+	"bytes"
 	"fmt"
-	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
 
 	"github.com/crabest/envguard/internal/envmanager"
+	"github.com/crabest/envguard/internal/report"
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
@@ -14,45 +19,178 @@ var listCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List all available environments",
 	Long: `List all environment files stored in the .envguard/ directory.
-Shows the environment names that can be used with use, delete, etc.
+Shows each environment with its variable count, tags, owner, last use and
+description. Metadata can be edited with 'envguard meta'.
 
-Example:
-  envguard list`,
+Examples:
+  envguard list
+  envguard list --tag backend --sort used
+  envguard list --json`,
 	Run: func(cmd *cobra.Command, args []string) {
-		manager, err := envmanager.NewManager()
+		tag, _ := cmd.Flags().GetString("tag")
+		sortBy, _ := cmd.Flags().GetString("sort")
+		asJSON, _ := cmd.Flags().GetBool("json")
+		asJSON = asJSON || jsonOutput()
+
+		manager, err := newManager()
 		if err != nil {
-			color.Red("Error: %v", err)
-			os.Exit(1)
+			fail(err)
 		}
 
-		// Auto-sync .env changes to active environment
-		manager.SyncActiveEnvironment()
-
-		environments, err := manager.ListEnvironments()
+		environments, err := manager.ListEnvironmentMetadata()
 		if err != nil {
-			color.Red("Error: %v", err)
-			os.Exit(1)
+			fail(err)
+		}
+
+		if tag != "" {
+			environments = filterByTag(environments, tag)
+		}
+
+		if err := sortEnvironments(environments, sortBy); err != nil {
+			fail(err)
+		}
+
+		if asJSON {
+			if environments == nil {
+				environments = []envmanager.EnvironmentMetadata{}
+			}
+			printJSON(environments)
+			return
+		}
+
+		if quiet {
+			for _, env := range environments {
+				fmt.Println(env.Name)
+			}
+			return
 		}
 
 		color.Cyan("🌍 Available Environments:")
-		color.Cyan("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+		color.Cyan(report.Rule)
 
 		if len(environments) == 0 {
-			color.Yellow("📭 No environments found in .envguard/ directory")
-			color.Blue("💡 Create your first environment with: envguard create -e <n>")
+			if tag != "" {
+				emit(report.LevelWarning, "environments.empty", "📭", report.Fields{"tag": tag}, "No environments tagged '%s'", tag)
+				return
+			}
+			emit(report.LevelWarning, "environments.empty", "📭", nil, "No environments found in .envguard/ directory")
+			hint("Create your first environment with: envguard create -e <n>")
 			return
 		}
 
+		// Lay the table out in plain text and colour it afterwards, since
+		// tabwriter would count escape codes towards the column widths.
+		var table bytes.Buffer
+		w := tabwriter.NewWriter(&table, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "   \tNAME\tVARS\tTAGS\tOWNER\tLAST USED\tDESCRIPTION")
+		for _, env := range environments {
+			fmt.Fprintf(w, "   %s\t%s\t%d\t%s\t%s\t%s\t%s\n",
+				listMarker(env),
+				listName(env),
+				env.VariableCount,
+				strings.Join(env.Tags, ","),
+				env.Owner,
+				formatAge(env.LastUsedAt),
+				strings.ReplaceAll(env.Description, "\n", " "))
+		}
+		w.Flush()
+
+		lines := strings.Split(strings.TrimSuffix(table.String(), "\n"), "\n")
+		fmt.Println(lines[0])
 		for i, env := range environments {
-			status := fmt.Sprintf("%d.", i+1)
-			fmt.Printf("   %s %s\n", color.BlueString(status), color.GreenString(env))
+			prefix := "   " + listMarker(env)
+			rest := strings.TrimPrefix(lines[i+1], prefix)
+			name := color.GreenString(listName(env))
+			if env.Protected {
+				name = color.RedString(listName(env))
+			}
+			fmt.Println("   " + color.GreenString(listMarker(env)) + strings.Replace(rest, listName(env), name, 1))
 		}
 
 		fmt.Printf("\n📊 Total: %s environments\n", color.CyanString(fmt.Sprintf("%d", len(environments))))
-		color.Blue("💡 Use with: envguard use <environment>")
+		hint("Use with: envguard use <environment>")
 	},
 }
 
 func init() {
+	listCmd.Flags().String("tag", "", "Only show environments with this tag")
+	listCmd.Flags().String("sort", "name", "Sort by name, created, used, modified or vars")
+	listCmd.Flags().Bool("json", false, "Print environments and metadata as JSON")
 	rootCmd.AddCommand(listCmd)
 }
+
+func listMarker(env envmanager.EnvironmentMetadata) string {
+	if env.Active {
+		return "●"
+	}
+	return " "
+}
+
+func listName(env envmanager.EnvironmentMetadata) string {
+	if env.Protected {
+		return env.Name + " (protected)"
+	}
+	return env.Name
+}
+
+func filterByTag(environments []envmanager.EnvironmentMetadata, tag string) []envmanager.EnvironmentMetadata {
+	var filtered []envmanager.EnvironmentMetadata
+	for _, env := range environments {
+		for _, t := range env.Tags {
+			if t == tag {
+				filtered = append(filtered, env)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+func sortEnvironments(environments []envmanager.EnvironmentMetadata, sortBy string) error {
+	newestFirst := func(get func(envmanager.EnvironmentMetadata) *time.Time) func(i, j int) bool {
+		return func(i, j int) bool {
+			a, b := get(environments[i]), get(environments[j])
+			if a == nil || b == nil {
+				return a != nil
+			}
+			return a.After(*b)
+		}
+	}
+
+	var less func(i, j int) bool
+	switch sortBy {
+	case "", "name":
+		less = func(i, j int) bool { return environments[i].Name < environments[j].Name }
+	case "created":
+		less = newestFirst(func(e envmanager.EnvironmentMetadata) *time.Time { return e.CreatedAt })
+	case "used":
+		less = newestFirst(func(e envmanager.EnvironmentMetadata) *time.Time { return e.LastUsedAt })
+	case "modified":
+		less = newestFirst(func(e envmanager.EnvironmentMetadata) *time.Time { return e.LastModified })
+	case "vars":
+		less = func(i, j int) bool { return environments[i].VariableCount > environments[j].VariableCount }
+	default:
+		return fmt.Errorf("unknown sort key '%s' (use name, created, used, modified or vars)", sortBy)
+	}
+
+	sort.SliceStable(environments, less)
+	return nil
+}
+
+func formatAge(t *time.Time) string {
+	if t == nil {
+		return "never"
+	}
+
+	age := time.Since(*t)
+	switch {
+	case age < time.Minute:
+		return "just now"
+	case age < time.Hour:
+		return fmt.Sprintf("%dm ago", int(age.Minutes()))
+	case age < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(age.Hours()))
+	default:
+		return fmt.Sprintf("%dd ago", int(age.Hours()/24))
+	}
+}
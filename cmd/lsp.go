@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/crabest/envguard/internal/lsp"
+
+	"github.com/spf13/cobra"
+)
+
+var lspCmd = &cobra.Command{
+	Use:   "lsp",
+	Short: "Run a language server for .env files over stdio",
+	Long: `Run a Language Server Protocol server on stdin and stdout for .env and
+.env.example files. Editors get:
+• diagnostics for missing, extra, invalid and placeholder values, broken
+  references, malformed rules and lint findings
+• hover with the comment, @type and other annotations from .env.example
+• completion of keys declared in .env.example and of names inside ${...}
+• a quick fix adding missing keys with their example values
+
+Env files are validated against the .env.example next to them; stored
+environments in .envguard/ use the project's .env.example. The lint
+dialect and interpolation mode can be set with the "dialect" and
+"interpolation" initialization options.
+
+Example (Neovim):
+  vim.lsp.start({ name = "envguard", cmd = { "envguard", "lsp" } })`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := lsp.NewServer(os.Stdin, os.Stdout).Run(); err != nil {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	// Many clients pass --stdio; it is the only transport, so it is accepted
+	// and ignored.
+	lspCmd.Flags().Bool("stdio", true, "Communicate over stdin and stdout")
+	lspCmd.Flags().MarkHidden("stdio")
+	rootCmd.AddCommand(lspCmd)
+}
@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/crabest/envguard/internal/envmanager"
+	"github.com/crabest/envguard/internal/report"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var metaCmd = &cobra.Command{
+	Use:   "meta <environment>",
+	Short: "Show or edit an environment's metadata",
+	Long: `Show or edit the description, tags and owner of a stored environment.
+Metadata is kept in .envguard/.metadata.json and shown by 'envguard list'.
+
+Examples:
+  envguard meta production
+  envguard meta production --description "Live API" --owner platform-team
+  envguard meta staging --add-tag backend --remove-tag legacy
+  envguard meta production --protect
+  envguard meta production --unprotect --confirm-protected=production`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		envName := args[0]
+
+		manager, err := newManager()
+		if err != nil {
+			fail(err)
+		}
+
+		flags := cmd.Flags()
+		if flags.Changed("description") || flags.Changed("owner") || flags.Changed("add-tag") || flags.Changed("remove-tag") {
+			description, _ := flags.GetString("description")
+			owner, _ := flags.GetString("owner")
+			addTags, _ := flags.GetStringSlice("add-tag")
+			removeTags, _ := flags.GetStringSlice("remove-tag")
+
+			err := manager.UpdateMetadata(envName, func(meta *envmanager.EnvironmentMetadata) {
+				if flags.Changed("description") {
+					meta.Description = description
+				}
+				if flags.Changed("owner") {
+					meta.Owner = owner
+				}
+				meta.Tags = updateTags(meta.Tags, addTags, removeTags)
+			})
+			if err != nil {
+				fail(err)
+			}
+			emit(report.LevelSuccess, "metadata.updated", "✅", report.Fields{"environment": envName}, "Updated metadata for %s", envName)
+		}
+
+		if flags.Changed("protect") || flags.Changed("unprotect") {
+			protect, _ := flags.GetBool("protect")
+			if flags.Changed("unprotect") {
+				protect = false
+			}
+			if err := manager.SetProtected(envName, protect); err != nil {
+				fail(err)
+			}
+			if protect {
+				emit(report.LevelSuccess, "environment.protected", "🔒", report.Fields{"environment": envName}, "%s is now protected", envName)
+			} else {
+				emit(report.LevelSuccess, "environment.unprotected", "🔓", report.Fields{"environment": envName}, "%s is no longer protected", envName)
+			}
+		}
+
+		meta, err := manager.GetMetadata(envName)
+		if err != nil {
+			fail(err)
+		}
+
+		if jsonOutput() {
+			printJSON(meta)
+			return
+		}
+		if quiet {
+			return
+		}
+
+		color.Cyan("🏷️  Environment Metadata:")
+		color.Cyan(report.Rule)
+		fmt.Printf("📍 Name: %s\n", color.GreenString(meta.Name))
+		fmt.Printf("📝 Description: %s\n", meta.Description)
+		fmt.Printf("🏷️  Tags: %s\n", strings.Join(meta.Tags, ", "))
+		fmt.Printf("👤 Owner: %s\n", meta.Owner)
+		fmt.Printf("🔒 Protected: %t\n", meta.Protected)
+		fmt.Printf("🔢 Variables: %d\n", meta.VariableCount)
+		fmt.Printf("🕐 Created: %s • Last used: %s • Last modified: %s\n",
+			formatAge(meta.CreatedAt), formatAge(meta.LastUsedAt), formatAge(meta.LastModified))
+
+		if len(meta.History) > 0 {
+			fmt.Println("📜 History:")
+			for _, event := range meta.History {
+				line := fmt.Sprintf("   %s %s", formatAge(&event.At), event.Action)
+				if event.From != "" {
+					line += " from " + event.From
+				}
+				fmt.Println(line)
+			}
+		}
+	},
+}
+
+func init() {
+	metaCmd.Flags().String("description", "", "Set the environment description")
+	metaCmd.Flags().String("owner", "", "Set the environment owner")
+	metaCmd.Flags().StringSlice("add-tag", nil, "Add tags (comma-separated)")
+	metaCmd.Flags().StringSlice("remove-tag", nil, "Remove tags (comma-separated)")
+	metaCmd.Flags().Bool("protect", false, "Require confirmation before using, deleting or overwriting")
+	metaCmd.Flags().Bool("unprotect", false, "Remove protection set with --protect, after typing the name or --confirm-protected")
+	rootCmd.AddCommand(metaCmd)
+}
+
+func updateTags(tags, add, remove []string) []string {
+	removed := make(map[string]bool)
+	for _, tag := range remove {
+		removed[tag] = true
+	}
+
+	seen := make(map[string]bool)
+	var result []string
+	for _, tag := range append(append([]string{}, tags...), add...) {
+		if tag == "" || removed[tag] || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		result = append(result, tag)
+	}
+	return result
+}
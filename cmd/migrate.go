@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/crabest/envguard/internal/validator"
+
+	"github.com/spf13/cobra"
+)
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Rename and remove retired keys in .env and every stored environment",
+	Long: `Apply the key renames declared in .env.example to the root .env and to
+every environment in .envguard/. A masked diff is shown before anything is
+written.
+
+  # @renamed-from REDIS_URL
+  CACHE_URL=redis://localhost:6379
+
+renames REDIS_URL to CACHE_URL, keeping its value and position. If CACHE_URL
+is already set to the same value REDIS_URL is dropped; if it differs, both
+are kept and reported as a conflict.
+
+Keys annotated with "# @deprecated" are only removed with --remove-deprecated.
+
+Examples:
+  envguard migrate --dry-run
+  envguard migrate
+  envguard migrate --remove-deprecated --yes`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		removeDeprecated, _ := cmd.Flags().GetBool("remove-deprecated")
+		example, _ := cmd.Flags().GetString("example")
+
+		schema, err := validator.LoadSchema(example)
+		if err != nil {
+			fail(fmt.Errorf("failed to parse %s: %w", example, err))
+		}
+
+		var remove []string
+		if removeDeprecated {
+			remove = schema.DeprecatedKeys()
+		}
+
+		manager, err := newManager()
+		if err != nil {
+			fail(err)
+		}
+
+		migrations, err := manager.PlanMigration(schema.Renames(), remove)
+		if err != nil {
+			fail(err)
+		}
+
+		if err := manager.Migrate(migrations, dryRun); err != nil {
+			fail(err)
+		}
+	},
+}
+
+func init() {
+	migrateCmd.Flags().Bool("dry-run", false, "Show the diff without writing")
+	migrateCmd.Flags().Bool("remove-deprecated", false, "Also remove keys annotated with @deprecated")
+	migrateCmd.Flags().StringP("example", "x", ".env.example", "Path to the .env.example file with key annotations")
+	rootCmd.AddCommand(migrateCmd)
+}
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/crabest/envguard/internal/report"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	quiet        bool
+	verbose      bool
+	outputFormat string
+
+	// reporter receives every user-facing message; it is configured from
+	// --quiet, --verbose and --output before any command runs.
+	reporter report.Reporter = report.NewTextReporter(os.Stdout)
+)
+
+func init() {
+	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "Only print warnings and errors")
+	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Print debug details")
+	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", "text", "Output format: text or json (JSON lines)")
+	rootCmd.PersistentPreRunE = setupOutput
+}
+
+func setupOutput(cmd *cobra.Command, args []string) error {
+	if quiet && verbose {
+		return fmt.Errorf("--quiet and --verbose cannot be used together")
+	}
+
+	switch outputFormat {
+	case "text":
+		reporter = &report.TextReporter{Out: os.Stdout, Quiet: quiet, Verbose: verbose}
+	case "json":
+		color.NoColor = true
+		reporter = &report.JSONReporter{Out: os.Stdout, Verbose: verbose}
+	default:
+		return fmt.Errorf("unknown output format '%s' (use text or json)", outputFormat)
+	}
+
+	return nil
+}
+
+func jsonOutput() bool {
+	return outputFormat == "json"
+}
+
+// textOutput reports whether decorative text output should be printed.
+func textOutput() bool {
+	return !jsonOutput() && !quiet
+}
+
+func emit(level report.Level, name, icon string, fields report.Fields, format string, args ...interface{}) {
+	reporter.Report(report.Event{
+		Name:    name,
+		Level:   level,
+		Message: fmt.Sprintf(format, args...),
+		Fields:  fields,
+		Icon:    icon,
+	})
+}
+
+func hint(format string, args ...interface{}) {
+	emit(report.LevelInfo, "hint", "💡", nil, format, args...)
+}
+
+// fail reports err and exits with status 1.
+func fail(err error) {
+	reporter.Report(report.Event{Name: "error", Level: report.LevelError, Message: err.Error(), Icon: "Error:"})
+	os.Exit(1)
+}
+
+// failUsage reports a usage error together with the expected invocation.
+func failUsage(err error, usage string) {
+	reporter.Report(report.Event{Name: "error", Level: report.LevelError, Message: err.Error(), Icon: "Error:"})
+	emit(report.LevelWarning, "usage", "", nil, "Usage: %s", usage)
+	os.Exit(1)
+}
+
+// printJSON writes a command's result as a single JSON line.
+func printJSON(v interface{}) {
+	if err := json.NewEncoder(os.Stdout).Encode(v); err != nil {
+		fail(err)
+	}
+}
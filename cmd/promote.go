@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/crabest/envguard/internal/validator"
+	"github.com/spf13/cobra"
+)
+
+var promoteCmd = &cobra.Command{
+	Use:   "promote <src> <dst>",
+	Short: "Promote selected keys from one environment to another",
+	Long: `Copy the keys matching --keys from one stored environment into another.
+A masked preview of added and changed keys is shown before anything is written.
+
+Keys annotated with "# @env-specific" in .env.example (for example
+DATABASE_URL) are never overwritten in the target environment.
+
+Examples:
+  envguard promote staging production --keys FEATURE_*
+  envguard promote staging production --keys FEATURE_CHECKOUT,LOG_LEVEL --yes`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		keys, _ := cmd.Flags().GetStringSlice("keys")
+		if len(keys) == 0 {
+			failUsage(fmt.Errorf("at least one key pattern is required"), "envguard promote <src> <dst> --keys FEATURE_*")
+		}
+
+		example, _ := cmd.Flags().GetString("example")
+
+		schema, err := validator.LoadSchema(example)
+		if err != nil {
+			fail(fmt.Errorf("failed to parse %s: %w", example, err))
+		}
+
+		manager, err := newManager()
+		if err != nil {
+			fail(err)
+		}
+
+		// Auto-sync .env changes so the preview reflects the latest values
+		if err := manager.AutoSync(); err != nil {
+			fail(err)
+		}
+
+		promotion, err := manager.PlanPromotion(args[0], args[1], keys, schema.IsEnvSpecific)
+		if err != nil {
+			fail(err)
+		}
+
+		if err := manager.PromoteKeys(promotion, true); err != nil {
+			fail(err)
+		}
+	},
+}
+
+func init() {
+	promoteCmd.Flags().StringSlice("keys", nil, "Keys to promote (comma-separated, patterns allowed)")
+	promoteCmd.Flags().StringP("example", "x", ".env.example", "Path to the .env.example file with key annotations")
+	promoteCmd.MarkFlagRequired("keys")
+	rootCmd.AddCommand(promoteCmd)
+}
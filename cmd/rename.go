@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var renameCmd = &cobra.Command{
+	Use:   "rename <old> <new>",
+	Short: "Rename an environment",
+	Long: `Rename an environment file in the .envguard/ directory.
+If the renamed environment is active, .envguard/.active is updated too.
+
+Examples:
+  envguard rename stage staging
+  envguard rename prod production`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		manager, err := newManager()
+		if err != nil {
+			fail(err)
+		}
+
+		// Auto-sync .env changes so they follow the environment to its new name
+		if err := manager.AutoSync(); err != nil {
+			fail(err)
+		}
+
+		if err := manager.RenameEnvironment(args[0], args[1]); err != nil {
+			fail(err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(renameCmd)
+}
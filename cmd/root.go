@@ -3,9 +3,12 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/crabest/envguard/internal/envmanager"
 	"github.com/crabest/envguard/internal/parser"
+	"github.com/crabest/envguard/internal/report"
 	"github.com/crabest/envguard/internal/validator"
 
 	"github.com/fatih/color"
@@ -13,8 +16,14 @@ import (
 )
 
 var (
-	envFile     string
-	exampleFile string
+	envFile          string
+	exampleFile      string
+	confirmProtected string
+	noSync           bool
+	assumeYes        bool
+	assumeNo         bool
+	interpolation    string
+	validateAs       string
 )
 
 var rootCmd = &cobra.Command{
@@ -28,6 +37,7 @@ Features:
 • Manage multiple environments (use, create, list, delete)
 • Colored output with detailed summaries
 • Environment-specific configuration management
+• Explicit sync between .env and the active environment
 
 Examples:
   envguard                        # Validate current .env against .env.example
@@ -36,8 +46,7 @@ Examples:
   envguard list                   # List all available environments`,
 	Run: func(cmd *cobra.Command, args []string) {
 		if err := runValidation(); err != nil {
-			color.Red("Error: %v", err)
-			os.Exit(1)
+			fail(err)
 		}
 	},
 }
@@ -45,39 +54,132 @@ Examples:
 func init() {
 	rootCmd.Flags().StringVarP(&envFile, "env", "e", ".env", "Path to the .env file")
 	rootCmd.Flags().StringVarP(&exampleFile, "example", "x", ".env.example", "Path to the .env.example file")
+	rootCmd.Flags().StringVar(&interpolation, "interpolation", string(parser.InterpolateGodotenv), "How ${VAR} references are resolved: none, godotenv or compose")
+	rootCmd.Flags().StringVar(&validateAs, "as", "", "Validate as this environment for @required-in rules (default: the active environment, then $ENVGUARD_ENV)")
+	rootCmd.PersistentFlags().BoolVarP(&assumeYes, "yes", "y", false, "Answer yes to all confirmation prompts")
+	rootCmd.PersistentFlags().BoolVar(&assumeNo, "no", false, "Answer no to all confirmation prompts")
+	rootCmd.PersistentFlags().BoolVar(&noSync, "no-sync", false, "Do not save .env edits into the active environment before switching")
+	rootCmd.PersistentFlags().StringVar(&confirmProtected, "confirm-protected", "", "Confirm operations on this protected environment without prompting")
 }
 
 func Execute() error {
 	return rootCmd.Execute()
 }
 
-func runValidation() error {
-	// Auto-sync .env changes to active environment before validation
+// newManager creates an environment manager configured from the global flags.
+func newManager() (*envmanager.Manager, error) {
 	manager, err := envmanager.NewManager()
-	if err == nil {
-		// Ignore sync errors for now, validation should still proceed
-		manager.SyncActiveEnvironment()
+	if err != nil {
+		return nil, err
+	}
+
+	manager.SetReporter(reporter)
+
+	if confirmProtected != "" {
+		manager.ConfirmProtected(confirmProtected)
+	}
+
+	if noSync {
+		manager.DisableImplicitSync()
+	}
+
+	switch {
+	case assumeYes && assumeNo:
+		return nil, fmt.Errorf("--yes and --no cannot be used together")
+	case assumeYes:
+		manager.SetPrompter(envmanager.AnswerPrompter{Answer: true})
+	case assumeNo:
+		manager.SetPrompter(envmanager.AnswerPrompter{Answer: false})
 	}
 
-	color.Cyan("🔍 EnvGuard - Environment File Validator\n")
+	return manager, nil
+}
+
+func runValidation() error {
+	if textOutput() {
+		color.Cyan("🔍 EnvGuard - Environment File Validator\n")
+	}
 
-	envVars, err := parser.ParseEnvFile(envFile)
+	mode, err := parser.ParseInterpolation(interpolation)
 	if err != nil {
-		return fmt.Errorf("failed to parse %s: %w", envFile, err)
+		return err
 	}
 
-	exampleVars, err := parser.ParseEnvFile(exampleFile)
+	result, _, err := validator.ValidateFile(validator.FileCheck{
+		EnvFile:       envFile,
+		ExampleFile:   exampleFile,
+		Interpolation: mode,
+		Environment:   validationEnvironment(),
+	})
 	if err != nil {
-		return fmt.Errorf("failed to parse %s: %w", exampleFile, err)
+		return err
 	}
 
-	result := validator.ValidateEnvFiles(envVars, exampleVars)
+	switch {
+	case jsonOutput():
+		printJSON(result)
+	case quiet:
+		reportProblems(result)
+	default:
+		validator.PrintResults(result, envFile, exampleFile)
+	}
 
-	validator.PrintResults(result, envFile, exampleFile)
+	// A broken rule can make keys look missing, so it is named first
+	for _, issue := range result.Issues {
+		if issue.Code == validator.CodeInvalidRule {
+			return fmt.Errorf("validation failed: invalid rule at %s", issue)
+		}
+	}
 
 	if len(result.MissingVars) > 0 {
 		return fmt.Errorf("validation failed: %d missing variables", len(result.MissingVars))
 	}
 
+	if validator.HasErrors(result.Issues) {
+		return fmt.Errorf("validation failed: invalid values or variable references")
+	}
+
 	return nil
 }
+
+// reportProblems sends what --quiet still has to show, the missing keys
+// and the error-level issues, through the reporter.
+func reportProblems(result validator.ValidationResult) {
+	for _, name := range result.MissingVars {
+		fields := report.Fields{"key": name, "file": envFile}
+		if reason, ok := result.Reasons[name]; ok {
+			fields["reason"] = reason
+			emit(report.LevelError, "validation.missing", "❌", fields, "%s is missing from %s (%s)", name, envFile, reason)
+			continue
+		}
+		emit(report.LevelError, "validation.missing", "❌", fields, "%s is missing from %s", name, envFile)
+	}
+	for _, issue := range result.Issues {
+		if issue.Severity != validator.SeverityError {
+			continue
+		}
+		emit(report.LevelError, "validation.issue", "❌", report.Fields{"code": issue.Code, "file": issue.File, "line": issue.Line}, "%s", issue)
+	}
+}
+
+// validationEnvironment picks the environment @required-in rules are
+// checked against: --as, the environment stored at the --env path, the
+// active environment when validating the root .env, or $ENVGUARD_ENV.
+func validationEnvironment() string {
+	if validateAs != "" {
+		return validateAs
+	}
+
+	if filepath.Dir(envFile) == envmanager.EnvGuardDir && filepath.Ext(envFile) == ".env" {
+		return strings.TrimSuffix(filepath.Base(envFile), ".env")
+	}
+
+	if filepath.Clean(envFile) == ".env" {
+		if manager, err := envmanager.NewManager(); err == nil {
+			if active, err := manager.GetActiveEnvironment(); err == nil {
+				return active
+			}
+		}
+	}
+	return os.Getenv(envmanager.EnvironmentEnv)
+}
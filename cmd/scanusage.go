@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/crabest/envguard/internal/parser"
+	"github.com/crabest/envguard/internal/report"
+	"github.com/crabest/envguard/internal/usage"
+	"github.com/crabest/envguard/internal/validator"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var scanUsageCmd = &cobra.Command{
+	Use:   "scan-usage [dir...]",
+	Short: "Find variables the code never reads or reads without declaring",
+	Long: `Scan source code for environment variable reads and compare them with
+.env.example. Reports keys declared but never read, and keys read but
+not declared.
+
+Recognized reads:
+  Go          os.Getenv("X"), os.LookupEnv("X"), env:"X" struct tags
+  JS/TS       process.env.X, process.env["X"], import.meta.env.X,
+              const { X } = process.env
+  Python      os.environ["X"], os.environ.get("X"), os.getenv("X")
+  Ruby        ENV["X"], ENV.fetch("X")
+
+Reads with a computed name, e.g. os.Getenv(name), cannot be found.
+node_modules, vendor and build output directories are skipped.
+
+Examples:
+  envguard scan-usage ./src
+  envguard scan-usage ./cmd ./internal --ignore HOSTNAME,GOFLAGS
+  envguard scan-usage . --strict    # exit 1 on any finding`,
+	Run: func(cmd *cobra.Command, args []string) {
+		example, _ := cmd.Flags().GetString("example")
+		ignore, _ := cmd.Flags().GetStringSlice("ignore")
+		strict, _ := cmd.Flags().GetBool("strict")
+
+		roots := args
+		if len(roots) == 0 {
+			roots = []string{"."}
+		}
+
+		exampleEntries, err := parser.ParseEnvFileEntries(example)
+		if err != nil {
+			fail(fmt.Errorf("failed to parse %s: %w", example, err))
+		}
+
+		result, err := usage.Scan(roots)
+		if err != nil {
+			fail(fmt.Errorf("failed to scan: %w", err))
+		}
+
+		issues := validator.CheckUsage(example, exampleEntries, result.Reads, ignore)
+
+		files := 0
+		for _, count := range result.Files {
+			files += count
+		}
+
+		if jsonOutput() {
+			printJSON(map[string]interface{}{"files": result.Files, "used": result.Keys(), "reads": result.Reads, "issues": issues})
+		} else {
+			if textOutput() {
+				color.Cyan("🔎 Scanned %d files in %s (%s)", files, strings.Join(roots, ", "), describeLanguages(result.Files))
+				fmt.Printf("   %d variables read, %d declared in %s\n\n", len(result.Keys()), len(parser.EntryKeys(exampleEntries)), example)
+			}
+			validator.PrintIssues(issues)
+		}
+
+		if len(issues) == 0 {
+			if jsonOutput() {
+				return
+			}
+			emit(report.LevelSuccess, "scan-usage.ok", "✅", report.Fields{"files": files},
+				"Every declared variable is read and every read variable is declared")
+			return
+		}
+
+		if strict {
+			fail(fmt.Errorf("scan-usage failed: %d findings", len(issues)))
+		}
+	},
+}
+
+// describeLanguages renders file counts such as "go 12, typescript 30".
+func describeLanguages(files map[string]int) string {
+	if len(files) == 0 {
+		return "no supported files"
+	}
+
+	languages := make([]string, 0, len(files))
+	for language := range files {
+		languages = append(languages, language)
+	}
+	sort.Strings(languages)
+
+	parts := make([]string, 0, len(languages))
+	for _, language := range languages {
+		parts = append(parts, fmt.Sprintf("%s %d", language, files[language]))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func init() {
+	scanUsageCmd.Flags().StringP("example", "x", ".env.example", "Path to the .env.example file")
+	scanUsageCmd.Flags().StringSlice("ignore", nil, "Variables never reported, e.g. set by the platform")
+	scanUsageCmd.Flags().Bool("strict", false, "Exit with status 1 when anything is reported")
+	rootCmd.AddCommand(scanUsageCmd)
+}
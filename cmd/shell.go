@@ -0,0 +1,173 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/crabest/envguard/internal/report"
+	"github.com/crabest/envguard/internal/shell"
+	"github.com/crabest/envguard/internal/trust"
+
+	"github.com/spf13/cobra"
+)
+
+var promptCmd = &cobra.Command{
+	Use:   "prompt",
+	Short: "Print the active environment for a shell prompt",
+	Long: `Print the active environment for use in a shell prompt. Nothing is
+printed outside a project or without an active environment. The command
+never syncs, creates files or fails, and reuses what the shell hook
+recorded while .envguard/.active is unchanged.
+
+Format verbs:
+  %e  active environment
+  %p  project directory name
+  %w  "⚠ " for production or protected environments
+  %E  active environment, upper-cased for production or protected ones
+  %%  a literal %
+
+Examples:
+  PS1='$(envguard prompt --format "[%w%e] ")'"$PS1"     # bash
+  PROMPT='$(envguard prompt --format "%%w%%e ")'"$PROMPT"  # zsh, with prompt_subst`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		format, _ := cmd.Flags().GetString("format")
+
+		prompt, ok := shell.PromptFor(".", os.Getenv, shell.NeedsWarning(format))
+		if !ok {
+			return
+		}
+		fmt.Print(shell.FormatPrompt(format, prompt))
+	},
+}
+
+var hookCmd = &cobra.Command{
+	Use:   "hook <bash|zsh|fish>",
+	Short: "Print the shell hook loading the active environment on cd",
+	Long: `Print a shell hook that loads the variables of the project's .env into
+the shell when entering a project with an active environment, reloads
+them after "envguard use" or edits to .env, and restores the previous
+values on leaving. ENVGUARD_ENV holds the loaded environment.
+
+A project's .env is only loaded after "envguard allow", and again after
+any edit made outside envguard. PATH, IFS, BASH_ENV, ENV, PROMPT_COMMAND, LD_* and
+DYLD_* are skipped unless permitted with "envguard allow --permit".
+
+Install it in your shell's startup file:
+  bash   eval "$(envguard hook bash)"     # ~/.bashrc
+  zsh    eval "$(envguard hook zsh)"      # ~/.zshrc
+  fish   envguard hook fish | source      # ~/.config/fish/config.fish`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		name, err := shell.ParseShell(args[0])
+		if err != nil {
+			failUsage(err, "envguard hook <"+strings.Join(shell.Shells, "|")+">")
+		}
+
+		executable, err := os.Executable()
+		if err != nil {
+			executable = "envguard"
+		}
+
+		script, err := shell.Hook(name, executable)
+		if err != nil {
+			fail(err)
+		}
+		fmt.Print(script)
+	},
+}
+
+// hookEnvCmd is what the installed hook runs on every prompt. It prints
+// shell code on stdout and messages on stderr, and never fails, so a
+// broken project cannot break the prompt.
+var hookEnvCmd = &cobra.Command{
+	Use:    "hook-env <bash|zsh|fish>",
+	Short:  "Print shell code updating the loaded environment",
+	Hidden: true,
+	Args:   cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		name, err := shell.ParseShell(args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "envguard: %v\n", err)
+			return
+		}
+
+		update, err := shell.Diff(name, ".", os.LookupEnv)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "envguard: %v\n", err)
+			return
+		}
+		fmt.Print(update.Script)
+
+		if update.Blocked != "" {
+			fmt.Fprintf(os.Stderr, "envguard: %s is not allowed; review it and run 'envguard allow' to load it\n", filepath.Join(update.Blocked, ".env"))
+		}
+		if update.Unloaded != "" {
+			fmt.Fprintf(os.Stderr, "envguard: unloaded %s\n", update.Unloaded)
+		}
+		if update.Loaded != "" {
+			fmt.Fprintf(os.Stderr, "envguard: loaded %s (%d variables)\n", update.Loaded, update.Count)
+			if shell.IsProductionLike(update.Dir, update.Loaded) {
+				fmt.Fprintf(os.Stderr, "envguard: ⚠️  %s variables are now set in this shell\n", update.Loaded)
+			}
+		}
+		if len(update.Skipped) > 0 {
+			fmt.Fprintf(os.Stderr, "envguard: skipped %s; permit them with 'envguard allow --permit %s'\n",
+				strings.Join(update.Skipped, ", "), strings.Join(update.Skipped, ","))
+		}
+	},
+}
+
+var allowCmd = &cobra.Command{
+	Use:   "allow",
+	Short: "Let the shell hook load this project's .env",
+	Long: `Trust the current .env of the project so the shell hook loads it. The
+allowance is stored under your config directory with a hash of .env.
+Commands such as "envguard use" keep it current when they rewrite .env;
+any other edit needs a new "envguard allow" after reviewing the file.
+
+Names that change how the shell runs commands (PATH, IFS, BASH_ENV, ENV,
+PROMPT_COMMAND, LD_* and DYLD_*) are skipped unless listed in --permit.
+
+Examples:
+  envguard allow
+  envguard allow --permit PATH
+  envguard allow --revoke`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		permit, _ := cmd.Flags().GetStringSlice("permit")
+		revoke, _ := cmd.Flags().GetBool("revoke")
+
+		dir, ok := shell.FindProject(".")
+		if !ok {
+			fail(fmt.Errorf("not in an envguard project; run 'envguard init' first"))
+		}
+
+		if revoke {
+			if err := trust.Revoke(dir); err != nil {
+				fail(err)
+			}
+			emit(report.LevelSuccess, "allow.revoked", "✅", report.Fields{"dir": dir}, "The shell hook no longer loads %s", filepath.Join(dir, ".env"))
+			return
+		}
+
+		allowance, err := trust.Allow(dir, permit)
+		if err != nil {
+			fail(err)
+		}
+		emit(report.LevelSuccess, "allow.allowed", "✅", report.Fields{"dir": dir, "permit": allowance.Permit},
+			"The shell hook may load %s", filepath.Join(dir, ".env"))
+	},
+}
+
+func init() {
+	allowCmd.Flags().StringSlice("permit", nil, "Dangerous names .env may set, e.g. PATH")
+	allowCmd.Flags().Bool("revoke", false, "Stop loading this project's .env")
+	rootCmd.AddCommand(allowCmd)
+	promptCmd.Flags().StringP("format", "f", shell.DefaultPromptFormat, "Prompt format, e.g. '[%w%e] '")
+	rootCmd.AddCommand(promptCmd)
+	rootCmd.AddCommand(hookCmd)
+	rootCmd.AddCommand(hookEnvCmd)
+}
@@ -3,8 +3,10 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/crabest/envguard/internal/envmanager"
+	"github.com/crabest/envguard/internal/report"
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
@@ -14,44 +16,93 @@ var statusCmd = &cobra.Command{
 	Use:   "status",
 	Short: "Show the currently active environment",
 	Long: `Show the currently active environment by reading from .envguard/.active.
-This shows which environment was last activated using 'envguard use <env>'.
+This shows which environment was last activated using 'envguard use <env>'
+and whether the root .env and the stored environment have drifted apart:
 
-Example:
-  envguard status`,
+  in sync         .env matches the stored environment
+  local edits     .env was edited since the last use or sync
+  store changed   the stored environment changed underneath .env
+  diverged        both changed
+
+Status never syncs, so it reports drift without changing it.
+
+Examples:
+  envguard status
+  envguard status --json`,
 	Run: func(cmd *cobra.Command, args []string) {
-		manager, err := envmanager.NewManager()
+		asJSON, _ := cmd.Flags().GetBool("json")
+		asJSON = asJSON || jsonOutput()
+
+		manager, err := newManager()
 		if err != nil {
-			color.Red("Error: %v", err)
-			os.Exit(1)
+			fail(err)
 		}
 
-		// Auto-sync .env changes to active environment
-		manager.SyncActiveEnvironment()
-
 		activeEnv, err := manager.GetActiveEnvironment()
 		if err != nil {
-			color.Yellow("⚠️  %v", err)
-			color.Blue("💡 Use 'envguard use <environment>' to set an active environment")
+			if asJSON {
+				printJSON(map[string]interface{}{"active": nil})
+				return
+			}
+			emit(report.LevelWarning, "status.no_active", "⚠️ ", nil, "%v", err)
+			hint("Use 'envguard use <environment>' to set an active environment")
 			return
 		}
 
 		// Check if the active environment file still exists
 		if !manager.EnvironmentExists(activeEnv) {
-			color.Red("❌ Active environment '%s' no longer exists", activeEnv)
-			color.Blue("💡 Available environments:")
-
-			environments, listErr := manager.ListEnvironments()
-			if listErr == nil {
-				for i, env := range environments {
-					fmt.Printf("   %d. %s\n", i+1, color.GreenString(env))
+			if asJSON {
+				printJSON(map[string]interface{}{"active": activeEnv, "exists": false})
+				os.Exit(1)
+			}
+			emit(report.LevelError, "status.missing_active", "❌", report.Fields{"environment": activeEnv}, "Active environment '%s' no longer exists", activeEnv)
+			if textOutput() {
+				hint("Available environments:")
+				environments, listErr := manager.ListEnvironments()
+				if listErr == nil {
+					for i, env := range environments {
+						fmt.Printf("   %d. %s\n", i+1, color.GreenString(env))
+					}
 				}
+				hint("Use 'envguard use <environment>' to set a new active environment")
 			}
-			color.Blue("💡 Use 'envguard use <environment>' to set a new active environment")
 			return
 		}
 
+		drift, err := manager.DetectDrift()
+		if err != nil {
+			fail(err)
+		}
+
+		protected, err := manager.IsProtected(activeEnv)
+		if err != nil {
+			fail(err)
+		}
+
+		if asJSON {
+			printJSON(map[string]interface{}{
+				"active":      activeEnv,
+				"exists":      true,
+				"protected":   protected,
+				"state":       drift.State,
+				"local_keys":  drift.LocalKeys,
+				"store_keys":  drift.StoreKeys,
+				"description": drift.String(),
+			})
+			return
+		}
+
+		if quiet {
+			fmt.Println(activeEnv)
+			return
+		}
+
+		if protected {
+			color.New(color.FgRed, color.Bold).Printf("🔒 PROTECTED ENVIRONMENT: %s — changes affect a protected configuration\n\n", activeEnv)
+		}
+
 		color.Cyan("🌍 Environment Status:")
-		color.Cyan("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+		color.Cyan(report.Rule)
 
 		fmt.Printf("📍 Active Environment: %s\n", color.GreenString(activeEnv))
 		fmt.Printf("📁 Environment File: %s/%s.env\n",
@@ -59,10 +110,26 @@ Example:
 			color.BlueString(activeEnv))
 		fmt.Printf("🎯 Active .env: %s\n", color.BlueString(".env"))
 
+		switch drift.State {
+		case envmanager.DriftInSync:
+			fmt.Printf("🔄 Sync: %s\n", color.GreenString(drift.String()))
+		case envmanager.DriftDiverged:
+			fmt.Printf("🔄 Sync: %s\n", color.RedString(drift.String()))
+		default:
+			fmt.Printf("🔄 Sync: %s\n", color.YellowString(drift.String()))
+		}
+		if len(drift.LocalKeys) > 0 {
+			fmt.Printf("   ✎ .env: %s\n", strings.Join(drift.LocalKeys, ", "))
+		}
+		if len(drift.StoreKeys) > 0 {
+			fmt.Printf("   ✎ %s/%s.env: %s\n", ".envguard", activeEnv, strings.Join(drift.StoreKeys, ", "))
+		}
+
 		color.Green("✅ Environment '%s' is currently active", activeEnv)
 	},
 }
 
 func init() {
+	statusCmd.Flags().Bool("json", false, "Print status as JSON for prompts and scripts")
 	rootCmd.AddCommand(statusCmd)
 }
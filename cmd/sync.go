@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"github.com/crabest/envguard/internal/envmanager"
+
+	"github.com/spf13/cobra"
+)
+
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Sync .env with the active environment",
+	Long: `Sync the root .env with the active environment in .envguard/.
+A masked key-level diff is shown before anything is written.
+
+  push  save .env edits into .envguard/<active>.env (default)
+  pull  overwrite .env with .envguard/<active>.env
+
+Implicit sync before use, create, rename, copy and promote can be turned
+off with --no-sync or with "implicit_sync": false in .envguard/config.json.
+
+Examples:
+  envguard sync
+  envguard sync --dry-run
+  envguard sync --direction pull`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		directionFlag, _ := cmd.Flags().GetString("direction")
+
+		direction, err := envmanager.ParseSyncDirection(directionFlag)
+		if err != nil {
+			fail(err)
+		}
+
+		manager, err := newManager()
+		if err != nil {
+			fail(err)
+		}
+
+		plan, err := manager.PlanSync(direction)
+		if err != nil {
+			fail(err)
+		}
+
+		if err := manager.Sync(plan, dryRun); err != nil {
+			fail(err)
+		}
+	},
+}
+
+func init() {
+	syncCmd.Flags().Bool("dry-run", false, "Show the diff without writing")
+	syncCmd.Flags().String("direction", string(envmanager.SyncPush), "Sync direction: push (.env → store) or pull (store → .env)")
+	rootCmd.AddCommand(syncCmd)
+}
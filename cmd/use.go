@@ -1,11 +1,6 @@
 package cmd
 
 import (
-	"os"
-
-	"github.com/crabest/envguard/internal/envmanager"
-
-	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 )
 
@@ -26,18 +21,18 @@ Examples:
 	Run: func(cmd *cobra.Command, args []string) {
 		envName := args[0]
 
-		manager, err := envmanager.NewManager()
+		manager, err := newManager()
 		if err != nil {
-			color.Red("Error: %v", err)
-			os.Exit(1)
+			fail(err)
 		}
 
 		// Auto-sync current .env changes before switching
-		manager.SyncActiveEnvironment()
+		if err := manager.AutoSync(); err != nil {
+			fail(err)
+		}
 
 		if err := manager.UseEnvironment(envName); err != nil {
-			color.Red("Error: %v", err)
-			os.Exit(1)
+			fail(err)
 		}
 	},
 }
@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/crabest/envguard/internal/envmanager"
+	"github.com/crabest/envguard/internal/parser"
+	"github.com/crabest/envguard/internal/report"
+	"github.com/crabest/envguard/internal/watch"
+
+	"github.com/spf13/cobra"
+)
+
+var watchCmd = &cobra.Command{
+	Use:   "watch [-- command [args...]]",
+	Short: "Validate and sync on every change, optionally restarting a command",
+	Long: `Watch .env, .env.example and .envguard/ and, whenever they change:
+• save .env edits into the active environment, unless --no-sync is given
+  or implicit_sync is turned off in .envguard/config.json
+• validate .env against .env.example
+• restart the command given after --, when the variables in .env changed
+  and validation passed
+
+Changes are handled once the files have been quiet for --debounce, so an
+editor save or an "envguard use" is reported once. The command runs with
+the variables from .env on top of envguard's own environment; a failing
+validation keeps the previous run going. Stop watching with Ctrl-C.
+
+Examples:
+  envguard watch                   # validate and sync on every change
+  envguard watch -- npm run dev    # also restart the dev server`,
+	Args: cobra.ArbitraryArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		debounce, _ := cmd.Flags().GetDuration("debounce")
+
+		var command []string
+		if dash := cmd.ArgsLenAtDash(); dash >= 0 {
+			args, command = args[:dash], args[dash:]
+		}
+		if len(args) > 0 {
+			failUsage(fmt.Errorf("unexpected argument '%s'", args[0]), "envguard watch [-- command [args...]]")
+		}
+
+		manager, err := newManager()
+		if err != nil {
+			fail(err)
+		}
+		root, err := os.Getwd()
+		if err != nil {
+			fail(err)
+		}
+
+		session := &watchSession{manager: manager, root: root, fingerprint: watch.Fingerprint(root)}
+		if len(command) > 0 {
+			session.child = &watch.Child{
+				Args:   command,
+				Stdout: os.Stdout,
+				Stderr: os.Stderr,
+				OnExit: func(err error) {
+					if err != nil {
+						emit(report.LevelWarning, "watch.exited", "⏹️ ", report.Fields{"command": strings.Join(command, " ")},
+							"%s exited: %v", strings.Join(command, " "), err)
+					} else {
+						emit(report.LevelInfo, "watch.exited", "⏹️ ", report.Fields{"command": strings.Join(command, " ")},
+							"%s exited", strings.Join(command, " "))
+					}
+					hint("It is started again on the next change to .env")
+				},
+			}
+			defer session.child.Stop(watch.DefaultStopTimeout)
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		session.update(nil)
+		emit(report.LevelInfo, "watch.started", "👀", nil, "Watching .env, .env.example and %s/ (Ctrl-C to stop)", envmanager.EnvGuardDir)
+
+		if err := watch.Watch(ctx, root, debounce, session.update); err != nil {
+			if session.child != nil {
+				session.child.Stop(watch.DefaultStopTimeout)
+			}
+			fail(err)
+		}
+	},
+}
+
+// watchSession holds what watch compares each change against.
+type watchSession struct {
+	manager     *envmanager.Manager
+	root        string
+	child       *watch.Child
+	fingerprint string
+	vars        parser.EnvVars
+}
+
+// update syncs, validates and restarts the child after a batch of changed
+// paths; nil means the initial run.
+func (s *watchSession) update(changed []string) {
+	envChanged := changed == nil
+	if changed != nil {
+		// Batches caused by our own sync, or by edits to environments
+		// other than what .env holds, leave the fingerprint unchanged.
+		fingerprint := watch.Fingerprint(s.root)
+		if fingerprint == s.fingerprint {
+			return
+		}
+		s.fingerprint = fingerprint
+
+		names := make([]string, 0, len(changed))
+		for _, path := range changed {
+			name, err := filepath.Rel(s.root, path)
+			if err != nil {
+				name = path
+			}
+			names = append(names, name)
+			envChanged = envChanged || name == ".env"
+		}
+		emit(report.LevelInfo, "watch.changed", "🔄", report.Fields{"files": names}, "%s changed", strings.Join(names, ", "))
+	}
+
+	if envChanged {
+		if err := s.manager.AutoSync(); err != nil {
+			emit(report.LevelError, "watch.sync_failed", "❌", nil, "%v", err)
+		}
+	}
+
+	if err := runValidation(); err != nil {
+		emit(report.LevelError, "watch.invalid", "❌", nil, "%v", err)
+		if s.child != nil && s.child.Running() {
+			hint("Keeping %s running until validation passes", s.child)
+		}
+		return
+	}
+
+	if s.child == nil {
+		return
+	}
+
+	vars, err := parser.ParseEnvFile(envFile)
+	if err != nil {
+		emit(report.LevelError, "watch.invalid", "❌", nil, "failed to parse %s: %v", envFile, err)
+		return
+	}
+
+	running := s.child.Running()
+	if running && maps.Equal(vars, s.vars) {
+		return
+	}
+	s.vars = vars
+
+	verb := "Starting"
+	if running {
+		verb = "Restarting"
+	}
+	emit(report.LevelInfo, "watch.start", "▶️ ", report.Fields{"command": s.child.String()}, "%s %s", verb, s.child)
+	if err := s.child.Start(watch.Environ(os.Environ(), vars)); err != nil {
+		emit(report.LevelError, "watch.start_failed", "❌", report.Fields{"command": s.child.String()}, "failed to start %s: %v", s.child, err)
+	}
+}
+
+func init() {
+	watchCmd.Flags().Duration("debounce", watch.DefaultDebounce, "How long files must be quiet before changes are handled")
+	rootCmd.AddCommand(watchCmd)
+}
@@ -0,0 +1,140 @@
+package codegen
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/crabest/envguard/internal/validator"
+)
+
+// Options tunes the generated module.
+type Options struct {
+	// PackageName is the Go package of the generated file.
+	PackageName string
+	// TypeName is the name of the generated struct, interface or class.
+	TypeName string
+	// Source is mentioned in the generated header.
+	Source string
+}
+
+type generator func(schema *validator.Schema, opts Options) (string, error)
+
+var generators = map[string]generator{
+	"go":         generateGo,
+	"typescript": generateTypeScript,
+	"zod":        generateZod,
+	"python":     generatePython,
+}
+
+// Languages returns the supported --lang values.
+func Languages() []string {
+	langs := make([]string, 0, len(generators))
+	for lang := range generators {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// Generate renders a typed config module for schema in the given language.
+func Generate(lang string, schema *validator.Schema, opts Options) (string, error) {
+	gen, ok := generators[strings.ToLower(lang)]
+	if !ok {
+		return "", fmt.Errorf("unsupported language '%s' (use %s)", lang, strings.Join(Languages(), ", "))
+	}
+
+	if opts.PackageName == "" {
+		opts.PackageName = "config"
+	}
+	if opts.TypeName == "" {
+		opts.TypeName = "Config"
+	}
+	if opts.Source == "" {
+		opts.Source = ".env.example"
+	}
+
+	for _, key := range schema.Keys {
+		if t := schema.Type(key); !isKnownType(t) {
+			return "", fmt.Errorf("%s: unknown @type '%s' (use %s)", key, t, strings.Join(validator.ValueTypes, ", "))
+		}
+	}
+
+	return gen(schema, opts)
+}
+
+func isKnownType(t string) bool {
+	for _, known := range validator.ValueTypes {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+func header(comment string, opts Options) string {
+	return fmt.Sprintf("%s Code generated by envguard codegen from %s. DO NOT EDIT.\n", comment, opts.Source)
+}
+
+// initialisms are kept upper-case in Go identifiers.
+var initialisms = map[string]bool{
+	"API": true, "DB": true, "DNS": true, "HTTP": true, "HTTPS": true, "ID": true,
+	"IP": true, "JSON": true, "JWT": true, "SMTP": true, "SQL": true, "SSL": true,
+	"TLS": true, "TTL": true, "UI": true, "URI": true, "URL": true, "UUID": true,
+}
+
+func goFieldName(key string) string {
+	var b strings.Builder
+	for _, part := range strings.FieldsFunc(key, func(r rune) bool { return r == '_' || r == '-' || r == '.' }) {
+		upper := strings.ToUpper(part)
+		if initialisms[upper] {
+			b.WriteString(upper)
+			continue
+		}
+		b.WriteString(upper[:1] + strings.ToLower(part[1:]))
+	}
+
+	name := b.String()
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "X" + name
+	}
+	return name
+}
+
+// pythonKeywords are the reserved words of keyword.kwlist that a
+// lower-cased key can spell.
+var pythonKeywords = map[string]bool{
+	"and": true, "as": true, "assert": true, "async": true, "await": true, "break": true,
+	"class": true, "continue": true, "def": true, "del": true, "elif": true, "else": true,
+	"except": true, "finally": true, "for": true, "from": true, "global": true, "if": true,
+	"import": true, "in": true, "is": true, "lambda": true, "nonlocal": true, "not": true,
+	"or": true, "pass": true, "raise": true, "return": true, "try": true, "while": true,
+	"with": true, "yield": true,
+}
+
+func pythonFieldName(key string) string {
+	name := strings.ToLower(strings.NewReplacer("-", "_", ".", "_").Replace(key))
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "x_" + name
+	}
+	if pythonKeywords[name] {
+		name += "_"
+	}
+	return name
+}
+
+// fieldNames maps every key in schema to its identifier in the generated
+// code, failing when two keys would end up with the same one.
+func fieldNames(schema *validator.Schema, fieldName func(string) string, lang string) (map[string]string, error) {
+	names := make(map[string]string, len(schema.Keys))
+	owners := make(map[string]string, len(schema.Keys))
+	for _, key := range schema.Keys {
+		name := fieldName(key)
+		if other, taken := owners[name]; taken {
+			return nil, fmt.Errorf("%s and %s both become the %s field %s; rename one of them", other, key, lang, name)
+		}
+		owners[name] = key
+		names[key] = name
+	}
+	return names, nil
+}
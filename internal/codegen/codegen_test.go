@@ -0,0 +1,144 @@
+package codegen
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/crabest/envguard/internal/parser"
+	"github.com/crabest/envguard/internal/validator"
+)
+
+const exampleSchema = `# Public API base URL
+# @type url
+API_URL=https://api.example.com
+# @type int
+PORT=3000
+# @type bool
+DEBUG=false
+# @enum development,staging,production
+NODE_ENV=development
+# @type duration
+REQUEST_TIMEOUT=30s
+JWT_SECRET=change-me
+# @optional
+SENTRY_DSN=
+`
+
+func loadSchema(t *testing.T, content string) *validator.Schema {
+	t.Helper()
+	entries, err := parser.ParseEntries([]byte(content))
+	if err != nil {
+		t.Fatalf("Failed to parse entries: %v", err)
+	}
+	return validator.NewSchema(entries)
+}
+
+// assertContains checks for snippets ignoring differences in horizontal
+// whitespace, since gofmt aligns struct fields.
+func assertContains(t *testing.T, code string, snippets ...string) {
+	t.Helper()
+	normalize := func(s string) string {
+		lines := strings.Split(s, "\n")
+		for i, line := range lines {
+			lines[i] = strings.Join(strings.Fields(line), " ")
+		}
+		return strings.Join(lines, "\n")
+	}
+	for _, snippet := range snippets {
+		if !strings.Contains(normalize(code), normalize(snippet)) {
+			t.Errorf("Expected generated code to contain %q:\n%s", snippet, code)
+		}
+	}
+}
+
+func TestGenerateGo(t *testing.T) {
+	code, err := Generate("go", loadSchema(t, exampleSchema), Options{PackageName: "settings"})
+	if err != nil {
+		t.Fatalf("Failed to generate: %v", err)
+	}
+
+	assertContains(t, code,
+		"package settings",
+		`"net/url"`,
+		`"time"`,
+		"// Public API base URL",
+		"APIURL url.URL `env:\"API_URL,required\"`",
+		"Port int `env:\"PORT,required\"`",
+		"RequestTimeout time.Duration",
+		"JWTSecret string",
+		"SentryDsn string `env:\"SENTRY_DSN\"`",
+		"func Load(opts envguard.Options) (*Config, error)",
+	)
+}
+
+func TestGenerateTypeScript(t *testing.T) {
+	schema := loadSchema(t, exampleSchema)
+
+	code, err := Generate("typescript", schema, Options{})
+	if err != nil {
+		t.Fatalf("Failed to generate: %v", err)
+	}
+	assertContains(t, code,
+		"interface ProcessEnv",
+		`"NODE_ENV": "development" | "staging" | "production";`,
+		`"PORT": string;`,
+		`"SENTRY_DSN"?: string;`,
+		"/** Public API base URL */",
+	)
+
+	code, err = Generate("zod", schema, Options{})
+	if err != nil {
+		t.Fatalf("Failed to generate: %v", err)
+	}
+	assertContains(t, code,
+		`"PORT": z.coerce.number().int(),`,
+		`"API_URL": z.string().url(),`,
+		`"SENTRY_DSN": z.string().optional(),`,
+		`"NODE_ENV": z.enum(["development", "staging", "production"]),`,
+		"export type Config = z.infer<typeof envSchema>;",
+	)
+}
+
+func TestGeneratePython(t *testing.T) {
+	code, err := Generate("python", loadSchema(t, exampleSchema), Options{TypeName: "Settings"})
+	if err != nil {
+		t.Fatalf("Failed to generate: %v", err)
+	}
+	assertContains(t, code,
+		"class Settings:",
+		"    port: int",
+		`            port=int(environ["PORT"]),`,
+		`            debug=_parse_bool(environ["DEBUG"]),`,
+		"    sentry_dsn: Optional[str]",
+		`            sentry_dsn=environ["SENTRY_DSN"] if "SENTRY_DSN" in environ else None,`,
+	)
+}
+
+func TestGenerateErrors(t *testing.T) {
+	if _, err := Generate("cobol", loadSchema(t, exampleSchema), Options{}); err == nil {
+		t.Error("Expected error for unsupported language")
+	}
+	if _, err := Generate("go", loadSchema(t, "# @type money\nPRICE=1\n"), Options{}); err == nil {
+		t.Error("Expected error for unknown @type")
+	}
+
+	_, err := Generate("go", loadSchema(t, "API_KEY=\nAPI__KEY=\n"), Options{})
+	if err == nil || !strings.Contains(err.Error(), "API_KEY and API__KEY") {
+		t.Errorf("Expected a field name collision naming both keys, got %v", err)
+	}
+	if _, err := Generate("python", loadSchema(t, "LOG_LEVEL=\nlog_level=\n"), Options{}); err == nil {
+		t.Error("Expected a Python field name collision")
+	}
+}
+
+func TestGeneratePythonKeywords(t *testing.T) {
+	code, err := Generate("python", loadSchema(t, "CLASS=\nFROM=\nIMPORT_PATH=\n"), Options{})
+	if err != nil {
+		t.Fatalf("Failed to generate: %v", err)
+	}
+	assertContains(t, code,
+		"    class_: str",
+		`            from_=environ["FROM"],`,
+		"    import_path: str",
+	)
+}
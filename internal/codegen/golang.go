@@ -0,0 +1,80 @@
+package codegen
+
+import (
+	"fmt"
+	"go/format"
+	"strings"
+
+	"github.com/crabest/envguard/internal/validator"
+)
+
+var goTypes = map[string]string{
+	validator.TypeString:   "string",
+	validator.TypeInt:      "int",
+	validator.TypeFloat:    "float64",
+	validator.TypeBool:     "bool",
+	validator.TypeURL:      "url.URL",
+	validator.TypeDuration: "time.Duration",
+	validator.TypeList:     "[]string",
+}
+
+func generateGo(schema *validator.Schema, opts Options) (string, error) {
+	names, err := fieldNames(schema, goFieldName, "Go")
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+
+	b.WriteString(header("//", opts))
+	fmt.Fprintf(&b, "\npackage %s\n\n", opts.PackageName)
+
+	imports := []string{`"github.com/crabest/envguard/pkg/envguard"`}
+	for _, key := range schema.Keys {
+		switch schema.Type(key) {
+		case validator.TypeURL:
+			imports = appendOnce(imports, `"net/url"`)
+		case validator.TypeDuration:
+			imports = appendOnce(imports, `"time"`)
+		}
+	}
+	fmt.Fprintf(&b, "import (\n\t%s\n)\n\n", strings.Join(imports, "\n\t"))
+
+	fmt.Fprintf(&b, "// %s holds the variables declared in %s.\n", opts.TypeName, opts.Source)
+	fmt.Fprintf(&b, "type %s struct {\n", opts.TypeName)
+	for _, key := range schema.Keys {
+		spec := schema.Specs[key]
+		for _, line := range spec.Description {
+			fmt.Fprintf(&b, "\t// %s\n", line)
+		}
+		if values := schema.Enum(key); len(values) > 0 {
+			fmt.Fprintf(&b, "\t// One of: %s\n", strings.Join(values, ", "))
+		}
+		tag := key + ",required"
+		if schema.Optional(key) {
+			tag = key
+		}
+		fmt.Fprintf(&b, "\t%s %s `env:\"%s\"`\n", names[key], goTypes[schema.Type(key)], tag)
+	}
+	b.WriteString("}\n\n")
+
+	fmt.Fprintf(&b, "// Load reads and validates the environment and binds it into a %s.\n", opts.TypeName)
+	fmt.Fprintf(&b, "func Load(opts envguard.Options) (*%s, error) {\n", opts.TypeName)
+	fmt.Fprintf(&b, "\tvar cfg %s\n", opts.TypeName)
+	b.WriteString("\tif err := envguard.LoadInto(opts, &cfg); err != nil {\n\t\treturn nil, err\n\t}\n\treturn &cfg, nil\n}\n")
+
+	formatted, err := format.Source([]byte(b.String()))
+	if err != nil {
+		return "", fmt.Errorf("failed to format generated Go code: %w", err)
+	}
+	return string(formatted), nil
+}
+
+func appendOnce(list []string, value string) []string {
+	for _, existing := range list {
+		if existing == value {
+			return list
+		}
+	}
+	return append(list, value)
+}
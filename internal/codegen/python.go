@@ -0,0 +1,86 @@
+package codegen
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/crabest/envguard/internal/validator"
+)
+
+var pythonTypes = map[string]string{
+	validator.TypeString:   "str",
+	validator.TypeInt:      "int",
+	validator.TypeFloat:    "float",
+	validator.TypeBool:     "bool",
+	validator.TypeURL:      "str",
+	validator.TypeDuration: "str",
+	validator.TypeList:     "List[str]",
+}
+
+var pythonParsers = map[string]string{
+	validator.TypeString:   "%s",
+	validator.TypeInt:      "int(%s)",
+	validator.TypeFloat:    "float(%s)",
+	validator.TypeBool:     "_parse_bool(%s)",
+	validator.TypeURL:      "%s",
+	validator.TypeDuration: "%s",
+	validator.TypeList:     "[item.strip() for item in %s.split(\",\")]",
+}
+
+// generatePython emits a frozen dataclass with a from_env constructor.
+func generatePython(schema *validator.Schema, opts Options) (string, error) {
+	names, err := fieldNames(schema, pythonFieldName, "Python")
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+
+	b.WriteString(header("#", opts))
+	b.WriteString("\nimport os\nfrom dataclasses import dataclass\nfrom typing import List, Mapping, Optional\n\n\n")
+	b.WriteString("def _parse_bool(value: str) -> bool:\n")
+	b.WriteString("    if value.lower() in (\"1\", \"true\", \"yes\", \"on\"):\n        return True\n")
+	b.WriteString("    if value.lower() in (\"0\", \"false\", \"no\", \"off\"):\n        return False\n")
+	b.WriteString("    raise ValueError(f\"invalid boolean {value!r}\")\n\n\n")
+
+	b.WriteString("@dataclass(frozen=True)\n")
+	fmt.Fprintf(&b, "class %s:\n", opts.TypeName)
+	fmt.Fprintf(&b, "    \"\"\"Variables declared in %s.\"\"\"\n\n", opts.Source)
+	for _, key := range schema.Keys {
+		for _, line := range schema.Specs[key].Description {
+			fmt.Fprintf(&b, "    # %s\n", line)
+		}
+		pyType := pythonTypes[schema.Type(key)]
+		if schema.Optional(key) {
+			pyType = "Optional[" + pyType + "]"
+		}
+		fmt.Fprintf(&b, "    %s: %s\n", names[key], pyType)
+	}
+
+	b.WriteString("\n    @classmethod\n")
+	fmt.Fprintf(&b, "    def from_env(cls, environ: Optional[Mapping[str, str]] = None) -> \"%s\":\n", opts.TypeName)
+	b.WriteString("        environ = os.environ if environ is None else environ\n")
+	b.WriteString("        missing = [key for key in _REQUIRED if key not in environ]\n")
+	b.WriteString("        if missing:\n")
+	b.WriteString("            raise KeyError(\"missing environment variables: \" + \", \".join(missing))\n")
+	b.WriteString("        return cls(\n")
+	for _, key := range schema.Keys {
+		value := fmt.Sprintf(pythonParsers[schema.Type(key)], fmt.Sprintf("environ[%s]", strconv.Quote(key)))
+		if schema.Optional(key) {
+			value = fmt.Sprintf("%s if %s in environ else None", value, strconv.Quote(key))
+		}
+		fmt.Fprintf(&b, "            %s=%s,\n", names[key], value)
+	}
+	b.WriteString("        )\n\n\n")
+
+	quoted := []string{}
+	for _, key := range schema.Keys {
+		if !schema.Optional(key) {
+			quoted = append(quoted, strconv.Quote(key))
+		}
+	}
+	fmt.Fprintf(&b, "_REQUIRED = (%s)\n", strings.Join(append(quoted, ""), ", "))
+
+	return b.String(), nil
+}
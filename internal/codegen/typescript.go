@@ -0,0 +1,100 @@
+package codegen
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/crabest/envguard/internal/validator"
+)
+
+// generateTypeScript emits an env.d.ts that types process.env. Values in
+// process.env are always strings, so only @enum narrows the type.
+func generateTypeScript(schema *validator.Schema, opts Options) (string, error) {
+	var b strings.Builder
+
+	b.WriteString(header("//", opts))
+	b.WriteString("\ndeclare global {\n  namespace NodeJS {\n    interface ProcessEnv {\n")
+	for _, key := range schema.Keys {
+		writeJSDoc(&b, "      ", schema, key)
+		fmt.Fprintf(&b, "      %s%s: %s;\n", strconv.Quote(key), tsOptional(schema, key), tsStringType(schema, key))
+	}
+	b.WriteString("    }\n  }\n}\n\nexport {};\n")
+
+	return b.String(), nil
+}
+
+// generateZod emits a zod schema that parses process.env into typed values.
+func generateZod(schema *validator.Schema, opts Options) (string, error) {
+	var b strings.Builder
+
+	b.WriteString(header("//", opts))
+	b.WriteString("\nimport { z } from \"zod\";\n\n")
+	b.WriteString("export const envSchema = z.object({\n")
+	for _, key := range schema.Keys {
+		writeJSDoc(&b, "  ", schema, key)
+		zod := zodType(schema, key)
+		if schema.Optional(key) {
+			zod += ".optional()"
+		}
+		fmt.Fprintf(&b, "  %s: %s,\n", strconv.Quote(key), zod)
+	}
+	b.WriteString("});\n\n")
+	fmt.Fprintf(&b, "export type %s = z.infer<typeof envSchema>;\n\n", opts.TypeName)
+	fmt.Fprintf(&b, "export const env: %s = envSchema.parse(process.env);\n", opts.TypeName)
+
+	return b.String(), nil
+}
+
+func writeJSDoc(b *strings.Builder, indent string, schema *validator.Schema, key string) {
+	description := schema.Specs[key].Description
+	if len(description) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "%s/** %s */\n", indent, strings.Join(description, " "))
+}
+
+func tsOptional(schema *validator.Schema, key string) string {
+	if schema.Optional(key) {
+		return "?"
+	}
+	return ""
+}
+
+func tsStringType(schema *validator.Schema, key string) string {
+	values := schema.Enum(key)
+	if len(values) == 0 {
+		return "string"
+	}
+
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = strconv.Quote(value)
+	}
+	return strings.Join(quoted, " | ")
+}
+
+func zodType(schema *validator.Schema, key string) string {
+	if values := schema.Enum(key); len(values) > 0 {
+		quoted := make([]string, len(values))
+		for i, value := range values {
+			quoted[i] = strconv.Quote(value)
+		}
+		return fmt.Sprintf("z.enum([%s])", strings.Join(quoted, ", "))
+	}
+
+	switch schema.Type(key) {
+	case validator.TypeInt:
+		return "z.coerce.number().int()"
+	case validator.TypeFloat:
+		return "z.coerce.number()"
+	case validator.TypeBool:
+		return `z.enum(["true", "false"]).transform((v) => v === "true")`
+	case validator.TypeURL:
+		return "z.string().url()"
+	case validator.TypeList:
+		return `z.string().transform((v) => v.split(",").map((s) => s.trim()))`
+	default:
+		return "z.string()"
+	}
+}
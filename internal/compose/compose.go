@@ -0,0 +1,343 @@
+// Package compose reads the variables a docker compose file depends on:
+// ${VAR} interpolations anywhere in the file, variables passed through to
+// containers by name in environment blocks, and env_file declarations.
+package compose
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/crabest/envguard/internal/parser"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultFiles are the compose file names looked up when none is given,
+// in the order docker compose prefers them.
+var DefaultFiles = []string{"compose.yaml", "compose.yml", "docker-compose.yml", "docker-compose.yaml"}
+
+// Usage is a single place where the compose file reads a variable.
+type Usage struct {
+	Name string
+	// Service is the service the usage belongs to, empty for top-level
+	// sections such as volumes or networks.
+	Service string
+	// Field is the dotted path of the value, e.g. "services.api.image".
+	Field string
+	Line  int
+	// Reference is the ${VAR} expression; it is zero for pass-through
+	// variables.
+	Reference parser.Reference
+	// Passthrough is set for "- VAR" or "VAR:" environment entries, which
+	// copy VAR from the environment compose runs in.
+	Passthrough bool
+}
+
+// HasFallback reports whether compose has a value for the usage when the
+// variable is unset.
+func (u Usage) HasFallback() bool {
+	return !u.Passthrough && u.Reference.HasFallback()
+}
+
+// EnvFile is an env_file entry of a service.
+type EnvFile struct {
+	Service string
+	// Path is relative to the compose file unless absolute.
+	Path     string
+	Required bool
+	Line     int
+}
+
+// File is a parsed compose file.
+type File struct {
+	Path     string
+	Services []string
+	Usages   []Usage
+	EnvFiles []EnvFile
+}
+
+// Find returns the first of DefaultFiles that exists in dir.
+func Find(dir string) (string, error) {
+	for _, name := range DefaultFiles {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("no compose file found (looked for %s)", strings.Join(DefaultFiles, ", "))
+}
+
+// Load reads and parses the compose file at path.
+func Load(path string) (*File, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := Parse(content)
+	if err != nil {
+		return nil, err
+	}
+	file.Path = path
+	return file, nil
+}
+
+// Parse extracts services, variable usages and env_file entries from the
+// content of a compose file.
+func Parse(content []byte) (*File, error) {
+	var document yaml.Node
+	if err := yaml.Unmarshal(content, &document); err != nil {
+		return nil, err
+	}
+
+	file := &File{}
+	if document.Kind == 0 || len(document.Content) == 0 {
+		return file, nil
+	}
+
+	root := document.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("line %d: a compose file must be a mapping", root.Line)
+	}
+
+	for _, field := range fields(root) {
+		if field.key != "services" {
+			file.walk(field.value, "", field.key)
+			continue
+		}
+
+		services := resolve(field.value)
+		if services.Kind == yaml.ScalarNode && services.Tag == "!!null" {
+			continue
+		}
+		if services.Kind != yaml.MappingNode {
+			return nil, fmt.Errorf("line %d: services must be a mapping", services.Line)
+		}
+		for _, service := range fields(services) {
+			if err := file.addService(service.key, service.value); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return file, nil
+}
+
+// EnvFileUsers returns the services that load path through env_file.
+func (f *File) EnvFileUsers(path string) []string {
+	target := absPath(path)
+	var users []string
+	for _, envFile := range f.EnvFiles {
+		resolved := envFile.Path
+		if !filepath.IsAbs(resolved) && f.Path != "" {
+			resolved = filepath.Join(filepath.Dir(f.Path), resolved)
+		}
+		if absPath(resolved) == target && !containsService(users, envFile.Service) {
+			users = append(users, envFile.Service)
+		}
+	}
+	return users
+}
+
+func (f *File) addService(name string, node *yaml.Node) error {
+	f.Services = append(f.Services, name)
+
+	service := resolve(node)
+	if service.Kind == yaml.ScalarNode && service.Tag == "!!null" {
+		return nil
+	}
+	if service.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: service '%s' must be a mapping", service.Line, name)
+	}
+
+	for _, field := range fields(service) {
+		path := "services." + name + "." + field.key
+		f.walk(field.value, name, path)
+
+		switch field.key {
+		case "environment":
+			f.addPassthroughs(name, path, resolve(field.value))
+		case "env_file":
+			if err := f.addEnvFiles(name, resolve(field.value)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// walk records the ${VAR} references in every scalar below node.
+func (f *File) walk(node *yaml.Node, service, path string) {
+	switch node.Kind {
+	case yaml.AliasNode:
+		f.walk(node.Alias, service, path)
+	case yaml.MappingNode:
+		for _, field := range fields(node) {
+			f.walk(field.value, service, path+"."+field.key)
+		}
+	case yaml.SequenceNode:
+		for i, item := range node.Content {
+			f.walk(item, service, fmt.Sprintf("%s[%d]", path, i))
+		}
+	case yaml.ScalarNode:
+		for _, ref := range parser.FindReferences(node.Value, parser.InterpolateCompose) {
+			f.Usages = append(f.Usages, Usage{
+				Name:      ref.Name,
+				Service:   service,
+				Field:     path,
+				Line:      node.Line,
+				Reference: ref,
+			})
+		}
+	}
+}
+
+// addPassthroughs records environment entries without a value, which
+// compose fills from its own environment.
+func (f *File) addPassthroughs(service, path string, node *yaml.Node) {
+	passthrough := func(name string, line int) {
+		f.Usages = append(f.Usages, Usage{
+			Name:        name,
+			Service:     service,
+			Field:       path + "." + name,
+			Line:        line,
+			Passthrough: true,
+		})
+	}
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		for _, field := range fields(node) {
+			value := resolve(field.value)
+			if value.Kind == yaml.ScalarNode && value.Tag == "!!null" {
+				passthrough(field.key, field.line)
+			}
+		}
+	case yaml.SequenceNode:
+		for _, item := range node.Content {
+			item = resolve(item)
+			if item.Kind == yaml.ScalarNode && !strings.Contains(item.Value, "=") && item.Value != "" {
+				passthrough(item.Value, item.Line)
+			}
+		}
+	}
+}
+
+// addEnvFiles records env_file given as a path, a list of paths or a list
+// of {path, required} mappings.
+func (f *File) addEnvFiles(service string, node *yaml.Node) error {
+	add := func(item *yaml.Node) error {
+		item = resolve(item)
+		switch item.Kind {
+		case yaml.ScalarNode:
+			f.EnvFiles = append(f.EnvFiles, EnvFile{Service: service, Path: item.Value, Required: true, Line: item.Line})
+		case yaml.MappingNode:
+			envFile := EnvFile{Service: service, Required: true, Line: item.Line}
+			for _, field := range fields(item) {
+				value := resolve(field.value)
+				switch field.key {
+				case "path":
+					envFile.Path = value.Value
+				case "required":
+					envFile.Required = value.Value != "false"
+				}
+			}
+			if envFile.Path == "" {
+				return fmt.Errorf("line %d: env_file entry of service '%s' has no path", item.Line, service)
+			}
+			f.EnvFiles = append(f.EnvFiles, envFile)
+		default:
+			return fmt.Errorf("line %d: invalid env_file entry in service '%s'", item.Line, service)
+		}
+		return nil
+	}
+
+	if node.Kind == yaml.SequenceNode {
+		for _, item := range node.Content {
+			if err := add(item); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return add(node)
+}
+
+type field struct {
+	key   string
+	value *yaml.Node
+	line  int
+}
+
+// fields returns the entries of a mapping with "<<" merge keys applied;
+// explicit keys take precedence over merged ones.
+func fields(node *yaml.Node) []field {
+	var result []field
+	var merged []field
+	seen := make(map[string]bool)
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		if key.Tag == "!!merge" {
+			for _, source := range mergeSources(value) {
+				merged = append(merged, fields(source)...)
+			}
+			continue
+		}
+		if !seen[key.Value] {
+			seen[key.Value] = true
+			result = append(result, field{key: key.Value, value: value, line: key.Line})
+		}
+	}
+
+	for _, field := range merged {
+		if !seen[field.key] {
+			seen[field.key] = true
+			result = append(result, field)
+		}
+	}
+	return result
+}
+
+func mergeSources(node *yaml.Node) []*yaml.Node {
+	node = resolve(node)
+	switch node.Kind {
+	case yaml.MappingNode:
+		return []*yaml.Node{node}
+	case yaml.SequenceNode:
+		var sources []*yaml.Node
+		for _, item := range node.Content {
+			if item = resolve(item); item.Kind == yaml.MappingNode {
+				sources = append(sources, item)
+			}
+		}
+		return sources
+	}
+	return nil
+}
+
+func resolve(node *yaml.Node) *yaml.Node {
+	for node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	return node
+}
+
+func absPath(path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return filepath.Clean(path)
+	}
+	return abs
+}
+
+func containsService(services []string, service string) bool {
+	for _, s := range services {
+		if s == service {
+			return true
+		}
+	}
+	return false
+}
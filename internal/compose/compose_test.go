@@ -0,0 +1,139 @@
+package compose
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const composeFile = `x-common: &common
+  env_file: .env
+  environment:
+    LOG_LEVEL: ${LOG_LEVEL:-info}
+
+services:
+  api:
+    <<: *common
+    image: "registry.example.com/api:${API_TAG}"
+    environment:
+      - DATABASE_URL=postgres://${DB_USER}:${DB_PASSWORD:?set DB_PASSWORD}@db/app
+      - SENTRY_DSN
+      - PRICE=$$5
+  worker:
+    image: worker
+    env_file:
+      - path: ./worker.env
+        required: false
+    environment:
+      QUEUE_URL:
+      CONCURRENCY: 4
+  db:
+
+volumes:
+  data:
+    name: ${VOLUME_NAME}
+`
+
+func usageNames(usages []Usage, service string) []string {
+	var names []string
+	for _, usage := range usages {
+		if usage.Service == service {
+			names = append(names, usage.Name)
+		}
+	}
+	return names
+}
+
+func TestParse(t *testing.T) {
+	file, err := Parse([]byte(composeFile))
+	if err != nil {
+		t.Fatalf("Failed to parse compose file: %v", err)
+	}
+
+	if !reflect.DeepEqual(file.Services, []string{"api", "worker", "db"}) {
+		t.Errorf("Unexpected services: %v", file.Services)
+	}
+
+	if names := usageNames(file.Usages, "api"); !reflect.DeepEqual(names, []string{"API_TAG", "DB_USER", "DB_PASSWORD", "SENTRY_DSN"}) {
+		t.Errorf("Unexpected api usages: %v", names)
+	}
+	if names := usageNames(file.Usages, "worker"); !reflect.DeepEqual(names, []string{"QUEUE_URL"}) {
+		t.Errorf("Unexpected worker usages: %v", names)
+	}
+	if names := usageNames(file.Usages, ""); !reflect.DeepEqual(names, []string{"LOG_LEVEL", "VOLUME_NAME"}) {
+		t.Errorf("Unexpected top-level usages: %v", names)
+	}
+
+	for _, usage := range file.Usages {
+		switch usage.Name {
+		case "SENTRY_DSN":
+			if !usage.Passthrough || usage.Line != 12 {
+				t.Errorf("Expected SENTRY_DSN to pass through on line 12, got %+v", usage)
+			}
+		case "DB_PASSWORD":
+			if !usage.Reference.Required() || usage.Field != "services.api.environment[0]" {
+				t.Errorf("Expected required DB_PASSWORD in environment[0], got %+v", usage)
+			}
+		case "LOG_LEVEL":
+			if !usage.HasFallback() {
+				t.Errorf("Expected LOG_LEVEL to have a fallback, got %+v", usage)
+			}
+		}
+	}
+
+	expected := []EnvFile{
+		{Service: "api", Path: ".env", Required: true, Line: 2},
+		{Service: "worker", Path: "./worker.env", Required: false, Line: 17},
+	}
+	if !reflect.DeepEqual(file.EnvFiles, expected) {
+		t.Errorf("Unexpected env files: %+v", file.EnvFiles)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, content := range []string{"- a\n- b\n", "services: [api]\n", "services:\n  api: nginx\n", "services:\n  api:\n    env_file:\n      - required: true\n"} {
+		if _, err := Parse([]byte(content)); err == nil {
+			t.Errorf("Expected error for %q", content)
+		}
+	}
+
+	file, err := Parse(nil)
+	if err != nil || len(file.Services) != 0 {
+		t.Errorf("Expected an empty file to parse, got %+v, %v", file, err)
+	}
+}
+
+func TestLoadAndEnvFileUsers(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "envguard-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	if _, err := Find(tmpDir); err == nil {
+		t.Error("Expected error when no compose file exists")
+	}
+
+	path := filepath.Join(tmpDir, "docker-compose.yml")
+	if err := os.WriteFile(path, []byte(composeFile), 0644); err != nil {
+		t.Fatalf("Failed to write compose file: %v", err)
+	}
+
+	found, err := Find(tmpDir)
+	if err != nil || found != path {
+		t.Fatalf("Expected to find %s, got %s, %v", path, found, err)
+	}
+
+	file, err := Load(found)
+	if err != nil {
+		t.Fatalf("Failed to load compose file: %v", err)
+	}
+
+	if users := file.EnvFileUsers(filepath.Join(tmpDir, ".env")); !reflect.DeepEqual(users, []string{"api"}) {
+		t.Errorf("Expected api to load .env, got %v", users)
+	}
+	if users := file.EnvFileUsers(filepath.Join(tmpDir, "other.env")); len(users) != 0 {
+		t.Errorf("Expected no users for other.env, got %v", users)
+	}
+}
@@ -0,0 +1,106 @@
+package envmanager
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ConfigFile holds project-wide envguard settings inside .envguard/.
+const ConfigFile = "config.json"
+
+// Config is the optional project configuration read from .envguard/config.json.
+type Config struct {
+	// Protected lists environments that need explicit confirmation before
+	// they are used, deleted or overwritten.
+	Protected []string `json:"protected,omitempty"`
+
+	// ReadOnlyProtected makes the root .env read-only while a protected
+	// environment is active.
+	ReadOnlyProtected bool `json:"read_only_protected,omitempty"`
+
+	// ImplicitSync controls whether commands that switch or create
+	// environments first save .env edits into the active environment.
+	// Defaults to true when unset.
+	ImplicitSync *bool `json:"implicit_sync,omitempty"`
+}
+
+// ImplicitSyncEnabled reports the effective implicit_sync setting.
+func (c *Config) ImplicitSyncEnabled() bool {
+	return c.ImplicitSync == nil || *c.ImplicitSync
+}
+
+func (m *Manager) GetConfigPath() string {
+	return filepath.Join(m.envDir, ConfigFile)
+}
+
+// Config returns the project configuration, loading it on first use. A
+// missing config file yields the defaults.
+func (m *Manager) Config() (*Config, error) {
+	if m.config != nil {
+		return m.config, nil
+	}
+
+	config := &Config{}
+
+	content, err := os.ReadFile(m.GetConfigPath())
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read %s/%s: %w", EnvGuardDir, ConfigFile, err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(content, config); err != nil {
+			return nil, fmt.Errorf("failed to parse %s/%s: %w", EnvGuardDir, ConfigFile, err)
+		}
+	}
+
+	m.config = config
+	return config, nil
+}
+
+// renameProtected rewrites oldName to newName in the config's protected
+// list so protection follows a renamed environment. Other settings in the
+// file are left as they are.
+func (m *Manager) renameProtected(oldName, newName string) error {
+	config, err := m.Config()
+	if err != nil {
+		return err
+	}
+
+	found := false
+	protected := make([]string, len(config.Protected))
+	for i, name := range config.Protected {
+		if name == oldName {
+			name = newName
+			found = true
+		}
+		protected[i] = name
+	}
+	if !found {
+		return nil
+	}
+
+	content, err := os.ReadFile(m.GetConfigPath())
+	if err != nil {
+		return fmt.Errorf("failed to read %s/%s: %w", EnvGuardDir, ConfigFile, err)
+	}
+
+	raw := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(content, &raw); err != nil {
+		return fmt.Errorf("failed to parse %s/%s: %w", EnvGuardDir, ConfigFile, err)
+	}
+	if raw["protected"], err = json.Marshal(protected); err != nil {
+		return err
+	}
+
+	content, err = json.MarshalIndent(raw, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode %s/%s: %w", EnvGuardDir, ConfigFile, err)
+	}
+	if err := os.WriteFile(m.GetConfigPath(), append(content, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write %s/%s: %w", EnvGuardDir, ConfigFile, err)
+	}
+
+	config.Protected = protected
+	return nil
+}
@@ -0,0 +1,140 @@
+package envmanager
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/crabest/envguard/internal/parser"
+)
+
+// SnapshotDir holds the content of each environment as of its last use or
+// sync, used as the common base when detecting drift.
+const SnapshotDir = ".snapshots"
+
+// DriftState summarises how the root .env relates to the active environment.
+type DriftState string
+
+const (
+	DriftInSync       DriftState = "in_sync"
+	DriftLocalEdits   DriftState = "local_edits"
+	DriftStoreChanged DriftState = "store_changed"
+	DriftDiverged     DriftState = "diverged"
+)
+
+// Drift compares the root .env, the stored active environment and the
+// snapshot taken when they were last known to match.
+type Drift struct {
+	Environment string     `json:"environment"`
+	State       DriftState `json:"state"`
+	LocalKeys   []string   `json:"local_keys"`
+	StoreKeys   []string   `json:"store_keys"`
+}
+
+func (d *Drift) String() string {
+	switch d.State {
+	case DriftInSync:
+		return "in sync"
+	case DriftLocalEdits:
+		return fmt.Sprintf("local edits (%d keys)", len(d.LocalKeys))
+	case DriftStoreChanged:
+		return fmt.Sprintf("store changed (%d keys)", len(d.StoreKeys))
+	default:
+		return fmt.Sprintf("diverged (%d local, %d stored keys)", len(d.LocalKeys), len(d.StoreKeys))
+	}
+}
+
+func (m *Manager) GetSnapshotPath(envName string) string {
+	return filepath.Join(m.envDir, SnapshotDir, envName+".env")
+}
+
+// DetectDrift reports whether the root .env and the active environment
+// have changed since the last use or sync. Without a snapshot the stored
+// environment is taken as the base, so any difference counts as local edits.
+func (m *Manager) DetectDrift() (*Drift, error) {
+	activeEnv, err := m.GetActiveEnvironment()
+	if err != nil {
+		return nil, err
+	}
+
+	if !m.EnvironmentExists(activeEnv) {
+		return nil, fmt.Errorf("active environment '%s' no longer exists", activeEnv)
+	}
+
+	root, err := readVarsOrEmpty(m.GetRootEnvPath())
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse .env: %w", err)
+	}
+
+	stored, err := parser.ParseEnvFile(m.GetEnvPath(activeEnv))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse environment '%s': %w", activeEnv, err)
+	}
+
+	base := stored
+	if _, err := os.Stat(m.GetSnapshotPath(activeEnv)); err == nil {
+		base, err = parser.ParseEnvFile(m.GetSnapshotPath(activeEnv))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse snapshot for '%s': %w", activeEnv, err)
+		}
+	}
+
+	drift := &Drift{
+		Environment: activeEnv,
+		LocalKeys:   ChangedKeys(base, root),
+		StoreKeys:   ChangedKeys(base, stored),
+	}
+
+	switch {
+	case len(ChangedKeys(root, stored)) == 0:
+		drift.State = DriftInSync
+		drift.LocalKeys = []string{}
+		drift.StoreKeys = []string{}
+	case len(drift.StoreKeys) == 0:
+		drift.State = DriftLocalEdits
+	case len(drift.LocalKeys) == 0:
+		drift.State = DriftStoreChanged
+	default:
+		drift.State = DriftDiverged
+	}
+
+	return drift, nil
+}
+
+// ChangedKeys returns the sorted keys that were added, removed or changed
+// between two sets of variables.
+func ChangedKeys(from, to parser.EnvVars) []string {
+	changed := []string{}
+	for key, value := range from {
+		if other, ok := to[key]; !ok || other != value {
+			changed = append(changed, key)
+		}
+	}
+	for key := range to {
+		if _, ok := from[key]; !ok {
+			changed = append(changed, key)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+// saveSnapshot records the stored environment as the new drift base.
+func (m *Manager) saveSnapshot(envName string) error {
+	if err := os.MkdirAll(filepath.Join(m.envDir, SnapshotDir), 0755); err != nil {
+		return fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+
+	if err := m.copyFile(m.GetEnvPath(envName), m.GetSnapshotPath(envName)); err != nil {
+		return fmt.Errorf("failed to snapshot environment '%s': %w", envName, err)
+	}
+	return nil
+}
+
+func readVarsOrEmpty(path string) (parser.EnvVars, error) {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return parser.EnvVars{}, nil
+	}
+	return parser.ParseEnvFile(path)
+}
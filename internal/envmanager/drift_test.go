@@ -0,0 +1,110 @@
+package envmanager
+
+import (
+	"os"
+	"testing"
+)
+
+func TestDetectDrift(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "envguard-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	manager, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+
+	if err := manager.CreateEnvironment("dev", false); err != nil {
+		t.Fatalf("Failed to create environment: %v", err)
+	}
+	os.WriteFile(manager.GetEnvPath("dev"), []byte("A=1\nB=2\n"), 0644)
+
+	if err := manager.UseEnvironment("dev"); err != nil {
+		t.Fatalf("Failed to use environment: %v", err)
+	}
+
+	assertDrift := func(state DriftState, local, store int) {
+		t.Helper()
+		drift, err := manager.DetectDrift()
+		if err != nil {
+			t.Fatalf("Failed to detect drift: %v", err)
+		}
+		if drift.State != state || len(drift.LocalKeys) != local || len(drift.StoreKeys) != store {
+			t.Errorf("Expected %s (%d local, %d store), got %+v", state, local, store, drift)
+		}
+	}
+
+	assertDrift(DriftInSync, 0, 0)
+
+	os.WriteFile(manager.GetRootEnvPath(), []byte("A=1\nB=3\nC=4\n"), 0644)
+	assertDrift(DriftLocalEdits, 2, 0)
+
+	os.WriteFile(manager.GetEnvPath("dev"), []byte("A=9\nB=2\n"), 0644)
+	assertDrift(DriftDiverged, 2, 1)
+
+	os.WriteFile(manager.GetRootEnvPath(), []byte("A=1\nB=2\n"), 0644)
+	assertDrift(DriftStoreChanged, 0, 1)
+
+	os.WriteFile(manager.GetRootEnvPath(), []byte("A=9\nB=2\n"), 0644)
+	assertDrift(DriftInSync, 0, 0)
+}
+
+func TestSyncActiveEnvironmentRespectsDrift(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "envguard-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	manager, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	if err := manager.CreateEnvironment("dev", false); err != nil {
+		t.Fatalf("Failed to create environment: %v", err)
+	}
+	os.WriteFile(manager.GetEnvPath("dev"), []byte("A=1\nB=2\n"), 0644)
+	if err := manager.UseEnvironment("dev"); err != nil {
+		t.Fatalf("Failed to use environment: %v", err)
+	}
+
+	assertStored := func(expected string) {
+		t.Helper()
+		stored, _ := os.ReadFile(manager.GetEnvPath("dev"))
+		if string(stored) != expected {
+			t.Errorf("Expected stored dev %q, got %q", expected, string(stored))
+		}
+	}
+
+	// The store changed and .env did not: nothing to push, store kept
+	os.WriteFile(manager.GetEnvPath("dev"), []byte("A=9\nB=2\n"), 0644)
+	if err := manager.SyncActiveEnvironment(); err != nil {
+		t.Fatalf("Expected store changes to be skipped, got %v", err)
+	}
+	assertStored("A=9\nB=2\n")
+
+	// Both changed: refuse rather than pick a side
+	os.WriteFile(manager.GetRootEnvPath(), []byte("A=1\nB=3\n"), 0644)
+	if err := manager.SyncActiveEnvironment(); err == nil {
+		t.Error("Expected diverged environments to be refused")
+	}
+	assertStored("A=9\nB=2\n")
+
+	// Only local edits are pushed
+	os.WriteFile(manager.GetEnvPath("dev"), []byte("A=1\nB=2\n"), 0644)
+	if err := manager.SyncActiveEnvironment(); err != nil {
+		t.Fatalf("Failed to sync local edits: %v", err)
+	}
+	assertStored("A=1\nB=3\n")
+}
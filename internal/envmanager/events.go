@@ -0,0 +1,35 @@
+package envmanager
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/crabest/envguard/internal/report"
+)
+
+// SetReporter replaces the reporter that receives the Manager's events.
+func (m *Manager) SetReporter(reporter report.Reporter) {
+	m.reporter = reporter
+}
+
+func (m *Manager) getReporter() report.Reporter {
+	if m.reporter == nil {
+		m.reporter = report.NewTextReporter(os.Stdout)
+	}
+	return m.reporter
+}
+
+func (m *Manager) emit(level report.Level, name, icon string, fields report.Fields, format string, args ...interface{}) {
+	m.getReporter().Report(report.Event{
+		Name:    name,
+		Level:   level,
+		Message: fmt.Sprintf(format, args...),
+		Fields:  fields,
+		Icon:    icon,
+	})
+}
+
+// preview emits the lines of a change plan through the Manager's reporter.
+func (m *Manager) preview(pending bool) report.Preview {
+	return report.Preview{Reporter: m.getReporter(), Pending: pending}
+}
@@ -0,0 +1,54 @@
+package envmanager
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/crabest/envguard/internal/report"
+)
+
+// ImportEnvironment stores content as envName. An existing environment is
+// only replaced when overwrite is set; if it is the active one the root
+// .env is updated too.
+func (m *Manager) ImportEnvironment(envName, content, source string, overwrite bool) error {
+	if err := ValidateEnvName(envName); err != nil {
+		return err
+	}
+
+	if err := m.EnsureEnvGuardDir(); err != nil {
+		return err
+	}
+
+	exists := m.EnvironmentExists(envName)
+	if exists && !overwrite {
+		return fmt.Errorf("environment '%s' already exists (use --force to replace it)", envName)
+	}
+
+	if exists {
+		if err := m.requireProtectedConfirmation(envName, "replace"); err != nil {
+			return err
+		}
+		if err := m.guardLocalEdits(envName); err != nil {
+			return err
+		}
+	}
+
+	if err := os.WriteFile(m.GetEnvPath(envName), []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write environment '%s': %w", envName, err)
+	}
+
+	if !exists {
+		if err := m.recordCreated(envName, ""); err != nil {
+			return err
+		}
+	}
+
+	m.emit(report.LevelSuccess, "environment.imported", "✅", report.Fields{"environment": envName, "source": source, "replaced": exists},
+		"Imported %s into environment '%s'", source, envName)
+	m.emit(report.LevelInfo, "environment.path", "📁", report.Fields{"environment": envName, "path": m.GetEnvPath(envName)}, "Environment file: %s/%s.env", EnvGuardDir, envName)
+
+	if exists {
+		return m.refreshActive(envName)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package envmanager
+
+import (
+	"os"
+	"testing"
+)
+
+func TestImportEnvironment(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "envguard-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	manager, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+
+	if err := manager.ImportEnvironment("staging", "DB_HOST=db\n", "config.json", false); err != nil {
+		t.Fatalf("Failed to import environment: %v", err)
+	}
+	content, _ := os.ReadFile(manager.GetEnvPath("staging"))
+	if string(content) != "DB_HOST=db\n" {
+		t.Errorf("Unexpected environment content: %q", string(content))
+	}
+	if meta, err := manager.GetMetadata("staging"); err != nil || meta.CreatedAt == nil {
+		t.Errorf("Expected creation to be recorded, got %+v (%v)", meta, err)
+	}
+
+	if err := manager.ImportEnvironment("staging", "DB_HOST=other\n", "config.json", false); err == nil {
+		t.Error("Expected importing over an existing environment to fail without overwrite")
+	}
+
+	if err := manager.UseEnvironment("staging"); err != nil {
+		t.Fatalf("Failed to use environment: %v", err)
+	}
+	if err := manager.ImportEnvironment("staging", "DB_HOST=other\n", "config.json", true); err != nil {
+		t.Fatalf("Failed to replace environment: %v", err)
+	}
+	root, _ := os.ReadFile(manager.GetRootEnvPath())
+	if string(root) != "DB_HOST=other\n" {
+		t.Errorf("Expected the active .env to be refreshed, got %q", string(root))
+	}
+
+	if err := manager.ImportEnvironment("../escape", "", "config.json", false); err == nil {
+		t.Error("Expected an invalid environment name to be rejected")
+	}
+}
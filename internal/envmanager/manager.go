@@ -1,15 +1,17 @@
 package envmanager
 
 import (
-	"bufio"
 	"crypto/md5"
 	"fmt"
 	"io"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 
-	"github.com/fatih/color"
+	"github.com/crabest/envguard/internal/parser"
+	"github.com/crabest/envguard/internal/report"
+	"github.com/crabest/envguard/internal/trust"
 )
 
 const (
@@ -17,9 +19,19 @@ const (
 	ActiveFile  = ".active"
 )
 
+// EnvironmentEnv names the environment being validated when nothing more
+// specific does, e.g. in a deployed service without .envguard/.active.
+const EnvironmentEnv = "ENVGUARD_ENV"
+
 type Manager struct {
 	workingDir string
 	envDir     string
+
+	config             *Config
+	confirmedProtected string
+	noImplicitSync     bool
+	prompter           Prompter
+	reporter           report.Reporter
 }
 
 func NewManager() (*Manager, error) {
@@ -28,6 +40,16 @@ func NewManager() (*Manager, error) {
 		return nil, fmt.Errorf("failed to get working directory: %w", err)
 	}
 
+	return NewManagerAt(wd)
+}
+
+// NewManagerAt creates a manager for the project rooted at dir.
+func NewManagerAt(dir string) (*Manager, error) {
+	wd, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve project directory: %w", err)
+	}
+
 	envDir := filepath.Join(wd, EnvGuardDir)
 
 	return &Manager{
@@ -41,7 +63,7 @@ func (m *Manager) EnsureEnvGuardDir() error {
 		if err := os.MkdirAll(m.envDir, 0755); err != nil {
 			return fmt.Errorf("failed to create %s directory: %w", EnvGuardDir, err)
 		}
-		color.Green("✅ Created %s directory", EnvGuardDir)
+		m.emit(report.LevelSuccess, "store.created", "✅", report.Fields{"path": m.envDir}, "Created %s directory", EnvGuardDir)
 	}
 	return nil
 }
@@ -59,11 +81,10 @@ func (m *Manager) GetActivePath() string {
 }
 
 func (m *Manager) ListEnvironments() ([]string, error) {
-	if err := m.EnsureEnvGuardDir(); err != nil {
-		return nil, err
-	}
-
 	files, err := os.ReadDir(m.envDir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to read %s directory: %w", EnvGuardDir, err)
 	}
@@ -95,15 +116,24 @@ func (m *Manager) switchEnvironment(envName string) error {
 		return fmt.Errorf("environment '%s' does not exist in %s", envName, EnvGuardDir)
 	}
 
+	if err := m.requireProtectedConfirmation(envName, "use"); err != nil {
+		return err
+	}
+
 	rootEnvPath := m.GetRootEnvPath()
 
+	if err := m.makeRootEnvWritable(); err != nil {
+		return fmt.Errorf("failed to make .env writable: %w", err)
+	}
+
 	if err := m.copyFile(envPath, rootEnvPath); err != nil {
 		return fmt.Errorf("failed to switch to environment '%s': %w", envName, err)
 	}
+	m.rootEnvWritten()
 
-	color.Blue("📁 Active .env file updated from %s/%s.env", EnvGuardDir, envName)
+	m.emit(report.LevelInfo, "env.materialized", "📁", report.Fields{"environment": envName}, "Active .env file updated from %s/%s.env", EnvGuardDir, envName)
 
-	return nil
+	return m.setRootEnvReadOnly(envName)
 }
 
 func (m *Manager) CreateEnvironment(envName string, fromCurrent bool) error {
@@ -111,6 +141,10 @@ func (m *Manager) CreateEnvironment(envName string, fromCurrent bool) error {
 		return err
 	}
 
+	if err := ValidateEnvName(envName); err != nil {
+		return err
+	}
+
 	envPath := m.GetEnvPath(envName)
 
 	if m.EnvironmentExists(envName) {
@@ -121,7 +155,7 @@ func (m *Manager) CreateEnvironment(envName string, fromCurrent bool) error {
 	if fromCurrent {
 		rootEnvPath := m.GetRootEnvPath()
 		if _, err := os.Stat(rootEnvPath); os.IsNotExist(err) {
-			color.Yellow("⚠️  No .env file found in root directory, creating empty environment")
+			m.emit(report.LevelWarning, "environment.no_root_env", "⚠️ ", report.Fields{"environment": envName}, "No .env file found in root directory, creating empty environment")
 			fromCurrent = false
 		} else {
 			sourceFile = rootEnvPath
@@ -132,17 +166,21 @@ func (m *Manager) CreateEnvironment(envName string, fromCurrent bool) error {
 		if err := m.copyFile(sourceFile, envPath); err != nil {
 			return fmt.Errorf("failed to copy current .env to '%s': %w", envName, err)
 		}
-		color.Green("✅ Created environment '%s' based on current .env", color.CyanString(envName))
+		m.emit(report.LevelSuccess, "environment.created", "✅", report.Fields{"environment": envName, "from_current": true}, "Created environment '%s' based on current .env", envName)
 	} else {
 		file, err := os.Create(envPath)
 		if err != nil {
 			return fmt.Errorf("failed to create environment '%s': %w", envName, err)
 		}
 		file.Close()
-		color.Green("✅ Created empty environment: %s", color.CyanString(envName))
+		m.emit(report.LevelSuccess, "environment.created", "✅", report.Fields{"environment": envName, "from_current": false}, "Created empty environment: %s", envName)
+	}
+
+	if err := m.recordCreated(envName, ""); err != nil {
+		return err
 	}
 
-	color.Blue("📁 Environment file: %s/%s.env", EnvGuardDir, envName)
+	m.emit(report.LevelInfo, "environment.path", "📁", report.Fields{"environment": envName, "path": envPath}, "Environment file: %s/%s.env", EnvGuardDir, envName)
 	return nil
 }
 
@@ -155,17 +193,18 @@ func (m *Manager) DeleteEnvironment(envName string, confirm bool) error {
 		return fmt.Errorf("environment '%s' does not exist", envName)
 	}
 
-	if confirm {
-		color.Yellow("⚠️  Are you sure you want to delete environment '%s'? (y/N): ", envName)
-		reader := bufio.NewReader(os.Stdin)
-		response, err := reader.ReadString('\n')
+	if err := m.requireProtectedConfirmation(envName, "delete"); err != nil {
+		return err
+	}
+
+	// Protected environments were confirmed by typing their name above
+	if protected, _ := m.IsProtected(envName); confirm && !protected {
+		ok, err := m.getPrompter().Confirm(fmt.Sprintf("Are you sure you want to delete environment '%s'?", envName), false)
 		if err != nil {
-			return fmt.Errorf("failed to read user input: %w", err)
+			return err
 		}
-
-		response = strings.ToLower(strings.TrimSpace(response))
-		if response != "y" && response != "yes" {
-			color.Blue("ℹ️  Deletion cancelled")
+		if !ok {
+			m.emit(report.LevelInfo, "environment.delete_cancelled", "ℹ️ ", report.Fields{"environment": envName}, "Deletion cancelled")
 			return nil
 		}
 	}
@@ -175,10 +214,201 @@ func (m *Manager) DeleteEnvironment(envName string, confirm bool) error {
 		return fmt.Errorf("failed to delete environment '%s': %w", envName, err)
 	}
 
-	color.Green("✅ Successfully deleted environment: %s", color.CyanString(envName))
+	if err := m.recordDeleted(envName); err != nil {
+		return err
+	}
+
+	if err := os.Remove(m.GetSnapshotPath(envName)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove snapshot for '%s': %w", envName, err)
+	}
+
+	m.emit(report.LevelSuccess, "environment.deleted", "✅", report.Fields{"environment": envName}, "Successfully deleted environment: %s", envName)
+	return nil
+}
+
+// RenameEnvironment moves a stored environment to a new name, keeping
+// .active pointed at it when it is the active one. A protected environment
+// needs confirmation and stays protected under its new name.
+func (m *Manager) RenameEnvironment(oldName, newName string) error {
+	if err := m.EnsureEnvGuardDir(); err != nil {
+		return err
+	}
+
+	if err := ValidateEnvName(newName); err != nil {
+		return err
+	}
+
+	if !m.EnvironmentExists(oldName) {
+		return fmt.Errorf("environment '%s' does not exist", oldName)
+	}
+
+	if m.EnvironmentExists(newName) {
+		return fmt.Errorf("environment '%s' already exists", newName)
+	}
+
+	if err := m.requireProtectedConfirmation(oldName, "rename"); err != nil {
+		return err
+	}
+	if err := m.requireProtectedConfirmation(newName, "replace"); err != nil {
+		return err
+	}
+
+	// The bookkeeping moves first and the environment file last, so a
+	// failure anywhere leaves oldName as it was once undo has run
+	var undo []func()
+	rollback := func(err error) error {
+		for i := len(undo) - 1; i >= 0; i-- {
+			undo[i]()
+		}
+		return err
+	}
+
+	for _, path := range []string{m.GetMetadataPath(), m.GetConfigPath(), m.GetActivePath()} {
+		undo = append(undo, m.keepFile(path))
+	}
+
+	if err := m.recordRenamed(oldName, newName); err != nil {
+		return rollback(err)
+	}
+
+	if err := m.renameProtected(oldName, newName); err != nil {
+		return rollback(err)
+	}
+
+	if _, err := os.Stat(m.GetSnapshotPath(oldName)); err == nil {
+		if err := os.Rename(m.GetSnapshotPath(oldName), m.GetSnapshotPath(newName)); err != nil {
+			return rollback(fmt.Errorf("failed to rename snapshot for '%s': %w", oldName, err))
+		}
+		undo = append(undo, func() { os.Rename(m.GetSnapshotPath(newName), m.GetSnapshotPath(oldName)) })
+	}
+
+	if activeEnv, err := m.GetActiveEnvironment(); err == nil && activeEnv == oldName {
+		if err := m.SetActiveEnvironment(newName); err != nil {
+			return rollback(fmt.Errorf("failed to update active environment: %w", err))
+		}
+	}
+
+	if err := os.Rename(m.GetEnvPath(oldName), m.GetEnvPath(newName)); err != nil {
+		return rollback(fmt.Errorf("failed to rename environment '%s': %w", oldName, err))
+	}
+
+	m.emit(report.LevelSuccess, "environment.renamed", "✅", report.Fields{"from": oldName, "to": newName}, "Renamed environment %s → %s", oldName, newName)
+	return nil
+}
+
+// keepFile remembers the current contents of path and returns a func
+// that puts them back, removing path again if it did not exist.
+func (m *Manager) keepFile(path string) func() {
+	content, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return func() {}
+	}
+	existed := err == nil
+	return func() {
+		if existed {
+			os.WriteFile(path, content, 0644)
+		} else {
+			os.Remove(path)
+		}
+		// the cached config may describe the rewritten file
+		m.config = nil
+	}
+}
+
+// CopyEnvironment creates dst from the contents of src. When only is
+// non-empty just the matching keys are copied; keys matching exclude are
+// always dropped. Both accept shell-style patterns such as FEATURE_*.
+func (m *Manager) CopyEnvironment(src, dst string, only, exclude []string) error {
+	if err := m.EnsureEnvGuardDir(); err != nil {
+		return err
+	}
+
+	if err := ValidateEnvName(dst); err != nil {
+		return err
+	}
+
+	if !m.EnvironmentExists(src) {
+		return fmt.Errorf("environment '%s' does not exist", src)
+	}
+
+	if m.EnvironmentExists(dst) {
+		return fmt.Errorf("environment '%s' already exists", dst)
+	}
+
+	// dst may still be listed as protected in the config
+	if err := m.requireProtectedConfirmation(dst, "replace"); err != nil {
+		return err
+	}
+
+	if len(only) == 0 && len(exclude) == 0 {
+		if err := m.copyFile(m.GetEnvPath(src), m.GetEnvPath(dst)); err != nil {
+			return fmt.Errorf("failed to copy environment '%s': %w", src, err)
+		}
+		m.emit(report.LevelSuccess, "environment.copied", "✅", report.Fields{"from": src, "to": dst}, "Copied environment %s → %s", src, dst)
+		return m.recordCreated(dst, src)
+	}
+
+	entries, err := parser.ParseEnvFileEntries(m.GetEnvPath(src))
+	if err != nil {
+		return fmt.Errorf("failed to parse environment '%s': %w", src, err)
+	}
+
+	var kept []parser.Entry
+	for _, entry := range entries {
+		if len(only) > 0 && !MatchesKeyPattern(entry.Key, only) {
+			continue
+		}
+		if MatchesKeyPattern(entry.Key, exclude) {
+			continue
+		}
+		kept = append(kept, entry)
+	}
+
+	if err := os.WriteFile(m.GetEnvPath(dst), []byte(renderEntries(kept)), 0644); err != nil {
+		return fmt.Errorf("failed to write environment '%s': %w", dst, err)
+	}
+
+	m.emit(report.LevelSuccess, "environment.copied", "✅", report.Fields{"from": src, "to": dst, "copied": len(kept), "total": len(entries)},
+		"Copied %d of %d variables from %s → %s", len(kept), len(entries), src, dst)
+	return m.recordCreated(dst, src)
+}
+
+// ValidateEnvName rejects names that cannot be stored as a single file
+// inside the .envguard/ directory.
+func ValidateEnvName(envName string) error {
+	if envName == "" {
+		return fmt.Errorf("environment name is required")
+	}
+	if strings.HasPrefix(envName, ".") || strings.ContainsAny(envName, `/\`) {
+		return fmt.Errorf("invalid environment name '%s'", envName)
+	}
 	return nil
 }
 
+// MatchesKeyPattern reports whether key matches any of the given
+// shell-style patterns.
+func MatchesKeyPattern(key string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, key); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// renderEntries writes entries back as dotenv text, copying each entry's
+// comment block and assignment lines exactly as they were written.
+func renderEntries(entries []parser.Entry) string {
+	var b strings.Builder
+	for _, entry := range entries {
+		for _, comment := range entry.RawComments {
+			b.WriteString(comment + "\n")
+		}
+		b.WriteString(entry.Raw + "\n")
+	}
+	return b.String()
+}
+
 func (m *Manager) copyFile(src, dst string) error {
 	sourceFile, err := os.Open(src)
 	if err != nil {
@@ -200,21 +430,22 @@ func (m *Manager) copyFile(src, dst string) error {
 	return destFile.Sync()
 }
 
+// rootEnvWritten keeps the shell hook loading .env after envguard itself
+// rewrote it, when the user had allowed the project. A failure only costs
+// a new "envguard allow", so it is reported without failing the command.
+func (m *Manager) rootEnvWritten() {
+	if err := trust.Refresh(m.workingDir); err != nil {
+		m.emit(report.LevelWarning, "trust.refresh_failed", "⚠️ ", nil, "Failed to update the shell hook allowance: %v", err)
+	}
+}
+
 func (m *Manager) PromptForCurrentEnv() (bool, error) {
 	rootEnvPath := m.GetRootEnvPath()
 	if _, err := os.Stat(rootEnvPath); os.IsNotExist(err) {
 		return false, nil
 	}
 
-	color.Yellow("🤔 Do you want to base the new environment on the current .env file? (Y/n): ")
-	reader := bufio.NewReader(os.Stdin)
-	response, err := reader.ReadString('\n')
-	if err != nil {
-		return false, fmt.Errorf("failed to read user input: %w", err)
-	}
-
-	response = strings.ToLower(strings.TrimSpace(response))
-	return response == "" || response == "y" || response == "yes", nil
+	return m.getPrompter().Confirm("Do you want to base the new environment on the current .env file?", true)
 }
 
 func (m *Manager) SetActiveEnvironment(envName string) error {
@@ -238,10 +469,6 @@ func (m *Manager) SetActiveEnvironment(envName string) error {
 }
 
 func (m *Manager) GetActiveEnvironment() (string, error) {
-	if err := m.EnsureEnvGuardDir(); err != nil {
-		return "", err
-	}
-
 	activePath := m.GetActivePath()
 	if _, err := os.Stat(activePath); os.IsNotExist(err) {
 		return "", fmt.Errorf("no active environment set")
@@ -269,7 +496,15 @@ func (m *Manager) UseEnvironment(envName string) error {
 		return fmt.Errorf("failed to set active environment: %w", err)
 	}
 
-	color.Green("✅ Using environment: %s", color.CyanString(envName))
+	if err := m.recordUsed(envName); err != nil {
+		return err
+	}
+
+	if err := m.saveSnapshot(envName); err != nil {
+		return err
+	}
+
+	m.emit(report.LevelSuccess, "environment.used", "✅", report.Fields{"environment": envName}, "Using environment: %s", envName)
 	return nil
 }
 
@@ -298,16 +533,47 @@ func (m *Manager) SyncActiveEnvironment() error {
 
 	// Compare file contents to see if sync is needed
 	if !m.filesAreDifferent(rootEnvPath, envPath) {
-		// Files are the same, no sync needed
+		m.emit(report.LevelDebug, "sync.unchanged", "", report.Fields{"environment": activeEnv}, ".env matches %s/%s.env, nothing to sync", EnvGuardDir, activeEnv)
 		return nil
 	}
 
+	// Only .env edits are pushed; when the stored file changed too, copying
+	// .env over it would silently drop those changes
+	drift, err := m.DetectDrift()
+	if err != nil {
+		return err
+	}
+	switch drift.State {
+	case DriftStoreChanged:
+		m.emit(report.LevelWarning, "sync.store_changed", "⚠️ ", report.Fields{"environment": activeEnv, "store_keys": drift.StoreKeys},
+			"%s/%s.env changed since .env was last synced (%s); not overwriting it", EnvGuardDir, activeEnv, strings.Join(drift.StoreKeys, ", "))
+		m.emit(report.LevelInfo, "hint", "💡", nil, "Run 'envguard sync --direction pull' to update .env")
+		return nil
+	case DriftDiverged:
+		return fmt.Errorf(".env and %s/%s.env both changed since the last sync (%s); run 'envguard status' and 'envguard sync' to reconcile them",
+			EnvGuardDir, activeEnv, drift)
+	}
+
+	// Never write into a protected environment behind the user's back, and
+	// never let the caller go on to replace .env and lose the edits either
+	if m.needsProtectedConfirmation(activeEnv) {
+		m.emit(report.LevelWarning, "sync.protected_pending", "⚠️ ", report.Fields{"environment": activeEnv},
+			".env has changes not synced to protected environment '%s'", activeEnv)
+		if err := m.requireProtectedConfirmation(activeEnv, "overwrite"); err != nil {
+			return fmt.Errorf("unsynced .env changes to '%s': %w", activeEnv, err)
+		}
+	}
+
 	// Copy .env changes back to the environment file
 	if err := m.copyFile(rootEnvPath, envPath); err != nil {
 		return fmt.Errorf("failed to sync .env changes to environment '%s': %w", activeEnv, err)
 	}
 
-	color.Blue("🔄 Synced .env changes to %s/%s.env", EnvGuardDir, activeEnv)
+	if err := m.saveSnapshot(activeEnv); err != nil {
+		return err
+	}
+
+	m.emit(report.LevelInfo, "sync.pushed", "🔄", report.Fields{"environment": activeEnv}, "Synced .env changes to %s/%s.env", EnvGuardDir, activeEnv)
 	return nil
 }
 
@@ -4,6 +4,8 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+
+	"github.com/crabest/envguard/internal/report"
 )
 
 func TestNewManager(t *testing.T) {
@@ -341,3 +343,187 @@ func TestUseEnvironment(t *testing.T) {
 		t.Errorf("Expected active environment 'test', got '%s'", activeEnv)
 	}
 }
+
+func TestRenameEnvironment(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "envguard-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	manager, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+
+	if err := manager.CreateEnvironment("stage", false); err != nil {
+		t.Fatalf("Failed to create environment: %v", err)
+	}
+	if err := manager.CreateEnvironment("production", false); err != nil {
+		t.Fatalf("Failed to create environment: %v", err)
+	}
+	if err := manager.UseEnvironment("stage"); err != nil {
+		t.Fatalf("Failed to use environment: %v", err)
+	}
+
+	if err := manager.RenameEnvironment("stage", "production"); err == nil {
+		t.Error("Should not be able to rename onto an existing environment")
+	}
+
+	if err := manager.RenameEnvironment("stage", "staging"); err != nil {
+		t.Fatalf("Failed to rename environment: %v", err)
+	}
+
+	if manager.EnvironmentExists("stage") || !manager.EnvironmentExists("staging") {
+		t.Error("Environment should only exist under its new name")
+	}
+
+	activeEnv, err := manager.GetActiveEnvironment()
+	if err != nil {
+		t.Fatalf("Failed to get active environment: %v", err)
+	}
+	if activeEnv != "staging" {
+		t.Errorf("Expected active environment 'staging', got '%s'", activeEnv)
+	}
+
+	if err := manager.RenameEnvironment("staging", "../escape"); err == nil {
+		t.Error("Should reject environment names containing path separators")
+	}
+
+	// A snapshot that cannot be moved fails the rename part way through
+	blocked := filepath.Join(manager.GetSnapshotPath("qa"), "keep")
+	if err := os.MkdirAll(blocked, 0755); err != nil {
+		t.Fatalf("Failed to block snapshot path: %v", err)
+	}
+	if err := manager.RenameEnvironment("staging", "qa"); err == nil {
+		t.Fatal("Expected the rename to fail")
+	}
+
+	if !manager.EnvironmentExists("staging") || manager.EnvironmentExists("qa") {
+		t.Error("A failed rename should leave the environment under its old name")
+	}
+	if activeEnv, _ := manager.GetActiveEnvironment(); activeEnv != "staging" {
+		t.Errorf("Expected active environment to stay 'staging', got '%s'", activeEnv)
+	}
+	if _, err := manager.GetMetadata("staging"); err != nil {
+		t.Errorf("Expected metadata for 'staging' to be restored: %v", err)
+	}
+	if _, err := os.Stat(manager.GetSnapshotPath("staging")); err != nil {
+		t.Errorf("Expected the snapshot for 'staging' to be restored: %v", err)
+	}
+}
+
+func TestCreateEnvironmentRejectsInvalidNames(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	manager, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+
+	for _, name := range []string{"", "../escape", "nested/name", ".hidden"} {
+		if err := manager.CreateEnvironment(name, false); err == nil {
+			t.Errorf("Expected CreateEnvironment(%q) to fail", name)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "escape.env")); !os.IsNotExist(err) {
+		t.Error("CreateEnvironment should not write outside .envguard")
+	}
+}
+
+func TestCopyEnvironment(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "envguard-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	manager, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+
+	if err := manager.CreateEnvironment("staging", false); err != nil {
+		t.Fatalf("Failed to create environment: %v", err)
+	}
+
+	content := "DATABASE_URL=postgres://staging\n#New checkout\n  ## @type bool\nFEATURE_CHECKOUT=true\nFEATURE_SEARCH=false\n"
+	if err := os.WriteFile(manager.GetEnvPath("staging"), []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write env file: %v", err)
+	}
+
+	if err := manager.CopyEnvironment("staging", "full", nil, nil); err != nil {
+		t.Fatalf("Failed to copy environment: %v", err)
+	}
+	copied, _ := os.ReadFile(manager.GetEnvPath("full"))
+	if string(copied) != content {
+		t.Errorf("Expected full copy %q, got %q", content, string(copied))
+	}
+
+	if err := manager.CopyEnvironment("staging", "flags", []string{"FEATURE_*"}, []string{"FEATURE_SEARCH"}); err != nil {
+		t.Fatalf("Failed to copy environment: %v", err)
+	}
+	filtered, _ := os.ReadFile(manager.GetEnvPath("flags"))
+	expected := "#New checkout\n  ## @type bool\nFEATURE_CHECKOUT=true\n"
+	if string(filtered) != expected {
+		t.Errorf("Expected filtered copy %q, got %q", expected, string(filtered))
+	}
+
+	if err := manager.CopyEnvironment("staging", "full", nil, nil); err == nil {
+		t.Error("Should not be able to copy onto an existing environment")
+	}
+}
+
+func TestManagerReportsEvents(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "envguard-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	manager, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+
+	recorder := &report.Recorder{}
+	manager.SetReporter(recorder)
+
+	if err := manager.CreateEnvironment("test", false); err != nil {
+		t.Fatalf("Failed to create environment: %v", err)
+	}
+	if err := manager.UseEnvironment("test"); err != nil {
+		t.Fatalf("Failed to use environment: %v", err)
+	}
+
+	expected := []string{"store.created", "environment.created", "environment.path", "env.materialized", "environment.used"}
+	names := recorder.Names()
+	if len(names) != len(expected) {
+		t.Fatalf("Expected events %v, got %v", expected, names)
+	}
+	for i, name := range expected {
+		if names[i] != name {
+			t.Errorf("Expected event %d to be %s, got %s", i, name, names[i])
+		}
+	}
+
+	if recorder.Events[1].Fields["environment"] != "test" {
+		t.Errorf("Expected environment field, got %v", recorder.Events[1].Fields)
+	}
+}
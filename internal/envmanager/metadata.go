@@ -0,0 +1,239 @@
+package envmanager
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/crabest/envguard/internal/parser"
+)
+
+// MetadataFile is the sidecar in .envguard/ holding per-environment metadata.
+const MetadataFile = ".metadata.json"
+
+// EnvironmentMetadata describes a stored environment. Description, Tags,
+// Owner, History and the created/last-used times are persisted in the
+// sidecar; the modification time and variable count are read from the env
+// file itself.
+type EnvironmentMetadata struct {
+	Name          string         `json:"name"`
+	Description   string         `json:"description,omitempty"`
+	Tags          []string       `json:"tags,omitempty"`
+	Owner         string         `json:"owner,omitempty"`
+	CreatedAt     *time.Time     `json:"created_at,omitempty"`
+	LastUsedAt    *time.Time     `json:"last_used_at,omitempty"`
+	LastModified  *time.Time     `json:"last_modified_at,omitempty"`
+	VariableCount int            `json:"variable_count"`
+	Active        bool           `json:"active"`
+	Protected     bool           `json:"protected"`
+	History       []HistoryEvent `json:"history,omitempty"`
+}
+
+// History actions recorded for an environment.
+const (
+	HistoryCreated = "created"
+	HistoryCopied  = "copied"
+	HistoryRenamed = "renamed"
+)
+
+// HistoryEvent records how an environment came to be under its current
+// name. From names the source of a copy or the previous name of a rename.
+type HistoryEvent struct {
+	Action string    `json:"action"`
+	At     time.Time `json:"at"`
+	From   string    `json:"from,omitempty"`
+}
+
+// storedMetadata is the persisted subset of EnvironmentMetadata.
+type storedMetadata struct {
+	Description string         `json:"description,omitempty"`
+	Tags        []string       `json:"tags,omitempty"`
+	Owner       string         `json:"owner,omitempty"`
+	CreatedAt   *time.Time     `json:"created_at,omitempty"`
+	LastUsedAt  *time.Time     `json:"last_used_at,omitempty"`
+	Protected   bool           `json:"protected,omitempty"`
+	History     []HistoryEvent `json:"history,omitempty"`
+}
+
+func (m *Manager) GetMetadataPath() string {
+	return filepath.Join(m.envDir, MetadataFile)
+}
+
+// GetMetadata returns the metadata for a single environment.
+func (m *Manager) GetMetadata(envName string) (*EnvironmentMetadata, error) {
+	if !m.EnvironmentExists(envName) {
+		return nil, fmt.Errorf("environment '%s' does not exist", envName)
+	}
+
+	all, err := m.loadMetadata()
+	if err != nil {
+		return nil, err
+	}
+
+	activeEnv, _ := m.GetActiveEnvironment()
+	return m.describe(envName, all[envName], activeEnv), nil
+}
+
+// ListEnvironmentMetadata returns metadata for every stored environment,
+// sorted by name.
+func (m *Manager) ListEnvironmentMetadata() ([]EnvironmentMetadata, error) {
+	environments, err := m.ListEnvironments()
+	if err != nil {
+		return nil, err
+	}
+
+	all, err := m.loadMetadata()
+	if err != nil {
+		return nil, err
+	}
+
+	activeEnv, _ := m.GetActiveEnvironment()
+
+	result := make([]EnvironmentMetadata, 0, len(environments))
+	for _, envName := range environments {
+		result = append(result, *m.describe(envName, all[envName], activeEnv))
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result, nil
+}
+
+// UpdateMetadata applies fn to the stored description, tags and owner of
+// an environment and persists the result.
+func (m *Manager) UpdateMetadata(envName string, fn func(meta *EnvironmentMetadata)) error {
+	if !m.EnvironmentExists(envName) {
+		return fmt.Errorf("environment '%s' does not exist", envName)
+	}
+
+	return m.modifyMetadata(func(all map[string]storedMetadata) {
+		stored := all[envName]
+		meta := &EnvironmentMetadata{Description: stored.Description, Tags: stored.Tags, Owner: stored.Owner}
+		fn(meta)
+		stored.Description = meta.Description
+		stored.Tags = meta.Tags
+		stored.Owner = meta.Owner
+		all[envName] = stored
+	})
+}
+
+func (m *Manager) describe(envName string, stored storedMetadata, activeEnv string) *EnvironmentMetadata {
+	meta := &EnvironmentMetadata{
+		Name:        envName,
+		Description: stored.Description,
+		Tags:        stored.Tags,
+		Owner:       stored.Owner,
+		CreatedAt:   stored.CreatedAt,
+		LastUsedAt:  stored.LastUsedAt,
+		Active:      envName == activeEnv,
+		History:     stored.History,
+	}
+
+	// Show an environment whose protection cannot be read as protected
+	protected, err := m.IsProtected(envName)
+	meta.Protected = protected || err != nil
+
+	envPath := m.GetEnvPath(envName)
+	if info, err := os.Stat(envPath); err == nil {
+		modified := info.ModTime()
+		meta.LastModified = &modified
+	}
+
+	if vars, err := parser.ParseEnvFile(envPath); err == nil {
+		meta.VariableCount = len(vars)
+	}
+
+	return meta
+}
+
+func (m *Manager) loadMetadata() (map[string]storedMetadata, error) {
+	all := make(map[string]storedMetadata)
+
+	content, err := os.ReadFile(m.GetMetadataPath())
+	if os.IsNotExist(err) {
+		return all, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read environment metadata: %w", err)
+	}
+
+	if err := json.Unmarshal(content, &all); err != nil {
+		return nil, fmt.Errorf("failed to parse %s/%s: %w", EnvGuardDir, MetadataFile, err)
+	}
+	return all, nil
+}
+
+func (m *Manager) modifyMetadata(fn func(all map[string]storedMetadata)) error {
+	if err := m.EnsureEnvGuardDir(); err != nil {
+		return err
+	}
+
+	all, err := m.loadMetadata()
+	if err != nil {
+		return err
+	}
+
+	fn(all)
+
+	content, err := json.MarshalIndent(all, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode environment metadata: %w", err)
+	}
+
+	if err := os.WriteFile(m.GetMetadataPath(), append(content, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write environment metadata: %w", err)
+	}
+	return nil
+}
+
+// recordCreated stamps a newly created environment, optionally inheriting
+// the description, tags and owner of the environment it was copied from.
+// A copy starts its own history with a "copied" event rather than taking
+// over the source's.
+func (m *Manager) recordCreated(envName, copiedFrom string) error {
+	now := time.Now().UTC()
+	return m.modifyMetadata(func(all map[string]storedMetadata) {
+		stored := storedMetadata{
+			CreatedAt: &now,
+			History:   []HistoryEvent{{Action: HistoryCreated, At: now}},
+		}
+		if copiedFrom != "" {
+			stored.History = []HistoryEvent{{Action: HistoryCopied, At: now, From: copiedFrom}}
+		}
+		if source, ok := all[copiedFrom]; ok && copiedFrom != "" {
+			stored.Description = source.Description
+			stored.Tags = append([]string{}, source.Tags...)
+			stored.Owner = source.Owner
+		}
+		all[envName] = stored
+	})
+}
+
+func (m *Manager) recordUsed(envName string) error {
+	now := time.Now().UTC()
+	return m.modifyMetadata(func(all map[string]storedMetadata) {
+		stored := all[envName]
+		stored.LastUsedAt = &now
+		all[envName] = stored
+	})
+}
+
+// recordRenamed moves an environment's metadata and history to its new
+// name and appends the rename to the history.
+func (m *Manager) recordRenamed(oldName, newName string) error {
+	now := time.Now().UTC()
+	return m.modifyMetadata(func(all map[string]storedMetadata) {
+		stored := all[oldName]
+		stored.History = append(stored.History, HistoryEvent{Action: HistoryRenamed, At: now, From: oldName})
+		all[newName] = stored
+		delete(all, oldName)
+	})
+}
+
+func (m *Manager) recordDeleted(envName string) error {
+	return m.modifyMetadata(func(all map[string]storedMetadata) {
+		delete(all, envName)
+	})
+}
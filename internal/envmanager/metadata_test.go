@@ -0,0 +1,98 @@
+package envmanager
+
+import (
+	"os"
+	"testing"
+)
+
+func TestEnvironmentMetadata(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "envguard-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	manager, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+
+	if err := manager.CreateEnvironment("stage", false); err != nil {
+		t.Fatalf("Failed to create environment: %v", err)
+	}
+	os.WriteFile(manager.GetEnvPath("stage"), []byte("A=1\nB=2\n"), 0644)
+
+	err = manager.UpdateMetadata("stage", func(meta *EnvironmentMetadata) {
+		meta.Description = "Shared staging"
+		meta.Tags = []string{"backend"}
+		meta.Owner = "platform"
+	})
+	if err != nil {
+		t.Fatalf("Failed to update metadata: %v", err)
+	}
+
+	meta, err := manager.GetMetadata("stage")
+	if err != nil {
+		t.Fatalf("Failed to get metadata: %v", err)
+	}
+	if meta.Description != "Shared staging" || meta.Owner != "platform" || len(meta.Tags) != 1 {
+		t.Errorf("Unexpected metadata: %+v", meta)
+	}
+	if meta.CreatedAt == nil || meta.LastUsedAt != nil || meta.LastModified == nil {
+		t.Errorf("Unexpected timestamps: %+v", meta)
+	}
+	if meta.VariableCount != 2 {
+		t.Errorf("Expected 2 variables, got %d", meta.VariableCount)
+	}
+
+	if err := manager.UseEnvironment("stage"); err != nil {
+		t.Fatalf("Failed to use environment: %v", err)
+	}
+	if err := manager.RenameEnvironment("stage", "staging"); err != nil {
+		t.Fatalf("Failed to rename environment: %v", err)
+	}
+
+	meta, err = manager.GetMetadata("staging")
+	if err != nil {
+		t.Fatalf("Failed to get metadata: %v", err)
+	}
+	if meta.Description != "Shared staging" || meta.LastUsedAt == nil || !meta.Active {
+		t.Errorf("Metadata should follow the renamed environment: %+v", meta)
+	}
+	if len(meta.History) != 2 || meta.History[0].Action != HistoryCreated ||
+		meta.History[1].Action != HistoryRenamed || meta.History[1].From != "stage" {
+		t.Errorf("Expected created then renamed history, got %+v", meta.History)
+	}
+
+	if err := manager.CopyEnvironment("staging", "qa", nil, nil); err != nil {
+		t.Fatalf("Failed to copy environment: %v", err)
+	}
+	copied, _ := manager.GetMetadata("qa")
+	if copied.Owner != "platform" || copied.LastUsedAt != nil || copied.Active {
+		t.Errorf("Copy should inherit description fields only: %+v", copied)
+	}
+	if len(copied.History) != 1 || copied.History[0].Action != HistoryCopied || copied.History[0].From != "staging" {
+		t.Errorf("Copy should start its own history, got %+v", copied.History)
+	}
+
+	if err := manager.DeleteEnvironment("qa", false); err != nil {
+		t.Fatalf("Failed to delete environment: %v", err)
+	}
+
+	all, err := manager.ListEnvironmentMetadata()
+	if err != nil {
+		t.Fatalf("Failed to list metadata: %v", err)
+	}
+	if len(all) != 1 || all[0].Name != "staging" {
+		t.Errorf("Expected only staging, got %+v", all)
+	}
+
+	stored, _ := manager.loadMetadata()
+	if _, ok := stored["qa"]; ok {
+		t.Error("Deleted environment should be removed from the sidecar")
+	}
+}
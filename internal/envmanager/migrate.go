@@ -0,0 +1,294 @@
+package envmanager
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/crabest/envguard/internal/parser"
+	"github.com/crabest/envguard/internal/report"
+)
+
+// MigrationKind classifies a single change made by a migration.
+type MigrationKind string
+
+const (
+	// MigrationRenamed rewrites an old key name to its replacement.
+	MigrationRenamed MigrationKind = "renamed"
+	// MigrationRemoved deletes a deprecated key.
+	MigrationRemoved MigrationKind = "removed"
+	// MigrationDropped deletes an old key whose replacement is already set
+	// to the same value.
+	MigrationDropped MigrationKind = "dropped"
+	// MigrationConflict leaves an old key in place because its replacement
+	// is already set to a different value.
+	MigrationConflict MigrationKind = "conflict"
+)
+
+// MigrationChange is one key-level change in a file.
+type MigrationChange struct {
+	Kind   MigrationKind
+	Key    string
+	NewKey string
+	Value  string
+	Line   int
+}
+
+// FileMigration is the rewrite planned for one dotenv file: the root .env
+// (Environment is empty) or a stored environment.
+type FileMigration struct {
+	Environment string
+	Path        string
+	Changes     []MigrationChange
+
+	content string
+}
+
+// HasChanges reports whether applying the migration would modify the file.
+func (f *FileMigration) HasChanges() bool {
+	for _, change := range f.Changes {
+		if change.Kind != MigrationConflict {
+			return true
+		}
+	}
+	return false
+}
+
+// PlanMigration works out how renames (old name → new name) and the
+// removal of keys in remove would rewrite the root .env and every stored
+// environment. Files without changes are left out.
+func (m *Manager) PlanMigration(renames map[string]string, remove []string) ([]*FileMigration, error) {
+	var migrations []*FileMigration
+
+	if _, err := os.Stat(m.GetRootEnvPath()); err == nil {
+		migration, err := planFileMigration(m.GetRootEnvPath(), renames, remove)
+		if err != nil {
+			return nil, fmt.Errorf("failed to plan migration of .env: %w", err)
+		}
+		migration.Path = ".env"
+		if len(migration.Changes) > 0 {
+			migrations = append(migrations, migration)
+		}
+	}
+
+	envs, err := m.ListEnvironments()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, envName := range envs {
+		migration, err := planFileMigration(m.GetEnvPath(envName), renames, remove)
+		if err != nil {
+			return nil, fmt.Errorf("failed to plan migration of environment '%s': %w", envName, err)
+		}
+		migration.Environment = envName
+		migration.Path = filepath.Join(EnvGuardDir, envName+".env")
+		if len(migration.Changes) > 0 {
+			migrations = append(migrations, migration)
+		}
+	}
+
+	return migrations, nil
+}
+
+// Migrate previews migrations as a masked diff and, unless dryRun is set,
+// writes them after confirmation. Snapshots are migrated along with their
+// environments so drift detection is unaffected.
+func (m *Manager) Migrate(migrations []*FileMigration, dryRun bool) error {
+	changed := 0
+	for _, migration := range migrations {
+		if migration.HasChanges() {
+			changed++
+		}
+	}
+
+	if len(migrations) == 0 {
+		m.emit(report.LevelSuccess, "migrate.nothing", "✅", nil, "All environments are up to date")
+		return nil
+	}
+
+	m.reportMigration(migrations, !dryRun && changed > 0)
+
+	if dryRun || changed == 0 {
+		if dryRun {
+			m.emit(report.LevelInfo, "migrate.dry_run", "💡", nil, "Dry run: no files were changed")
+		}
+		return nil
+	}
+
+	ok, err := m.getPrompter().Confirm(fmt.Sprintf("Rewrite %d files?", changed), false)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		m.emit(report.LevelInfo, "migrate.cancelled", "ℹ️ ", nil, "Migration cancelled")
+		return nil
+	}
+
+	for _, migration := range migrations {
+		if migration.Environment != "" && migration.HasChanges() {
+			if err := m.requireProtectedConfirmation(migration.Environment, "migrate"); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, migration := range migrations {
+		if !migration.HasChanges() {
+			continue
+		}
+		if err := m.applyMigration(migration); err != nil {
+			return err
+		}
+	}
+
+	m.emit(report.LevelSuccess, "migrate.applied", "✅", report.Fields{"files": changed}, "Migrated %d files", changed)
+	return nil
+}
+
+func (m *Manager) applyMigration(migration *FileMigration) error {
+	if migration.Environment == "" {
+		if err := m.makeRootEnvWritable(); err != nil {
+			return fmt.Errorf("failed to make .env writable: %w", err)
+		}
+		if err := os.WriteFile(m.GetRootEnvPath(), []byte(migration.content), 0644); err != nil {
+			return fmt.Errorf("failed to write .env: %w", err)
+		}
+		m.rootEnvWritten()
+		if activeEnv, err := m.GetActiveEnvironment(); err == nil {
+			return m.setRootEnvReadOnly(activeEnv)
+		}
+		return nil
+	}
+
+	if err := os.WriteFile(m.GetEnvPath(migration.Environment), []byte(migration.content), 0644); err != nil {
+		return fmt.Errorf("failed to write environment '%s': %w", migration.Environment, err)
+	}
+
+	snapshotPath := m.GetSnapshotPath(migration.Environment)
+	if _, err := os.Stat(snapshotPath); err != nil {
+		return nil
+	}
+
+	var renames = make(map[string]string)
+	var remove []string
+	for _, change := range migration.Changes {
+		switch change.Kind {
+		case MigrationRenamed, MigrationDropped:
+			renames[change.Key] = change.NewKey
+		case MigrationRemoved:
+			remove = append(remove, change.Key)
+		}
+	}
+
+	snapshot, err := planFileMigration(snapshotPath, renames, remove)
+	if err != nil {
+		return fmt.Errorf("failed to migrate snapshot for '%s': %w", migration.Environment, err)
+	}
+	if err := os.WriteFile(snapshotPath, []byte(snapshot.content), 0644); err != nil {
+		return fmt.Errorf("failed to migrate snapshot for '%s': %w", migration.Environment, err)
+	}
+	return nil
+}
+
+// planFileMigration rewrites the file at path in memory. Renamed keys keep
+// their value, position and formatting.
+func planFileMigration(path string, renames map[string]string, remove []string) (*FileMigration, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := parser.ParseEntries(content)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string)
+	for _, entry := range entries {
+		values[entry.Key] = entry.Value
+	}
+
+	removed := make(map[string]bool)
+	for _, key := range remove {
+		removed[key] = true
+	}
+
+	migration := &FileMigration{}
+	lines := strings.Split(strings.TrimRight(string(content), "\n"), "\n")
+	if len(content) == 0 {
+		lines = nil
+	}
+
+	// Walk backwards so earlier line numbers stay valid as spans change size
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+		change := MigrationChange{Key: entry.Key, Value: entry.Value, Line: entry.Line}
+
+		switch newKey, renamed := renames[entry.Key]; {
+		case removed[entry.Key]:
+			change.Kind = MigrationRemoved
+			lines = append(lines[:entry.Line-1], lines[entry.EndLine:]...)
+		case renamed:
+			change.NewKey = newKey
+			current, exists := values[newKey]
+			switch {
+			case !exists:
+				change.Kind = MigrationRenamed
+				raw := entry.Raw[:entry.Column] + newKey + entry.Raw[entry.Column+len(entry.Key):]
+				lines = append(lines[:entry.Line-1], append(strings.Split(raw, "\n"), lines[entry.EndLine:]...)...)
+				values[newKey] = entry.Value
+			case current == entry.Value:
+				change.Kind = MigrationDropped
+				lines = append(lines[:entry.Line-1], lines[entry.EndLine:]...)
+			default:
+				change.Kind = MigrationConflict
+			}
+		default:
+			continue
+		}
+
+		migration.Changes = append(migration.Changes, change)
+	}
+
+	sort.Slice(migration.Changes, func(a, b int) bool {
+		return migration.Changes[a].Line < migration.Changes[b].Line
+	})
+
+	migration.content = strings.Join(lines, "\n")
+	if migration.content != "" {
+		migration.content += "\n"
+	}
+	return migration, nil
+}
+
+func (m *Manager) reportMigration(migrations []*FileMigration, pending bool) {
+	p := m.preview(pending)
+	p.Heading("migrate.plan", "🧭", nil, "Migration plan:")
+
+	for _, migration := range migrations {
+		p.Note("migrate.file", "📄", report.Fields{"path": migration.Path, "environment": migration.Environment}, "%s", migration.Path)
+
+		for _, change := range migration.Changes {
+			// Each event is one line of the diff; op tells removed from added
+			fields := func(op string) report.Fields {
+				return report.Fields{"path": migration.Path, "op": op, "key": change.Key, "new_key": change.NewKey, "change": string(change.Kind), "line": change.Line}
+			}
+			value := MaskValue(change.Value)
+
+			switch change.Kind {
+			case MigrationRenamed:
+				p.Item(report.StyleRemoved, "migrate.diff", fields("-"), "- %s=%s", change.Key, value)
+				p.Item(report.StyleAdded, "migrate.diff", fields("+"), "+ %s=%s", change.NewKey, value)
+			case MigrationRemoved:
+				p.Item(report.StyleRemoved, "migrate.diff", fields("-"), "- %s=%s (deprecated)", change.Key, value)
+			case MigrationDropped:
+				p.Item(report.StyleRemoved, "migrate.diff", fields("-"), "- %s=%s (%s already set)", change.Key, value, change.NewKey)
+			case MigrationConflict:
+				p.Item(report.StyleChanged, "migrate.conflict", fields("!"), "! %s kept: %s is already set to a different value", change.Key, change.NewKey)
+			}
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package envmanager
+
+import (
+	"os"
+	"testing"
+)
+
+func TestMigrateRenamesAcrossEnvironments(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "envguard-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	manager, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	manager.SetPrompter(AnswerPrompter{Answer: true})
+
+	for _, env := range []string{"dev", "staging", "prod"} {
+		if err := manager.CreateEnvironment(env, false); err != nil {
+			t.Fatalf("Failed to create environment: %v", err)
+		}
+	}
+	os.WriteFile(manager.GetEnvPath("dev"), []byte("# cache\nexport REDIS_URL=\"redis://dev\" # local\nOLD=1\nAPP=x\n"), 0644)
+	os.WriteFile(manager.GetEnvPath("staging"), []byte("REDIS_URL=redis://a\nCACHE_URL=redis://a\n"), 0644)
+	os.WriteFile(manager.GetEnvPath("prod"), []byte("REDIS_URL=redis://old\nCACHE_URL=redis://new\n"), 0644)
+
+	if err := manager.UseEnvironment("dev"); err != nil {
+		t.Fatalf("Failed to use environment: %v", err)
+	}
+
+	migrations, err := manager.PlanMigration(map[string]string{"REDIS_URL": "CACHE_URL"}, []string{"OLD"})
+	if err != nil {
+		t.Fatalf("Failed to plan migration: %v", err)
+	}
+	if len(migrations) != 4 {
+		t.Fatalf("Expected .env and 3 environments to be planned, got %d", len(migrations))
+	}
+
+	if err := manager.Migrate(migrations, true); err != nil {
+		t.Fatalf("Dry run failed: %v", err)
+	}
+	content, _ := os.ReadFile(manager.GetEnvPath("dev"))
+	if string(content) != "# cache\nexport REDIS_URL=\"redis://dev\" # local\nOLD=1\nAPP=x\n" {
+		t.Errorf("Dry run should not write, got %q", string(content))
+	}
+
+	if err := manager.Migrate(migrations, false); err != nil {
+		t.Fatalf("Migration failed: %v", err)
+	}
+
+	expected := "# cache\nexport CACHE_URL=\"redis://dev\" # local\nAPP=x\n"
+	for _, path := range []string{manager.GetEnvPath("dev"), manager.GetRootEnvPath(), manager.GetSnapshotPath("dev")} {
+		content, _ := os.ReadFile(path)
+		if string(content) != expected {
+			t.Errorf("Expected %s to be migrated to %q, got %q", path, expected, string(content))
+		}
+	}
+
+	content, _ = os.ReadFile(manager.GetEnvPath("staging"))
+	if string(content) != "CACHE_URL=redis://a\n" {
+		t.Errorf("Expected duplicate old key to be dropped, got %q", string(content))
+	}
+
+	content, _ = os.ReadFile(manager.GetEnvPath("prod"))
+	if string(content) != "REDIS_URL=redis://old\nCACHE_URL=redis://new\n" {
+		t.Errorf("Expected conflicting keys to be kept, got %q", string(content))
+	}
+
+	drift, err := manager.DetectDrift()
+	if err != nil {
+		t.Fatalf("Failed to detect drift: %v", err)
+	}
+	if drift.State != DriftInSync {
+		t.Errorf("Expected migration to keep .env in sync, got %s", drift)
+	}
+}
+
+func TestMigrateRenamesOnlyTheKey(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "envguard-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	manager, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	manager.SetPrompter(AnswerPrompter{Answer: true})
+	if err := manager.CreateEnvironment("dev", false); err != nil {
+		t.Fatalf("Failed to create environment: %v", err)
+	}
+	original := "export port=port # the port\n  export ex=export\nsupport=port\n"
+	os.WriteFile(manager.GetEnvPath("dev"), []byte(original), 0644)
+
+	migrations, err := manager.PlanMigration(map[string]string{"port": "CACHE_PORT", "ex": "EX"}, nil)
+	if err != nil {
+		t.Fatalf("Failed to plan migration: %v", err)
+	}
+	if err := manager.Migrate(migrations, false); err != nil {
+		t.Fatalf("Migration failed: %v", err)
+	}
+
+	expected := "export CACHE_PORT=port # the port\n  export EX=export\nsupport=port\n"
+	content, _ := os.ReadFile(manager.GetEnvPath("dev"))
+	if string(content) != expected {
+		t.Errorf("Expected %q, got %q", expected, string(content))
+	}
+}
@@ -0,0 +1,266 @@
+package envmanager
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/crabest/envguard/internal/parser"
+	"github.com/crabest/envguard/internal/report"
+)
+
+// Promotion is the set of changes promoting keys from one environment to
+// another would make.
+type Promotion struct {
+	Source  string
+	Target  string
+	Added   []parser.Entry
+	Changed []parser.Entry
+	Blocked []string
+}
+
+// HasChanges reports whether applying the promotion would modify the target.
+func (p *Promotion) HasChanges() bool {
+	return len(p.Added) > 0 || len(p.Changed) > 0
+}
+
+// PlanPromotion works out which keys matching patterns differ between src
+// and dst. Keys for which envSpecific returns true are never promoted.
+func (m *Manager) PlanPromotion(src, dst string, patterns []string, envSpecific func(string) bool) (*Promotion, error) {
+	if !m.EnvironmentExists(src) {
+		return nil, fmt.Errorf("environment '%s' does not exist", src)
+	}
+	if !m.EnvironmentExists(dst) {
+		return nil, fmt.Errorf("environment '%s' does not exist", dst)
+	}
+	if src == dst {
+		return nil, fmt.Errorf("source and target environments are the same")
+	}
+
+	srcEntries, err := parser.ParseEnvFileEntries(m.GetEnvPath(src))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse environment '%s': %w", src, err)
+	}
+
+	dstVars, err := parser.ParseEnvFile(m.GetEnvPath(dst))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse environment '%s': %w", dst, err)
+	}
+
+	promotion := &Promotion{Source: src, Target: dst}
+	for _, entry := range srcEntries {
+		if !MatchesKeyPattern(entry.Key, patterns) {
+			continue
+		}
+
+		current, exists := dstVars[entry.Key]
+		if exists && current == entry.Value {
+			continue
+		}
+
+		if envSpecific != nil && envSpecific(entry.Key) {
+			promotion.Blocked = append(promotion.Blocked, entry.Key)
+			continue
+		}
+
+		if exists {
+			promotion.Changed = append(promotion.Changed, entry)
+		} else {
+			promotion.Added = append(promotion.Added, entry)
+		}
+	}
+
+	return promotion, nil
+}
+
+// PromoteKeys previews a promotion with masked values and, after
+// confirmation when confirm is set, writes it into the target environment.
+func (m *Manager) PromoteKeys(promotion *Promotion, confirm bool) error {
+	pending := promotion.HasChanges() && (confirm || m.needsProtectedConfirmation(promotion.Target))
+	m.reportPromotion(promotion, pending)
+
+	if !promotion.HasChanges() {
+		m.emit(report.LevelInfo, "promote.nothing", "ℹ️ ", nil, "Nothing to promote")
+		return nil
+	}
+
+	if err := m.requireProtectedConfirmation(promotion.Target, "modify"); err != nil {
+		return err
+	}
+
+	if confirm {
+		ok, err := m.getPrompter().Confirm(fmt.Sprintf("Apply these changes to '%s'?", promotion.Target), false)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			m.emit(report.LevelInfo, "promote.cancelled", "ℹ️ ", nil, "Promotion cancelled")
+			return nil
+		}
+	}
+
+	if err := m.guardLocalEdits(promotion.Target); err != nil {
+		return err
+	}
+
+	updates := append(append([]parser.Entry{}, promotion.Changed...), promotion.Added...)
+	if err := m.writeEntries(promotion.Target, updates); err != nil {
+		return err
+	}
+
+	// Keep the root .env in step so the next sync does not undo the promotion
+	if err := m.refreshActive(promotion.Target); err != nil {
+		return err
+	}
+
+	m.emit(report.LevelSuccess, "promote.applied", "✅", report.Fields{"from": promotion.Source, "to": promotion.Target, "count": len(updates)},
+		"Promoted %d variables from %s → %s", len(updates), promotion.Source, promotion.Target)
+	return nil
+}
+
+// guardLocalEdits is called before envName's stored file is changed
+// directly. When envName is active and the root .env has edits that were
+// never synced, refreshActive would overwrite them, so the user must agree
+// to discard them first.
+func (m *Manager) guardLocalEdits(envName string) error {
+	activeEnv, err := m.GetActiveEnvironment()
+	if err != nil || activeEnv != envName {
+		return nil
+	}
+	if _, err := os.Stat(m.GetRootEnvPath()); os.IsNotExist(err) {
+		return nil
+	}
+
+	drift, err := m.DetectDrift()
+	if err != nil {
+		return err
+	}
+	if len(drift.LocalKeys) == 0 {
+		return nil
+	}
+
+	m.emit(report.LevelWarning, "env.local_edits", "⚠️ ", report.Fields{"environment": envName, "keys": drift.LocalKeys},
+		".env has unsynced edits to %s", strings.Join(drift.LocalKeys, ", "))
+	ok, err := m.getPrompter().Confirm(fmt.Sprintf("Discard them and update .env from '%s'?", envName), false)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf(".env has unsynced edits; run 'envguard sync' to save them into '%s' first", envName)
+	}
+	return nil
+}
+
+// refreshActive copies envName over the root .env when it is the active
+// environment, after its stored file was changed directly. Callers check
+// guardLocalEdits before changing the stored file.
+func (m *Manager) refreshActive(envName string) error {
+	activeEnv, err := m.GetActiveEnvironment()
+	if err != nil || activeEnv != envName {
+		return nil
+	}
+
+	if err := m.makeRootEnvWritable(); err != nil {
+		return fmt.Errorf("failed to make .env writable: %w", err)
+	}
+	if err := m.copyFile(m.GetEnvPath(envName), m.GetRootEnvPath()); err != nil {
+		return fmt.Errorf("failed to update active .env: %w", err)
+	}
+	m.rootEnvWritten()
+	if err := m.saveSnapshot(envName); err != nil {
+		return err
+	}
+	m.emit(report.LevelInfo, "env.materialized", "📁", report.Fields{"environment": envName}, "Active .env file updated from %s/%s.env", EnvGuardDir, envName)
+	return m.setRootEnvReadOnly(envName)
+}
+
+// writeEntries replaces the matching assignments in envName with the given
+// entries, appending any keys the environment does not have yet.
+func (m *Manager) writeEntries(envName string, updates []parser.Entry) error {
+	envPath := m.GetEnvPath(envName)
+
+	content, err := os.ReadFile(envPath)
+	if err != nil {
+		return fmt.Errorf("failed to read environment '%s': %w", envName, err)
+	}
+
+	existing, err := parser.ParseEntries(content)
+	if err != nil {
+		return fmt.Errorf("failed to parse environment '%s': %w", envName, err)
+	}
+
+	byKey := make(map[string]parser.Entry)
+	for _, update := range updates {
+		byKey[update.Key] = update
+	}
+
+	lines := strings.Split(strings.TrimRight(string(content), "\n"), "\n")
+	if len(content) == 0 {
+		lines = nil
+	}
+
+	// Walk backwards so earlier line numbers stay valid as spans change size
+	for i := len(existing) - 1; i >= 0; i-- {
+		entry := existing[i]
+		update, ok := byKey[entry.Key]
+		if !ok {
+			continue
+		}
+		replacement := strings.Split(update.Raw, "\n")
+		lines = append(lines[:entry.Line-1], append(replacement, lines[entry.EndLine:]...)...)
+		delete(byKey, entry.Key)
+	}
+
+	var appended []parser.Entry
+	for _, update := range updates {
+		if _, ok := byKey[update.Key]; ok {
+			appended = append(appended, update)
+		}
+	}
+
+	output := strings.Join(lines, "\n")
+	if output != "" {
+		output += "\n"
+	}
+	output += renderEntries(appended)
+
+	if err := os.WriteFile(envPath, []byte(output), 0644); err != nil {
+		return fmt.Errorf("failed to write environment '%s': %w", envName, err)
+	}
+	return nil
+}
+
+func (m *Manager) reportPromotion(promotion *Promotion, pending bool) {
+	p := m.preview(pending)
+	p.Heading("promote.plan", "🚀", report.Fields{"from": promotion.Source, "to": promotion.Target},
+		"Promote %s → %s:", promotion.Source, promotion.Target)
+
+	for _, entry := range promotion.Added {
+		p.Item(report.StyleAdded, "promote.key", report.Fields{"key": entry.Key, "op": "+", "change": "added", "value": MaskValue(entry.Value)},
+			"+ %s=%s", entry.Key, MaskValue(entry.Value))
+	}
+	for _, entry := range promotion.Changed {
+		p.Item(report.StyleChanged, "promote.key", report.Fields{"key": entry.Key, "op": "~", "change": "changed", "value": MaskValue(entry.Value)},
+			"~ %s=%s", entry.Key, MaskValue(entry.Value))
+	}
+	for _, key := range promotion.Blocked {
+		p.Item(report.StyleRemoved, "promote.key", report.Fields{"key": key, "op": "✗", "change": "blocked"},
+			"✗ %s (environment-specific, not promoted)", key)
+	}
+
+	p.Note("promote.summary", "📊",
+		report.Fields{"added": len(promotion.Added), "changed": len(promotion.Changed), "blocked": len(promotion.Blocked)},
+		"%d added • %d changed • %d blocked", len(promotion.Added), len(promotion.Changed), len(promotion.Blocked))
+}
+
+// MaskValue hides all but a short prefix of a value for previews.
+func MaskValue(value string) string {
+	if value == "" {
+		return `""`
+	}
+	runes := []rune(value)
+	if len(runes) <= 4 {
+		return "****"
+	}
+	return string(runes[:2]) + strings.Repeat("*", 6)
+}
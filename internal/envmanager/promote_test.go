@@ -0,0 +1,145 @@
+package envmanager
+
+import (
+	"os"
+	"testing"
+
+	"github.com/crabest/envguard/internal/report"
+)
+
+func TestPromoteKeys(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "envguard-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	manager, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+
+	for _, env := range []string{"staging", "production"} {
+		if err := manager.CreateEnvironment(env, false); err != nil {
+			t.Fatalf("Failed to create environment: %v", err)
+		}
+	}
+
+	staging := "DATABASE_URL=postgres://staging\nFEATURE_CHECKOUT=true\nFEATURE_SEARCH=on\nFEATURE_DATA=x\n"
+	production := "DATABASE_URL=postgres://prod\nFEATURE_SEARCH=off\nFEATURE_DATA=y\nLOG_LEVEL=warn\n"
+	os.WriteFile(manager.GetEnvPath("staging"), []byte(staging), 0644)
+	os.WriteFile(manager.GetEnvPath("production"), []byte(production), 0644)
+
+	envSpecific := func(key string) bool { return key == "DATABASE_URL" || key == "FEATURE_DATA" }
+
+	promotion, err := manager.PlanPromotion("staging", "production", []string{"FEATURE_*", "DATABASE_URL"}, envSpecific)
+	if err != nil {
+		t.Fatalf("Failed to plan promotion: %v", err)
+	}
+
+	if len(promotion.Added) != 1 || promotion.Added[0].Key != "FEATURE_CHECKOUT" {
+		t.Errorf("Expected FEATURE_CHECKOUT to be added, got %+v", promotion.Added)
+	}
+	if len(promotion.Changed) != 1 || promotion.Changed[0].Key != "FEATURE_SEARCH" {
+		t.Errorf("Expected FEATURE_SEARCH to be changed, got %+v", promotion.Changed)
+	}
+	if len(promotion.Blocked) != 2 {
+		t.Errorf("Expected 2 blocked keys, got %v", promotion.Blocked)
+	}
+
+	recorder := &report.Recorder{}
+	manager.SetReporter(recorder)
+	if err := manager.PromoteKeys(promotion, false); err != nil {
+		t.Fatalf("Failed to promote keys: %v", err)
+	}
+
+	// Every preview line shares one level; the op field tells them apart
+	for _, event := range recorder.Events {
+		if event.Name == "promote.key" && (event.Level != report.LevelInfo || event.Fields["op"] == nil) {
+			t.Errorf("Unexpected preview event: %+v", event)
+		}
+	}
+
+	content, _ := os.ReadFile(manager.GetEnvPath("production"))
+	expected := "DATABASE_URL=postgres://prod\nFEATURE_SEARCH=on\nFEATURE_DATA=y\nLOG_LEVEL=warn\nFEATURE_CHECKOUT=true\n"
+	if string(content) != expected {
+		t.Errorf("Expected production %q, got %q", expected, string(content))
+	}
+}
+
+func TestPromoteKeysGuardsLocalEdits(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "envguard-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	manager, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	manager.DisableImplicitSync()
+
+	for _, env := range []string{"staging", "production"} {
+		if err := manager.CreateEnvironment(env, false); err != nil {
+			t.Fatalf("Failed to create environment: %v", err)
+		}
+	}
+	os.WriteFile(manager.GetEnvPath("staging"), []byte("FEATURE=on\n"), 0644)
+	os.WriteFile(manager.GetEnvPath("production"), []byte("FEATURE=off\n"), 0644)
+
+	if err := manager.UseEnvironment("production"); err != nil {
+		t.Fatalf("Failed to use environment: %v", err)
+	}
+	os.Chmod(manager.GetRootEnvPath(), 0644)
+	edited := "FEATURE=off\nLOCAL_ONLY=1\n"
+	os.WriteFile(manager.GetRootEnvPath(), []byte(edited), 0644)
+
+	promotion, err := manager.PlanPromotion("staging", "production", []string{"FEATURE"}, nil)
+	if err != nil {
+		t.Fatalf("Failed to plan promotion: %v", err)
+	}
+
+	manager.SetPrompter(AnswerPrompter{Answer: false})
+	if err := manager.PromoteKeys(promotion, false); err == nil {
+		t.Error("Expected promotion to refuse while .env has unsynced edits")
+	}
+	if content, _ := os.ReadFile(manager.GetRootEnvPath()); string(content) != edited {
+		t.Errorf("Local edits should be kept, got %q", string(content))
+	}
+	if content, _ := os.ReadFile(manager.GetEnvPath("production")); string(content) != "FEATURE=off\n" {
+		t.Errorf("Production should be unchanged, got %q", string(content))
+	}
+
+	manager.SetPrompter(AnswerPrompter{Answer: true})
+	if err := manager.PromoteKeys(promotion, false); err != nil {
+		t.Fatalf("Failed to promote keys: %v", err)
+	}
+	if content, _ := os.ReadFile(manager.GetRootEnvPath()); string(content) != "FEATURE=on\n" {
+		t.Errorf("Expected .env to follow production, got %q", string(content))
+	}
+}
+
+func TestMaskValue(t *testing.T) {
+	cases := map[string]string{
+		"":                    `""`,
+		"abc":                 "****",
+		"supersecretpassword": "su******",
+		"äöüß":                "****",
+		"äöüßen":              "äö******",
+	}
+
+	for value, expected := range cases {
+		if got := MaskValue(value); got != expected {
+			t.Errorf("MaskValue(%q) = %q, want %q", value, got, expected)
+		}
+	}
+}
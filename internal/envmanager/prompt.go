@@ -0,0 +1,128 @@
+package envmanager
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/mattn/go-isatty"
+)
+
+// NonInteractiveEnv disables prompts when set to a truthy value.
+const NonInteractiveEnv = "ENVGUARD_NONINTERACTIVE"
+
+// ErrNonInteractive is returned when a prompt is needed but no user can answer it.
+var ErrNonInteractive = errors.New("input required but envguard is running non-interactively")
+
+// Prompter asks the user questions on behalf of the Manager.
+type Prompter interface {
+	// Confirm asks a yes/no question. defaultYes is the answer to an empty reply.
+	Confirm(question string, defaultYes bool) (bool, error)
+	// Input asks for a line of free text.
+	Input(question string) (string, error)
+}
+
+// TerminalPrompter reads answers line by line from a reader, normally
+// stdin, writing questions to out so they never mix with command output.
+type TerminalPrompter struct {
+	reader *bufio.Reader
+	out    io.Writer
+}
+
+func NewTerminalPrompter(in io.Reader, out io.Writer) *TerminalPrompter {
+	return &TerminalPrompter{reader: bufio.NewReader(in), out: out}
+}
+
+func (p *TerminalPrompter) Confirm(question string, defaultYes bool) (bool, error) {
+	hint := "(y/N)"
+	if defaultYes {
+		hint = "(Y/n)"
+	}
+
+	response, err := p.Input(question + " " + hint)
+	if err != nil {
+		return false, err
+	}
+
+	response = strings.ToLower(response)
+	if response == "" {
+		return defaultYes, nil
+	}
+	return response == "y" || response == "yes", nil
+}
+
+func (p *TerminalPrompter) Input(question string) (string, error) {
+	fmt.Fprint(p.out, color.YellowString("❓ %s: ", question))
+	response, err := p.reader.ReadString('\n')
+	if err != nil && (err != io.EOF || response == "") {
+		return "", fmt.Errorf("failed to read user input: %w", err)
+	}
+	return strings.TrimSpace(response), nil
+}
+
+// AnswerPrompter answers every confirmation the same way, as --yes and
+// --no do. Free-text input cannot be answered and fails fast.
+type AnswerPrompter struct {
+	Answer bool
+}
+
+func (p AnswerPrompter) Confirm(question string, defaultYes bool) (bool, error) {
+	return p.Answer, nil
+}
+
+func (p AnswerPrompter) Input(question string) (string, error) {
+	return "", fmt.Errorf("%w: %s", ErrNonInteractive, question)
+}
+
+// NonInteractivePrompter fails every prompt instead of blocking.
+type NonInteractivePrompter struct{}
+
+func (NonInteractivePrompter) Confirm(question string, defaultYes bool) (bool, error) {
+	return false, fmt.Errorf("%w: %s (use --yes or --no)", ErrNonInteractive, question)
+}
+
+func (NonInteractivePrompter) Input(question string) (string, error) {
+	return "", fmt.Errorf("%w: %s", ErrNonInteractive, question)
+}
+
+// DefaultPrompter prompts on the terminal when stdin is a TTY and
+// ENVGUARD_NONINTERACTIVE is unset, and fails fast otherwise.
+func DefaultPrompter() Prompter {
+	if IsNonInteractive() {
+		return NonInteractivePrompter{}
+	}
+	return NewTerminalPrompter(os.Stdin, os.Stderr)
+}
+
+// IsNonInteractive reports whether prompts must not be shown.
+func IsNonInteractive() bool {
+	switch strings.ToLower(os.Getenv(NonInteractiveEnv)) {
+	case "1", "true", "yes":
+		return true
+	}
+
+	fd := os.Stdin.Fd()
+	return !isatty.IsTerminal(fd) && !isatty.IsCygwinTerminal(fd)
+}
+
+// SetPrompter replaces the prompter used for confirmations.
+func (m *Manager) SetPrompter(prompter Prompter) {
+	m.prompter = prompter
+}
+
+func (m *Manager) getPrompter() Prompter {
+	if m.prompter == nil {
+		m.prompter = DefaultPrompter()
+	}
+	return m.prompter
+}
+
+// Confirm asks a yes/no question through the configured prompter, so
+// commands honour --yes, --no and non-interactive mode.
+func (m *Manager) Confirm(question string, defaultYes bool) (bool, error) {
+	return m.getPrompter().Confirm(question, defaultYes)
+}
@@ -0,0 +1,189 @@
+package envmanager
+
+import (
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+// fakePrompter replays scripted answers and records the questions asked.
+type fakePrompter struct {
+	confirms []bool
+	inputs   []string
+	asked    []string
+}
+
+func (p *fakePrompter) Confirm(question string, defaultYes bool) (bool, error) {
+	p.asked = append(p.asked, question)
+	if len(p.confirms) == 0 {
+		return false, errors.New("unexpected confirmation: " + question)
+	}
+	answer := p.confirms[0]
+	p.confirms = p.confirms[1:]
+	return answer, nil
+}
+
+func (p *fakePrompter) Input(question string) (string, error) {
+	p.asked = append(p.asked, question)
+	if len(p.inputs) == 0 {
+		return "", errors.New("unexpected input: " + question)
+	}
+	answer := p.inputs[0]
+	p.inputs = p.inputs[1:]
+	return answer, nil
+}
+
+func TestDeleteEnvironmentPrompts(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "envguard-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	manager, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+
+	prompter := &fakePrompter{confirms: []bool{false, true}}
+	manager.SetPrompter(prompter)
+
+	if err := manager.CreateEnvironment("test", false); err != nil {
+		t.Fatalf("Failed to create environment: %v", err)
+	}
+
+	if err := manager.DeleteEnvironment("test", true); err != nil {
+		t.Fatalf("Declined deletion should not fail: %v", err)
+	}
+	if !manager.EnvironmentExists("test") {
+		t.Error("Environment should survive a declined deletion")
+	}
+
+	if err := manager.DeleteEnvironment("test", true); err != nil {
+		t.Fatalf("Failed to delete environment: %v", err)
+	}
+	if manager.EnvironmentExists("test") {
+		t.Error("Environment should be deleted after confirmation")
+	}
+
+	if len(prompter.asked) != 2 {
+		t.Errorf("Expected 2 prompts, got %v", prompter.asked)
+	}
+}
+
+func TestPromptForCurrentEnv(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "envguard-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	manager, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+
+	prompter := &fakePrompter{}
+	manager.SetPrompter(prompter)
+
+	// No .env means nothing to ask
+	if base, err := manager.PromptForCurrentEnv(); err != nil || base {
+		t.Errorf("Expected no prompt without .env, got %v, %v", base, err)
+	}
+
+	os.WriteFile(manager.GetRootEnvPath(), []byte("A=1\n"), 0644)
+	prompter.confirms = []bool{true}
+	if base, err := manager.PromptForCurrentEnv(); err != nil || !base {
+		t.Errorf("Expected scripted yes, got %v, %v", base, err)
+	}
+
+	manager.SetPrompter(NonInteractivePrompter{})
+	if _, err := manager.PromptForCurrentEnv(); !errors.Is(err, ErrNonInteractive) {
+		t.Errorf("Expected ErrNonInteractive, got %v", err)
+	}
+
+	manager.SetPrompter(AnswerPrompter{Answer: false})
+	if base, err := manager.PromptForCurrentEnv(); err != nil || base {
+		t.Errorf("Expected --no answer, got %v, %v", base, err)
+	}
+}
+
+func TestProtectedConfirmationPrompt(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "envguard-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	manager, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+
+	if err := manager.CreateEnvironment("production", false); err != nil {
+		t.Fatalf("Failed to create environment: %v", err)
+	}
+	if err := manager.SetProtected("production", true); err != nil {
+		t.Fatalf("Failed to protect environment: %v", err)
+	}
+
+	manager.SetPrompter(&fakePrompter{inputs: []string{"prod"}})
+	if err := manager.UseEnvironment("production"); err == nil {
+		t.Error("Mistyped confirmation should be rejected")
+	}
+
+	manager.SetPrompter(AnswerPrompter{Answer: true})
+	if err := manager.UseEnvironment("production"); !errors.Is(err, ErrNonInteractive) {
+		t.Errorf("--yes must not confirm protected environments, got %v", err)
+	}
+
+	manager.SetPrompter(&fakePrompter{inputs: []string{"production"}})
+	if err := manager.UseEnvironment("production"); err != nil {
+		t.Errorf("Typed confirmation should be accepted: %v", err)
+	}
+
+	// Removing protection needs the same confirmation
+	unprotect, _ := NewManager()
+	unprotect.SetPrompter(AnswerPrompter{Answer: true})
+	if err := unprotect.SetProtected("production", false); err == nil || !isProtected(t, unprotect, "production") {
+		t.Errorf("Unprotecting without typing the name should be refused, got %v", err)
+	}
+	unprotect.SetPrompter(&fakePrompter{inputs: []string{"production"}})
+	if err := unprotect.SetProtected("production", false); err != nil || isProtected(t, unprotect, "production") {
+		t.Errorf("Typed confirmation should unprotect, got %v", err)
+	}
+}
+
+func TestTerminalPrompter(t *testing.T) {
+	prompter := NewTerminalPrompter(strings.NewReader("\nyes\nn\nproduction"), io.Discard)
+
+	if ok, _ := prompter.Confirm("default", true); !ok {
+		t.Error("Empty answer should use the default")
+	}
+	if ok, _ := prompter.Confirm("explicit yes", false); !ok {
+		t.Error("Expected yes")
+	}
+	if ok, _ := prompter.Confirm("explicit no", true); ok {
+		t.Error("Expected no")
+	}
+	if answer, err := prompter.Input("name"); err != nil || answer != "production" {
+		t.Errorf("Expected final line without newline, got %q, %v", answer, err)
+	}
+	if _, err := prompter.Input("eof"); err == nil {
+		t.Error("Expected error at end of input")
+	}
+}
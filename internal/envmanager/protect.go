@@ -0,0 +1,122 @@
+package envmanager
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/crabest/envguard/internal/report"
+)
+
+// ConfirmProtected pre-confirms operations on the named protected
+// environment, as --confirm-protected=<name> does for scripts.
+func (m *Manager) ConfirmProtected(envName string) {
+	m.confirmedProtected = envName
+}
+
+// IsProtected reports whether envName is protected by the project config
+// or by its metadata. It fails when either cannot be read, so that a
+// broken file never silently unprotects an environment.
+func (m *Manager) IsProtected(envName string) (bool, error) {
+	config, err := m.Config()
+	if err != nil {
+		return false, err
+	}
+	for _, name := range config.Protected {
+		if name == envName {
+			return true, nil
+		}
+	}
+
+	all, err := m.loadMetadata()
+	if err != nil {
+		return false, err
+	}
+	return all[envName].Protected, nil
+}
+
+// SetProtected marks or unmarks an environment as protected in its
+// metadata. Removing protection asks for the environment name like any
+// other change to a protected environment.
+func (m *Manager) SetProtected(envName string, protected bool) error {
+	if !m.EnvironmentExists(envName) {
+		return fmt.Errorf("environment '%s' does not exist", envName)
+	}
+
+	if !protected {
+		if err := m.requireProtectedConfirmation(envName, "unprotect"); err != nil {
+			return err
+		}
+	}
+
+	return m.modifyMetadata(func(all map[string]storedMetadata) {
+		stored := all[envName]
+		stored.Protected = protected
+		all[envName] = stored
+	})
+}
+
+// needsProtectedConfirmation reports whether changing envName would
+// prompt for its name first. An environment whose protection cannot be
+// determined counts as protected.
+func (m *Manager) needsProtectedConfirmation(envName string) bool {
+	protected, err := m.IsProtected(envName)
+	return (protected || err != nil) && m.confirmedProtected != envName
+}
+
+// requireProtectedConfirmation asks the user to type the environment name
+// before action is performed on a protected environment.
+func (m *Manager) requireProtectedConfirmation(envName, action string) error {
+	if _, err := m.IsProtected(envName); err != nil {
+		return fmt.Errorf("cannot tell whether '%s' is protected, refusing to %s it: %w", envName, action, err)
+	}
+	if !m.needsProtectedConfirmation(envName) {
+		return nil
+	}
+
+	m.emit(report.LevelWarning, "protected.confirmation_required", "🔒", report.Fields{"environment": envName, "action": action},
+		"'%s' is a protected environment.", envName)
+
+	response, err := m.getPrompter().Input(fmt.Sprintf("Type the environment name to %s it", action))
+	if err != nil {
+		return fmt.Errorf("protected environment '%s' requires confirmation (use --confirm-protected=%s): %w", envName, envName, err)
+	}
+
+	if response != envName {
+		return fmt.Errorf("confirmation did not match '%s', refusing to %s it", envName, action)
+	}
+
+	m.confirmedProtected = envName
+	return nil
+}
+
+// setRootEnvReadOnly applies the read_only_protected setting to the root
+// .env after envName has been materialized into it.
+func (m *Manager) setRootEnvReadOnly(envName string) error {
+	config, err := m.Config()
+	if err != nil {
+		return err
+	}
+
+	if !config.ReadOnlyProtected {
+		return nil
+	}
+	if protected, err := m.IsProtected(envName); err != nil || !protected {
+		return err
+	}
+
+	if err := os.Chmod(m.GetRootEnvPath(), 0444); err != nil {
+		return fmt.Errorf("failed to make .env read-only: %w", err)
+	}
+
+	m.emit(report.LevelInfo, "env.read_only", "🔒", report.Fields{"environment": envName}, ".env is read-only while '%s' is active", envName)
+	return nil
+}
+
+// makeRootEnvWritable undoes a previous read-only .env so it can be replaced.
+func (m *Manager) makeRootEnvWritable() error {
+	info, err := os.Stat(m.GetRootEnvPath())
+	if err != nil || info.Mode().Perm()&0200 != 0 {
+		return nil
+	}
+	return os.Chmod(m.GetRootEnvPath(), 0644)
+}
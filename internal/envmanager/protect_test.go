@@ -0,0 +1,244 @@
+package envmanager
+
+import (
+	"os"
+	"testing"
+)
+
+// isProtected is IsProtected for environments whose protection must be
+// readable.
+func isProtected(t *testing.T, m *Manager, envName string) bool {
+	t.Helper()
+	protected, err := m.IsProtected(envName)
+	if err != nil {
+		t.Fatalf("Failed to read protection of %s: %v", envName, err)
+	}
+	return protected
+}
+
+func TestProtectedEnvironment(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "envguard-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	manager, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+
+	if err := manager.CreateEnvironment("production", false); err != nil {
+		t.Fatalf("Failed to create environment: %v", err)
+	}
+	os.WriteFile(manager.GetEnvPath("production"), []byte("MODE=live\n"), 0644)
+
+	if isProtected(t, manager, "production") {
+		t.Error("Environment should not be protected by default")
+	}
+
+	if err := manager.SetProtected("production", true); err != nil {
+		t.Fatalf("Failed to protect environment: %v", err)
+	}
+	if !isProtected(t, manager, "production") {
+		t.Error("Environment should be protected")
+	}
+
+	manager.ConfirmProtected("production")
+	if err := manager.UseEnvironment("production"); err != nil {
+		t.Fatalf("Failed to use confirmed protected environment: %v", err)
+	}
+
+	// Without confirmation, implicit sync must neither write into the
+	// protected env nor let the caller discard the edits
+	unconfirmed, _ := NewManager()
+	unconfirmed.SetPrompter(NonInteractivePrompter{})
+	os.WriteFile(unconfirmed.GetRootEnvPath(), []byte("MODE=oops\n"), 0644)
+	if err := unconfirmed.SyncActiveEnvironment(); err == nil {
+		t.Fatal("Sync should refuse unconfirmed changes to a protected environment")
+	}
+	stored, _ := os.ReadFile(unconfirmed.GetEnvPath("production"))
+	if string(stored) != "MODE=live\n" {
+		t.Errorf("Protected environment was overwritten: %q", string(stored))
+	}
+
+	// Typing the name saves the edits
+	typed, _ := NewManager()
+	typed.SetPrompter(&fakePrompter{inputs: []string{"production"}})
+	if err := typed.SyncActiveEnvironment(); err != nil {
+		t.Fatalf("Sync should save after the name is typed, got %v", err)
+	}
+	stored, _ = os.ReadFile(typed.GetEnvPath("production"))
+	if string(stored) != "MODE=oops\n" {
+		t.Errorf("Typed confirmation should update the environment, got %q", string(stored))
+	}
+	os.WriteFile(manager.GetRootEnvPath(), []byte("MODE=again\n"), 0644)
+
+	if err := manager.SyncActiveEnvironment(); err != nil {
+		t.Fatalf("Failed to sync confirmed environment: %v", err)
+	}
+	stored, _ = os.ReadFile(manager.GetEnvPath("production"))
+	if string(stored) != "MODE=again\n" {
+		t.Errorf("Confirmed sync should update the environment, got %q", string(stored))
+	}
+}
+
+func TestProtectedFromConfig(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "envguard-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	manager, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+
+	if err := manager.CreateEnvironment("production", false); err != nil {
+		t.Fatalf("Failed to create environment: %v", err)
+	}
+
+	config := `{"protected": ["production"], "read_only_protected": true}`
+	if err := os.WriteFile(manager.GetConfigPath(), []byte(config), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	if !isProtected(t, manager, "production") {
+		t.Error("Environment listed in config should be protected")
+	}
+
+	manager.ConfirmProtected("production")
+	if err := manager.UseEnvironment("production"); err != nil {
+		t.Fatalf("Failed to use environment: %v", err)
+	}
+
+	info, err := os.Stat(manager.GetRootEnvPath())
+	if err != nil {
+		t.Fatalf("Failed to stat .env: %v", err)
+	}
+	if info.Mode().Perm()&0222 != 0 {
+		t.Errorf("Expected read-only .env, got mode %v", info.Mode().Perm())
+	}
+
+	if err := manager.CreateEnvironment("development", false); err != nil {
+		t.Fatalf("Failed to create environment: %v", err)
+	}
+	if err := manager.UseEnvironment("development"); err != nil {
+		t.Fatalf("Switching away from a read-only .env should work: %v", err)
+	}
+}
+
+func TestRenameProtectedEnvironment(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "envguard-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	manager, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+
+	for _, env := range []string{"prod", "live"} {
+		if err := manager.CreateEnvironment(env, false); err != nil {
+			t.Fatalf("Failed to create environment: %v", err)
+		}
+	}
+	if err := manager.SetProtected("live", true); err != nil {
+		t.Fatalf("Failed to protect environment: %v", err)
+	}
+
+	config := `{"protected": ["prod", "billing"], "read_only_protected": true}`
+	if err := os.WriteFile(manager.GetConfigPath(), []byte(config), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	manager.SetPrompter(&fakePrompter{inputs: []string{"wrong"}})
+	if err := manager.RenameEnvironment("prod", "production"); err == nil {
+		t.Fatal("Renaming a protected environment should need confirmation")
+	}
+	if !manager.EnvironmentExists("prod") {
+		t.Error("Environment should not be renamed without confirmation")
+	}
+
+	manager.SetPrompter(&fakePrompter{inputs: []string{"prod"}})
+	if err := manager.RenameEnvironment("prod", "production"); err != nil {
+		t.Fatalf("Failed to rename confirmed environment: %v", err)
+	}
+
+	// A fresh manager rereads the rewritten config
+	reloaded, _ := NewManager()
+	if !isProtected(t, reloaded, "production") || isProtected(t, reloaded, "prod") {
+		t.Error("Config protection should follow the renamed environment")
+	}
+	cfg, err := reloaded.Config()
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if len(cfg.Protected) != 2 || cfg.Protected[0] != "production" || cfg.Protected[1] != "billing" || !cfg.ReadOnlyProtected {
+		t.Errorf("Unexpected config after rename: %+v", cfg)
+	}
+
+	reloaded.SetPrompter(&fakePrompter{inputs: []string{"live"}})
+	if err := reloaded.RenameEnvironment("live", "live-eu"); err != nil {
+		t.Fatalf("Failed to rename confirmed environment: %v", err)
+	}
+	if !isProtected(t, reloaded, "live-eu") {
+		t.Error("Metadata protection should follow the renamed environment")
+	}
+
+	reloaded.SetPrompter(&fakePrompter{})
+	if err := reloaded.CopyEnvironment("production", "billing", nil, nil); err == nil {
+		t.Error("Copying onto an environment protected by config should need confirmation")
+	}
+	if reloaded.EnvironmentExists("billing") {
+		t.Error("Copy should not be created without confirmation")
+	}
+}
+
+func TestProtectionUnreadable(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "envguard-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	manager, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	if err := manager.CreateEnvironment("production", false); err != nil {
+		t.Fatalf("Failed to create environment: %v", err)
+	}
+	os.WriteFile(manager.GetConfigPath(), []byte(`{"protected": ["production"`), 0644)
+
+	broken, _ := NewManager()
+	broken.ConfirmProtected("production")
+	if _, err := broken.IsProtected("production"); err == nil {
+		t.Error("Expected an error for a malformed config")
+	}
+	if err := broken.DeleteEnvironment("production", false); err == nil {
+		t.Error("Expected delete to be refused when protection cannot be read")
+	}
+	if !broken.EnvironmentExists("production") {
+		t.Error("Environment should not have been deleted")
+	}
+}
@@ -0,0 +1,167 @@
+package envmanager
+
+import (
+	"fmt"
+
+	"github.com/crabest/envguard/internal/parser"
+	"github.com/crabest/envguard/internal/report"
+)
+
+// SyncDirection selects which side of a sync is overwritten.
+type SyncDirection string
+
+const (
+	// SyncPush copies the root .env into the active stored environment.
+	SyncPush SyncDirection = "push"
+	// SyncPull copies the active stored environment over the root .env.
+	SyncPull SyncDirection = "pull"
+)
+
+// ParseSyncDirection validates a --direction value.
+func ParseSyncDirection(value string) (SyncDirection, error) {
+	switch SyncDirection(value) {
+	case SyncPush, SyncPull:
+		return SyncDirection(value), nil
+	}
+	return "", fmt.Errorf("invalid sync direction '%s' (use push or pull)", value)
+}
+
+// SyncPlan describes the key-level changes a sync would make.
+type SyncPlan struct {
+	Environment string
+	Direction   SyncDirection
+	From        parser.EnvVars
+	To          parser.EnvVars
+	Keys        []string
+
+	// ContentDiffers is true when the files differ at all, including
+	// comment or formatting changes that leave Keys empty.
+	ContentDiffers bool
+}
+
+// DisableImplicitSync stops AutoSync from writing, as --no-sync does.
+func (m *Manager) DisableImplicitSync() {
+	m.noImplicitSync = true
+}
+
+// AutoSync saves .env edits into the active environment unless implicit
+// sync has been turned off by flag or by implicit_sync in the config.
+// Only commands that change environments should call it.
+func (m *Manager) AutoSync() error {
+	if m.noImplicitSync {
+		return nil
+	}
+
+	config, err := m.Config()
+	if err != nil {
+		return err
+	}
+	if !config.ImplicitSyncEnabled() {
+		return nil
+	}
+
+	return m.SyncActiveEnvironment()
+}
+
+// PlanSync compares the root .env with the active environment for the
+// given direction without writing anything.
+func (m *Manager) PlanSync(direction SyncDirection) (*SyncPlan, error) {
+	activeEnv, err := m.GetActiveEnvironment()
+	if err != nil {
+		return nil, err
+	}
+
+	if !m.EnvironmentExists(activeEnv) {
+		return nil, fmt.Errorf("active environment '%s' no longer exists", activeEnv)
+	}
+
+	root, err := readVarsOrEmpty(m.GetRootEnvPath())
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse .env: %w", err)
+	}
+
+	stored, err := parser.ParseEnvFile(m.GetEnvPath(activeEnv))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse environment '%s': %w", activeEnv, err)
+	}
+
+	plan := &SyncPlan{Environment: activeEnv, Direction: direction, From: root, To: stored}
+	if direction == SyncPull {
+		plan.From, plan.To = stored, root
+	}
+	plan.Keys = ChangedKeys(plan.To, plan.From)
+	plan.ContentDiffers = m.filesAreDifferent(m.GetRootEnvPath(), m.GetEnvPath(activeEnv))
+
+	return plan, nil
+}
+
+// Sync previews plan and, unless dryRun is set, applies it.
+func (m *Manager) Sync(plan *SyncPlan, dryRun bool) error {
+	pending := !dryRun && plan.ContentDiffers && plan.Direction == SyncPush && m.needsProtectedConfirmation(plan.Environment)
+	m.reportSyncPlan(plan, pending)
+
+	if !plan.ContentDiffers {
+		m.emit(report.LevelSuccess, "sync.in_sync", "✅", report.Fields{"environment": plan.Environment}, ".env and %s/%s.env are in sync", EnvGuardDir, plan.Environment)
+		return nil
+	}
+
+	if dryRun {
+		m.emit(report.LevelInfo, "sync.dry_run", "ℹ️ ", nil, "Dry run, nothing was written")
+		return nil
+	}
+
+	switch plan.Direction {
+	case SyncPush:
+		if err := m.requireProtectedConfirmation(plan.Environment, "overwrite"); err != nil {
+			return err
+		}
+		if err := m.copyFile(m.GetRootEnvPath(), m.GetEnvPath(plan.Environment)); err != nil {
+			return fmt.Errorf("failed to sync .env changes to environment '%s': %w", plan.Environment, err)
+		}
+		m.emit(report.LevelSuccess, "sync.pushed", "✅", report.Fields{"environment": plan.Environment}, "Synced .env → %s/%s.env", EnvGuardDir, plan.Environment)
+	case SyncPull:
+		if err := m.makeRootEnvWritable(); err != nil {
+			return fmt.Errorf("failed to make .env writable: %w", err)
+		}
+		if err := m.copyFile(m.GetEnvPath(plan.Environment), m.GetRootEnvPath()); err != nil {
+			return fmt.Errorf("failed to update .env from environment '%s': %w", plan.Environment, err)
+		}
+		m.rootEnvWritten()
+		m.emit(report.LevelSuccess, "sync.pulled", "✅", report.Fields{"environment": plan.Environment}, "Synced %s/%s.env → .env", EnvGuardDir, plan.Environment)
+		if err := m.setRootEnvReadOnly(plan.Environment); err != nil {
+			return err
+		}
+	}
+
+	return m.saveSnapshot(plan.Environment)
+}
+
+func (m *Manager) reportSyncPlan(plan *SyncPlan, pending bool) {
+	source, target := ".env", fmt.Sprintf("%s/%s.env", EnvGuardDir, plan.Environment)
+	if plan.Direction == SyncPull {
+		source, target = target, source
+	}
+
+	p := m.preview(pending)
+	p.Heading("sync.plan", "🔄", report.Fields{"environment": plan.Environment, "direction": plan.Direction},
+		"Sync %s → %s:", source, target)
+
+	for _, key := range plan.Keys {
+		newValue, inFrom := plan.From[key]
+		_, inTo := plan.To[key]
+		switch {
+		case inFrom && !inTo:
+			p.Item(report.StyleAdded, "sync.key", report.Fields{"key": key, "op": "+", "change": "added", "value": MaskValue(newValue)}, "+ %s=%s", key, MaskValue(newValue))
+		case !inFrom && inTo:
+			p.Item(report.StyleRemoved, "sync.key", report.Fields{"key": key, "op": "-", "change": "removed"}, "- %s", key)
+		default:
+			p.Item(report.StyleChanged, "sync.key", report.Fields{"key": key, "op": "~", "change": "changed", "value": MaskValue(newValue)}, "~ %s=%s", key, MaskValue(newValue))
+		}
+	}
+
+	if len(plan.Keys) > 0 {
+		p.Note("sync.summary", "📊", report.Fields{"keys": len(plan.Keys)}, "%d keys differ", len(plan.Keys))
+	} else if plan.ContentDiffers {
+		p.Item("", "sync.formatting", nil, "Only comments or formatting differ")
+	}
+}
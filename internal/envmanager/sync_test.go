@@ -0,0 +1,130 @@
+package envmanager
+
+import (
+	"os"
+	"testing"
+)
+
+func TestSyncPushAndPull(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "envguard-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	manager, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+
+	if err := manager.CreateEnvironment("dev", false); err != nil {
+		t.Fatalf("Failed to create environment: %v", err)
+	}
+	os.WriteFile(manager.GetEnvPath("dev"), []byte("A=1\n"), 0644)
+	if err := manager.UseEnvironment("dev"); err != nil {
+		t.Fatalf("Failed to use environment: %v", err)
+	}
+
+	os.WriteFile(manager.GetRootEnvPath(), []byte("A=2\nB=3\n"), 0644)
+
+	plan, err := manager.PlanSync(SyncPush)
+	if err != nil {
+		t.Fatalf("Failed to plan sync: %v", err)
+	}
+	if len(plan.Keys) != 2 {
+		t.Errorf("Expected 2 changed keys, got %v", plan.Keys)
+	}
+
+	if err := manager.Sync(plan, true); err != nil {
+		t.Fatalf("Dry run failed: %v", err)
+	}
+	stored, _ := os.ReadFile(manager.GetEnvPath("dev"))
+	if string(stored) != "A=1\n" {
+		t.Errorf("Dry run should not write, got %q", string(stored))
+	}
+
+	if err := manager.Sync(plan, false); err != nil {
+		t.Fatalf("Push failed: %v", err)
+	}
+	stored, _ = os.ReadFile(manager.GetEnvPath("dev"))
+	if string(stored) != "A=2\nB=3\n" {
+		t.Errorf("Push should update the stored env, got %q", string(stored))
+	}
+
+	os.WriteFile(manager.GetEnvPath("dev"), []byte("A=5\n"), 0644)
+	plan, err = manager.PlanSync(SyncPull)
+	if err != nil {
+		t.Fatalf("Failed to plan sync: %v", err)
+	}
+	if err := manager.Sync(plan, false); err != nil {
+		t.Fatalf("Pull failed: %v", err)
+	}
+	root, _ := os.ReadFile(manager.GetRootEnvPath())
+	if string(root) != "A=5\n" {
+		t.Errorf("Pull should update .env, got %q", string(root))
+	}
+}
+
+func TestAutoSyncDisabled(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "envguard-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	manager, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+
+	if err := manager.CreateEnvironment("dev", false); err != nil {
+		t.Fatalf("Failed to create environment: %v", err)
+	}
+	if err := manager.UseEnvironment("dev"); err != nil {
+		t.Fatalf("Failed to use environment: %v", err)
+	}
+	os.WriteFile(manager.GetRootEnvPath(), []byte("A=1\n"), 0644)
+	os.WriteFile(manager.GetConfigPath(), []byte(`{"implicit_sync": false}`), 0644)
+
+	manager, _ = NewManager()
+	if err := manager.AutoSync(); err != nil {
+		t.Fatalf("AutoSync failed: %v", err)
+	}
+	stored, _ := os.ReadFile(manager.GetEnvPath("dev"))
+	if len(stored) != 0 {
+		t.Errorf("AutoSync should not write when implicit_sync is false, got %q", string(stored))
+	}
+}
+
+func TestReadOnlyCallsDoNotCreateDir(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "envguard-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	originalWd, _ := os.Getwd()
+	defer os.Chdir(originalWd)
+	os.Chdir(tmpDir)
+
+	manager, err := NewManager()
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+
+	manager.ListEnvironments()
+	manager.ListEnvironmentMetadata()
+	manager.GetActiveEnvironment()
+
+	if _, err := os.Stat(EnvGuardDir); !os.IsNotExist(err) {
+		t.Errorf("Read-only calls should not create %s", EnvGuardDir)
+	}
+}
@@ -0,0 +1,29 @@
+package exporter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/crabest/envguard/internal/parser"
+)
+
+// exportDockerEnv writes values literally, one KEY=value per line, as
+// docker run --env-file expects: no quotes, escapes or interpolation.
+// Docker cannot represent multi-line values, so they are an error.
+func exportDockerEnv(entries []parser.Entry, opts Options) (string, error) {
+	var b strings.Builder
+	var multiline []string
+
+	for _, entry := range dedupe(entries) {
+		if strings.ContainsAny(entry.Value, "\n\r") {
+			multiline = append(multiline, entry.Key)
+			continue
+		}
+		fmt.Fprintf(&b, "%s=%s\n", entry.Key, entry.Value)
+	}
+
+	if len(multiline) > 0 {
+		return "", fmt.Errorf("docker env files cannot hold multi-line values: %s", strings.Join(multiline, ", "))
+	}
+	return b.String(), nil
+}
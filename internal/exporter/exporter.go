@@ -0,0 +1,69 @@
+// Package exporter renders stored environments in formats other tools
+// consume directly, such as Kubernetes manifests and Docker env files.
+package exporter
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/crabest/envguard/internal/parser"
+)
+
+// Options configures an export.
+type Options struct {
+	// Name is the metadata.name of generated Kubernetes objects.
+	Name string
+	// Namespace is the metadata.namespace, omitted when empty.
+	Namespace string
+	// Environment is the envguard environment being exported.
+	Environment string
+	// Split puts keys for which IsSecret returns true into a Secret and the
+	// rest into a ConfigMap, whichever Kubernetes target was chosen.
+	Split bool
+	// IsSecret classifies a key with its value.
+	IsSecret func(key, value string) bool
+}
+
+type exporter func(entries []parser.Entry, opts Options) (string, error)
+
+var exporters = map[string]exporter{
+	"docker-env":    exportDockerEnv,
+	"k8s-secret":    exportKubernetesSecret,
+	"k8s-configmap": exportKubernetesConfigMap,
+}
+
+// Targets returns the supported export targets in sorted order.
+func Targets() []string {
+	targets := make([]string, 0, len(exporters))
+	for target := range exporters {
+		targets = append(targets, target)
+	}
+	sort.Strings(targets)
+	return targets
+}
+
+// Export renders entries, in file order, for target.
+func Export(target string, entries []parser.Entry, opts Options) (string, error) {
+	export, ok := exporters[target]
+	if !ok {
+		return "", fmt.Errorf("unknown export target '%s' (use %s)", target, strings.Join(Targets(), ", "))
+	}
+	return export(entries, opts)
+}
+
+// dedupe keeps the last assignment of each key at the position of its
+// first, matching how dotenv loaders resolve duplicates.
+func dedupe(entries []parser.Entry) []parser.Entry {
+	index := make(map[string]int)
+	var result []parser.Entry
+	for _, entry := range entries {
+		if i, ok := index[entry.Key]; ok {
+			result[i] = entry
+			continue
+		}
+		index[entry.Key] = len(result)
+		result = append(result, entry)
+	}
+	return result
+}
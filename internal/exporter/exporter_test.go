@@ -0,0 +1,116 @@
+package exporter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/crabest/envguard/internal/parser"
+	"github.com/crabest/envguard/internal/validator"
+)
+
+func loadEntries(t *testing.T, content string) []parser.Entry {
+	t.Helper()
+	entries, err := parser.ParseEntries([]byte(content))
+	if err != nil {
+		t.Fatalf("Failed to parse entries: %v", err)
+	}
+	return entries
+}
+
+const exampleEnv = `PORT=3000
+DEBUG=true
+JWT_SECRET=s3cr3t
+DATABASE_URL=postgres://app:pw@db/app
+PORT=8080
+`
+
+func TestExportKubernetesConfigMap(t *testing.T) {
+	manifest, err := Export("k8s-configmap", loadEntries(t, exampleEnv), Options{Name: "api-env", Namespace: "prod", Environment: "production"})
+	if err != nil {
+		t.Fatalf("Failed to export: %v", err)
+	}
+
+	expected := `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: api-env
+  namespace: prod
+  labels:
+    app.kubernetes.io/managed-by: envguard
+  annotations:
+    envguard.dev/environment: "production"
+data:
+  PORT: "8080"
+  DEBUG: "true"
+  JWT_SECRET: "s3cr3t"
+  DATABASE_URL: "postgres://app:pw@db/app"
+`
+	if manifest != expected {
+		t.Errorf("Unexpected manifest:\n%s", manifest)
+	}
+}
+
+func TestExportKubernetesSecretSplit(t *testing.T) {
+	schema := validator.NewSchema(nil)
+	opts := Options{Name: "api-env", Split: true, IsSecret: schema.IsSecret}
+
+	manifest, err := Export("k8s-secret", loadEntries(t, exampleEnv), opts)
+	if err != nil {
+		t.Fatalf("Failed to export: %v", err)
+	}
+
+	documents := strings.Split(manifest, "---\n")
+	if len(documents) != 2 {
+		t.Fatalf("Expected a Secret and a ConfigMap, got:\n%s", manifest)
+	}
+	if !strings.Contains(documents[0], "kind: Secret") || !strings.Contains(documents[0], "type: Opaque") {
+		t.Errorf("Expected the first document to be a Secret:\n%s", documents[0])
+	}
+	// base64("s3cr3t") and base64("postgres://app:pw@db/app")
+	if !strings.Contains(documents[0], "  JWT_SECRET: czNjcjN0\n") || !strings.Contains(documents[0], "  DATABASE_URL: cG9zdGdyZXM6Ly9hcHA6cHdAZGIvYXBw\n") {
+		t.Errorf("Expected base64 secret data:\n%s", documents[0])
+	}
+	if strings.Contains(documents[1], "JWT_SECRET") || !strings.Contains(documents[1], `  PORT: "8080"`) {
+		t.Errorf("Expected only non-secret keys in the ConfigMap:\n%s", documents[1])
+	}
+}
+
+func TestExportErrors(t *testing.T) {
+	entries := loadEntries(t, exampleEnv)
+
+	if _, err := Export("helm", entries, Options{Name: "x"}); err == nil {
+		t.Error("Expected an unknown target to fail")
+	}
+	if _, err := Export("k8s-secret", entries, Options{}); err == nil {
+		t.Error("Expected a missing name to fail")
+	}
+	if _, err := Export("k8s-secret", entries, Options{Name: "Api_Env"}); err == nil {
+		t.Error("Expected an invalid name to fail")
+	}
+}
+
+func TestExportDockerEnv(t *testing.T) {
+	entries := loadEntries(t, "NAME=\"my app\"\nREF='${HOME}'\nINLINE=abc # note\nexport TOKEN=x\n")
+
+	output, err := Export("docker-env", entries, Options{})
+	if err != nil {
+		t.Fatalf("Failed to export: %v", err)
+	}
+	if output != "NAME=my app\nREF=${HOME}\nINLINE=abc\nTOKEN=x\n" {
+		t.Errorf("Unexpected docker env file:\n%s", output)
+	}
+
+	read, err := parser.ReadDialect([]byte(output), parser.DialectDocker)
+	if err != nil {
+		t.Fatalf("Failed to read docker dialect: %v", err)
+	}
+	for i, entry := range read {
+		if entry.Problem != "" || entry.Value != entries[i].Value {
+			t.Errorf("Expected docker to read %s as %q, got %+v", entries[i].Key, entries[i].Value, entry)
+		}
+	}
+
+	if _, err := Export("docker-env", loadEntries(t, "CERT=\"a\nb\"\n"), Options{}); err == nil {
+		t.Error("Expected multi-line values to fail")
+	}
+}
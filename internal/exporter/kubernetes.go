@@ -0,0 +1,164 @@
+package exporter
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/crabest/envguard/internal/parser"
+)
+
+func exportKubernetesSecret(entries []parser.Entry, opts Options) (string, error) {
+	if opts.Split {
+		return exportKubernetesSplit(entries, opts)
+	}
+	return kubernetesSecret(dedupe(entries), opts)
+}
+
+func exportKubernetesConfigMap(entries []parser.Entry, opts Options) (string, error) {
+	if opts.Split {
+		return exportKubernetesSplit(entries, opts)
+	}
+	return kubernetesConfigMap(dedupe(entries), opts)
+}
+
+// exportKubernetesSplit emits a Secret for secret keys followed by a
+// ConfigMap for everything else, leaving out an object that would be empty.
+func exportKubernetesSplit(entries []parser.Entry, opts Options) (string, error) {
+	if opts.IsSecret == nil {
+		return "", fmt.Errorf("splitting requires a secret classifier")
+	}
+
+	var secrets, config []parser.Entry
+	for _, entry := range dedupe(entries) {
+		if opts.IsSecret(entry.Key, entry.Value) {
+			secrets = append(secrets, entry)
+		} else {
+			config = append(config, entry)
+		}
+	}
+
+	var documents []string
+	if len(secrets) > 0 || len(config) == 0 {
+		secret, err := kubernetesSecret(secrets, opts)
+		if err != nil {
+			return "", err
+		}
+		documents = append(documents, secret)
+	}
+	if len(config) > 0 {
+		configMap, err := kubernetesConfigMap(config, opts)
+		if err != nil {
+			return "", err
+		}
+		documents = append(documents, configMap)
+	}
+
+	return strings.Join(documents, "---\n"), nil
+}
+
+func kubernetesSecret(entries []parser.Entry, opts Options) (string, error) {
+	var b strings.Builder
+	if err := checkDataKeys(entries); err != nil {
+		return "", err
+	}
+	if err := writeKubernetesHeader(&b, "Secret", opts); err != nil {
+		return "", err
+	}
+	b.WriteString("type: Opaque\n")
+	writeKubernetesData(&b, entries, func(value string) string {
+		return base64.StdEncoding.EncodeToString([]byte(value))
+	})
+	return b.String(), nil
+}
+
+func kubernetesConfigMap(entries []parser.Entry, opts Options) (string, error) {
+	var b strings.Builder
+	if err := checkDataKeys(entries); err != nil {
+		return "", err
+	}
+	if err := writeKubernetesHeader(&b, "ConfigMap", opts); err != nil {
+		return "", err
+	}
+	writeKubernetesData(&b, entries, yamlString)
+	return b.String(), nil
+}
+
+func writeKubernetesHeader(b *strings.Builder, kind string, opts Options) error {
+	if opts.Name == "" {
+		return fmt.Errorf("a name is required for a Kubernetes %s", kind)
+	}
+	if !isDNSSubdomain(opts.Name) {
+		return fmt.Errorf("invalid Kubernetes name '%s' (use lowercase letters, digits, '-' and '.')", opts.Name)
+	}
+
+	if opts.Namespace != "" && !isDNSSubdomain(opts.Namespace) {
+		return fmt.Errorf("invalid Kubernetes namespace '%s'", opts.Namespace)
+	}
+
+	fmt.Fprintf(b, "apiVersion: v1\nkind: %s\nmetadata:\n", kind)
+	fmt.Fprintf(b, "  name: %s\n", opts.Name)
+	if opts.Namespace != "" {
+		fmt.Fprintf(b, "  namespace: %s\n", opts.Namespace)
+	}
+	b.WriteString("  labels:\n    app.kubernetes.io/managed-by: envguard\n")
+	if opts.Environment != "" {
+		fmt.Fprintf(b, "  annotations:\n    envguard.dev/environment: %s\n", yamlString(opts.Environment))
+	}
+	return nil
+}
+
+func writeKubernetesData(b *strings.Builder, entries []parser.Entry, encode func(string) string) {
+	if len(entries) == 0 {
+		b.WriteString("data: {}\n")
+		return
+	}
+	b.WriteString("data:\n")
+	for _, entry := range entries {
+		fmt.Fprintf(b, "  %s: %s\n", entry.Key, encode(entry.Value))
+	}
+}
+
+// yamlString quotes value as a YAML double-quoted scalar, so values such as
+// "true", "3000" or "yes" stay strings as Kubernetes requires.
+func yamlString(value string) string {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.Encode(value)
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// checkDataKeys rejects keys Kubernetes does not accept in data maps.
+func checkDataKeys(entries []parser.Entry) error {
+	for _, entry := range entries {
+		for i := 0; i < len(entry.Key); i++ {
+			c := entry.Key[i]
+			if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_' || c == '.') {
+				return fmt.Errorf("line %d: %s is not a valid Kubernetes data key", entry.Line, entry.Key)
+			}
+		}
+	}
+	return nil
+}
+
+func isDNSSubdomain(name string) bool {
+	if len(name) > 253 {
+		return false
+	}
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		switch {
+		case c >= 'a' && c <= 'z', c >= '0' && c <= '9':
+		case c == '-' || c == '.':
+			if i == 0 || i == len(name)-1 {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return name != ""
+}
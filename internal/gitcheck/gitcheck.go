@@ -0,0 +1,259 @@
+// Package gitcheck keeps dotenv files with secrets out of git: it checks
+// .gitignore coverage, staged files and history through the local git
+// binary, and installs a pre-commit hook running those checks.
+package gitcheck
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/crabest/envguard/internal/parser"
+	"github.com/crabest/envguard/internal/validator"
+)
+
+// IgnoredPaths must be covered by .gitignore.
+var IgnoredPaths = []string{".env", ".envguard/"}
+
+// templateSuffixes mark dotenv files that are meant to be committed.
+var templateSuffixes = []string{".example", ".sample", ".template", ".dist", ".defaults"}
+
+// Finding is a dotenv file in the index or in history.
+type Finding struct {
+	Path string `json:"path"`
+	// Commit is the commit that added this content; empty for staged
+	// files.
+	Commit string `json:"commit,omitempty"`
+	// SecretKeys are the keys with a secret-looking, non-placeholder value.
+	SecretKeys []string `json:"secret_keys"`
+}
+
+// Repo is a git working tree.
+type Repo struct {
+	Dir string
+}
+
+// Open returns the repository containing dir.
+func Open(dir string) (*Repo, error) {
+	if _, err := exec.LookPath("git"); err != nil {
+		return nil, fmt.Errorf("git is not installed")
+	}
+
+	out, err := (&Repo{Dir: dir}).git("rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, fmt.Errorf("%s is not inside a git repository", dir)
+	}
+	return &Repo{Dir: strings.TrimSpace(string(out))}, nil
+}
+
+// Unignored returns the entries of IgnoredPaths that .gitignore does not
+// cover.
+func (r *Repo) Unignored() ([]string, error) {
+	var missing []string
+	for _, p := range IgnoredPaths {
+		_, err := r.git("check-ignore", "--quiet", "--no-index", p)
+		var exitErr *exec.ExitError
+		switch {
+		case err == nil:
+		case errors.As(err, &exitErr) && exitErr.ExitCode() == 1:
+			missing = append(missing, p)
+		default:
+			return nil, err
+		}
+	}
+	return missing, nil
+}
+
+// AddToGitignore appends patterns to the .gitignore at the repository root.
+func (r *Repo) AddToGitignore(patterns []string) error {
+	gitignore := filepath.Join(r.Dir, ".gitignore")
+
+	existing, err := os.ReadFile(gitignore)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read .gitignore: %w", err)
+	}
+
+	var buf bytes.Buffer
+	buf.Write(existing)
+	if len(existing) > 0 && !bytes.HasSuffix(existing, []byte("\n")) {
+		buf.WriteString("\n")
+	}
+	if len(existing) > 0 {
+		buf.WriteString("\n")
+	}
+	buf.WriteString("# Local environments managed by envguard\n")
+	for _, pattern := range patterns {
+		buf.WriteString(pattern + "\n")
+	}
+
+	if err := os.WriteFile(gitignore, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write .gitignore: %w", err)
+	}
+	return nil
+}
+
+// Staged returns the staged dotenv files, whether or not they hold secrets.
+func (r *Repo) Staged() ([]Finding, error) {
+	out, err := r.git("diff", "--cached", "--name-only", "--diff-filter=ACMR", "-z")
+	if err != nil {
+		return nil, err
+	}
+
+	var findings []Finding
+	for _, p := range strings.Split(string(out), "\x00") {
+		if !IsDotenvPath(p) {
+			continue
+		}
+		content, err := r.git("show", ":"+p)
+		if err != nil {
+			return nil, err
+		}
+		findings = append(findings, Finding{Path: p, SecretKeys: SecretKeys(content)})
+	}
+	return findings, nil
+}
+
+// History returns the dotenv files with secrets that any commit reachable
+// from a ref added or changed. Each distinct content is reported once.
+func (r *Repo) History() ([]Finding, error) {
+	out, err := r.git("-c", "core.quotePath=false", "log", "--all", "--no-renames", "--raw", "--no-abbrev",
+		"--diff-filter=AM", "--format=commit %H")
+	if err != nil {
+		// A repository without commits has no history to scan.
+		if _, headErr := r.git("rev-parse", "--verify", "--quiet", "HEAD"); headErr != nil {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var findings []Finding
+	seen := make(map[string]bool)
+	commit := ""
+
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "commit ") {
+			commit = strings.TrimPrefix(line, "commit ")
+			continue
+		}
+
+		// :100644 100644 <old blob> <new blob> M\tpath
+		meta, p, ok := strings.Cut(line, "\t")
+		fields := strings.Fields(meta)
+		if !ok || !strings.HasPrefix(line, ":") || len(fields) < 5 || !IsDotenvPath(p) {
+			continue
+		}
+
+		blob := fields[3]
+		if seen[blob+"\t"+p] {
+			continue
+		}
+		seen[blob+"\t"+p] = true
+
+		content, err := r.git("cat-file", "blob", blob)
+		if err != nil {
+			return nil, err
+		}
+		if keys := SecretKeys(content); len(keys) > 0 {
+			findings = append(findings, Finding{Path: p, Commit: commit, SecretKeys: keys})
+		}
+	}
+	return findings, scanner.Err()
+}
+
+// IsDotenvPath reports whether p names a dotenv file that should not be
+// committed: .env, .env.local, production.env or anything in .envguard/.
+// Templates such as .env.example are allowed.
+func IsDotenvPath(p string) bool {
+	p = filepath.ToSlash(p)
+	base := path.Base(p)
+
+	for _, suffix := range templateSuffixes {
+		if strings.HasSuffix(base, suffix) {
+			return false
+		}
+	}
+
+	switch {
+	case strings.HasPrefix(p, ".envguard/") || strings.Contains(p, "/.envguard/"):
+		return strings.HasSuffix(base, ".env")
+	case base == ".env", strings.HasPrefix(base, ".env."), strings.HasSuffix(base, ".env"):
+		return true
+	}
+	return false
+}
+
+// IsEnvironmentFile reports whether p is the project's own .env or an
+// environment stored in .envguard/. These hold real values by design, so
+// committing one is never expected, whether or not a secret is detected.
+func IsEnvironmentFile(p string) bool {
+	if !IsDotenvPath(p) {
+		return false
+	}
+	p = filepath.ToSlash(p)
+	return p == ".env" || strings.HasPrefix(p, ".envguard/") || strings.Contains(p, "/.envguard/")
+}
+
+// SecretKeys returns the keys in a dotenv file whose name or value looks
+// secret and whose value is not a placeholder, sorted.
+func SecretKeys(content []byte) []string {
+	entries, err := parser.ParseEntries(content)
+	if err != nil {
+		return nil
+	}
+
+	var keys []string
+	for _, entry := range entries {
+		if isPlaceholder(entry.Value) {
+			continue
+		}
+		if validator.LooksSecret(entry.Key) || validator.HasCredentials(entry.Value) {
+			keys = append(keys, entry.Key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// isPlaceholder reports whether value is a dummy the validator already
+// knows, such as "change-me" or "<your-token>", or one that is only
+// harmless in a committed file: empty, none/null, "<>" or a lone
+// reference like ${API_KEY}.
+func isPlaceholder(value string) bool {
+	value = strings.TrimSpace(value)
+
+	switch strings.ToLower(value) {
+	case "", "none", "null", "<>":
+		return true
+	}
+	if validator.IsPlaceholder(value) {
+		return true
+	}
+
+	refs := parser.FindReferences(value, parser.InterpolateCompose)
+	return len(refs) == 1 && refs[0].Start == 0 && refs[0].End == len(value)
+}
+
+func (r *Repo) git(args ...string) ([]byte, error) {
+	cmd := exec.Command("git", append([]string{"-C", r.Dir}, args...)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && stderr.Len() > 0 {
+			return out, fmt.Errorf("git %s: %s: %w", args[0], strings.TrimSpace(stderr.String()), err)
+		}
+		return out, fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return out, nil
+}
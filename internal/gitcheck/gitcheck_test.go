@@ -0,0 +1,202 @@
+package gitcheck
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// newRepo creates a git repository in a temp dir, skipping the test when
+// git is not installed.
+func newRepo(t *testing.T) *Repo {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	tmpDir, err := os.MkdirTemp("", "envguard-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(tmpDir) })
+
+	runGit(t, tmpDir, "init", "--quiet")
+	runGit(t, tmpDir, "config", "user.email", "test@example.com")
+	runGit(t, tmpDir, "config", "user.name", "Test")
+	runGit(t, tmpDir, "config", "core.hooksPath", ".git/hooks")
+
+	repo, err := Open(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to open repository: %v", err)
+	}
+	return repo
+}
+
+func runGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %s failed: %v\n%s", strings.Join(args, " "), err, out)
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("Failed to create dir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write %s: %v", path, err)
+	}
+}
+
+func TestIsDotenvPath(t *testing.T) {
+	for p, expected := range map[string]bool{
+		".env":                      true,
+		"api/.env.local":            true,
+		"deploy/production.env":     true,
+		".envguard/staging.env":     true,
+		".envguard/config.json":     false,
+		".env.example":              false,
+		"config/.env.sample":        false,
+		"environment.go":            false,
+		"docs/envguard/.env.sample": false,
+	} {
+		if IsDotenvPath(p) != expected {
+			t.Errorf("IsDotenvPath(%q) = %v, expected %v", p, !expected, expected)
+		}
+	}
+}
+
+func TestIsEnvironmentFile(t *testing.T) {
+	for p, expected := range map[string]bool{
+		".env":                             true,
+		".envguard/production.env":         true,
+		"api/.envguard/staging.env":        true,
+		".envguard/production.env.example": false,
+		"api/.env":                         false,
+		".env.local":                       false,
+		"deploy/production.env":            false,
+	} {
+		if IsEnvironmentFile(p) != expected {
+			t.Errorf("IsEnvironmentFile(%q) = %v, expected %v", p, !expected, expected)
+		}
+	}
+}
+
+func TestSecretKeys(t *testing.T) {
+	content := `PORT=3000
+JWT_SECRET=s3cr3t-value
+DB_PASSWORD=change-me
+STRIPE_API_KEY=${STRIPE_API_KEY}
+GITHUB_TOKEN=<your-token>
+NPM_TOKEN=<>
+DATABASE_URL=postgres://app:hunter2@db/app
+EMPTY_TOKEN=
+`
+	keys := SecretKeys([]byte(content))
+	if !reflect.DeepEqual(keys, []string{"DATABASE_URL", "JWT_SECRET"}) {
+		t.Errorf("Unexpected secret keys: %v", keys)
+	}
+}
+
+func TestGitignoreCoverage(t *testing.T) {
+	repo := newRepo(t)
+
+	missing, err := repo.Unignored()
+	if err != nil {
+		t.Fatalf("Failed to check .gitignore: %v", err)
+	}
+	if !reflect.DeepEqual(missing, IgnoredPaths) {
+		t.Errorf("Expected %v to be unignored, got %v", IgnoredPaths, missing)
+	}
+
+	writeFile(t, filepath.Join(repo.Dir, ".gitignore"), "node_modules/")
+	if err := repo.AddToGitignore(missing); err != nil {
+		t.Fatalf("Failed to update .gitignore: %v", err)
+	}
+
+	content, _ := os.ReadFile(filepath.Join(repo.Dir, ".gitignore"))
+	if !strings.HasPrefix(string(content), "node_modules/\n\n# Local environments managed by envguard\n.env\n.envguard/\n") {
+		t.Errorf("Unexpected .gitignore:\n%s", content)
+	}
+
+	if missing, err := repo.Unignored(); err != nil || len(missing) != 0 {
+		t.Errorf("Expected .gitignore to cover everything, got %v, %v", missing, err)
+	}
+}
+
+func TestStagedAndHistory(t *testing.T) {
+	repo := newRepo(t)
+
+	if findings, err := repo.History(); err != nil || len(findings) != 0 {
+		t.Errorf("Expected no history findings in an empty repository, got %v, %v", findings, err)
+	}
+
+	writeFile(t, filepath.Join(repo.Dir, ".env.example"), "JWT_SECRET=real-looking-value\n")
+	writeFile(t, filepath.Join(repo.Dir, ".envguard", "production.env"), "JWT_SECRET=abc123\nPORT=80\n")
+	runGit(t, repo.Dir, "add", ".")
+	runGit(t, repo.Dir, "commit", "--quiet", "-m", "add envs")
+	runGit(t, repo.Dir, "rm", "--quiet", "--cached", ".envguard/production.env")
+	runGit(t, repo.Dir, "commit", "--quiet", "-m", "remove env")
+
+	history, err := repo.History()
+	if err != nil {
+		t.Fatalf("Failed to scan history: %v", err)
+	}
+	if len(history) != 1 || history[0].Path != ".envguard/production.env" || history[0].Commit == "" {
+		t.Fatalf("Expected the removed production.env in history, got %+v", history)
+	}
+	if !reflect.DeepEqual(history[0].SecretKeys, []string{"JWT_SECRET"}) {
+		t.Errorf("Unexpected secret keys: %v", history[0].SecretKeys)
+	}
+
+	writeFile(t, filepath.Join(repo.Dir, ".env"), "PORT=3000\n")
+	writeFile(t, filepath.Join(repo.Dir, "api", ".env.local"), "API_TOKEN=tok_123\n")
+	runGit(t, repo.Dir, "add", ".env", "api/.env.local")
+
+	staged, err := repo.Staged()
+	if err != nil {
+		t.Fatalf("Failed to list staged files: %v", err)
+	}
+	expected := []Finding{
+		{Path: ".env"},
+		{Path: "api/.env.local", SecretKeys: []string{"API_TOKEN"}},
+	}
+	if !reflect.DeepEqual(staged, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, staged)
+	}
+}
+
+func TestInstallHook(t *testing.T) {
+	repo := newRepo(t)
+
+	hook, err := repo.InstallHook(false)
+	if err != nil {
+		t.Fatalf("Failed to install hook: %v", err)
+	}
+	info, err := os.Stat(hook)
+	if err != nil || info.Mode()&0111 == 0 {
+		t.Fatalf("Expected an executable hook at %s: %v", hook, err)
+	}
+
+	// Reinstalling replaces our own hook.
+	if _, err := repo.InstallHook(false); err != nil {
+		t.Errorf("Expected reinstall to succeed, got %v", err)
+	}
+
+	writeFile(t, hook, "#!/bin/sh\nmake lint\n")
+	if _, err := repo.InstallHook(false); err == nil {
+		t.Error("Expected error replacing a foreign hook without force")
+	}
+	if _, err := repo.InstallHook(true); err != nil {
+		t.Errorf("Expected force to replace the hook, got %v", err)
+	}
+	content, _ := os.ReadFile(hook)
+	if string(content) != PreCommitHook {
+		t.Errorf("Unexpected hook content:\n%s", content)
+	}
+}
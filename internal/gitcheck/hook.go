@@ -0,0 +1,76 @@
+package gitcheck
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// hookMarker identifies hooks written by InstallHook, so they can be
+// replaced without --force.
+const hookMarker = "# envguard pre-commit hook"
+
+// PreCommitHook blocks commits that stage .env, a stored environment or
+// another dotenv file with secrets, or that leave .env invalid. Paths in
+// $ENVGUARD_ALLOW_STAGED (comma-separated) are let through. Validation is
+// skipped in checkouts without a .env, such as CI.
+const PreCommitHook = `#!/bin/sh
+` + hookMarker + `
+# Installed by "envguard git install-hook"; remove this file to disable.
+
+if ! command -v envguard >/dev/null 2>&1; then
+  echo "envguard not found in PATH; skipping checks" >&2
+  exit 0
+fi
+
+envguard git check --staged ${ENVGUARD_ALLOW_STAGED:+--allow-staged "$ENVGUARD_ALLOW_STAGED"} || exit 1
+
+if [ -f .env ] && [ -f .env.example ]; then
+  envguard --quiet || exit 1
+fi
+`
+
+// HookPath returns where git looks for the pre-commit hook, honoring
+// core.hooksPath.
+func (r *Repo) HookPath() (string, error) {
+	out, err := r.git("rev-parse", "--git-path", "hooks/pre-commit")
+	if err != nil {
+		return "", err
+	}
+
+	hook := strings.TrimSpace(string(out))
+	if !filepath.IsAbs(hook) {
+		hook = filepath.Join(r.Dir, hook)
+	}
+	return hook, nil
+}
+
+// InstallHook writes PreCommitHook and returns its path. An existing hook
+// that envguard did not write is only replaced with force.
+func (r *Repo) InstallHook(force bool) (string, error) {
+	hook, err := r.HookPath()
+	if err != nil {
+		return "", err
+	}
+
+	existing, err := os.ReadFile(hook)
+	switch {
+	case err == nil && !strings.Contains(string(existing), hookMarker) && !force:
+		return "", fmt.Errorf("%s already exists; use --force to replace it", hook)
+	case err != nil && !os.IsNotExist(err):
+		return "", fmt.Errorf("failed to read %s: %w", hook, err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(hook), 0755); err != nil {
+		return "", fmt.Errorf("failed to create hooks directory: %w", err)
+	}
+	if err := os.WriteFile(hook, []byte(PreCommitHook), 0755); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", hook, err)
+	}
+	// WriteFile keeps the mode of an existing file.
+	if err := os.Chmod(hook, 0755); err != nil {
+		return "", fmt.Errorf("failed to make %s executable: %w", hook, err)
+	}
+	return hook, nil
+}
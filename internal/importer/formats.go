@@ -0,0 +1,327 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// decodeJSON walks the token stream so object keys keep their order.
+func decodeJSON(content []byte) (*node, error) {
+	dec := json.NewDecoder(bytes.NewReader(content))
+	dec.UseNumber()
+
+	root, err := decodeJSONValue(dec)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err == nil {
+		return nil, fmt.Errorf("unexpected data after the top-level value")
+	}
+	return root, nil
+}
+
+func decodeJSONValue(dec *json.Decoder) (*node, error) {
+	token, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch value := token.(type) {
+	case json.Delim:
+		switch value {
+		case '{':
+			n := newMap()
+			for dec.More() {
+				keyToken, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				child, err := decodeJSONValue(dec)
+				if err != nil {
+					return nil, err
+				}
+				n.set(keyToken.(string), child)
+			}
+			_, err := dec.Token()
+			return n, err
+		case '[':
+			n := &node{kind: kindList}
+			for dec.More() {
+				child, err := decodeJSONValue(dec)
+				if err != nil {
+					return nil, err
+				}
+				n.items = append(n.items, child)
+			}
+			_, err := dec.Token()
+			return n, err
+		}
+	case json.Number:
+		return scalar(value.String()), nil
+	case bool:
+		return scalar(strconv.FormatBool(value)), nil
+	case string:
+		return scalar(value), nil
+	case nil:
+		return scalar(""), nil
+	}
+	return nil, fmt.Errorf("unexpected token %v", token)
+}
+
+func decodeYAML(content []byte) (*node, error) {
+	var document yaml.Node
+	if err := yaml.Unmarshal(content, &document); err != nil {
+		return nil, err
+	}
+	if document.Kind == 0 {
+		return newMap(), nil
+	}
+	return convertYAML(&document)
+}
+
+func convertYAML(y *yaml.Node) (*node, error) {
+	switch y.Kind {
+	case yaml.DocumentNode:
+		if len(y.Content) == 0 {
+			return newMap(), nil
+		}
+		return convertYAML(y.Content[0])
+	case yaml.AliasNode:
+		return convertYAML(y.Alias)
+	case yaml.MappingNode:
+		n := newMap()
+		for i := 0; i+1 < len(y.Content); i += 2 {
+			key, value := y.Content[i], y.Content[i+1]
+			if key.Tag == "!!merge" {
+				merged, err := convertYAML(value)
+				if err != nil {
+					return nil, err
+				}
+				for _, k := range merged.keys {
+					if _, exists := n.fields[k]; !exists {
+						n.set(k, merged.fields[k])
+					}
+				}
+				continue
+			}
+			child, err := convertYAML(value)
+			if err != nil {
+				return nil, err
+			}
+			n.set(key.Value, child)
+		}
+		return n, nil
+	case yaml.SequenceNode:
+		n := &node{kind: kindList}
+		for _, item := range y.Content {
+			child, err := convertYAML(item)
+			if err != nil {
+				return nil, err
+			}
+			n.items = append(n.items, child)
+		}
+		return n, nil
+	case yaml.ScalarNode:
+		if y.Tag == "!!null" {
+			return scalar(""), nil
+		}
+		return scalar(y.Value), nil
+	}
+	return nil, fmt.Errorf("line %d: unsupported YAML node", y.Line)
+}
+
+func decodeTOML(content []byte) (*node, error) {
+	var data map[string]interface{}
+	meta, err := toml.Decode(string(content), &data)
+	if err != nil {
+		return nil, err
+	}
+
+	// Maps lose the document order; recover it from the decoder's key list
+	order := make(map[string]int)
+	for i, key := range meta.Keys() {
+		order[key.String()] = i
+	}
+
+	return convertTOML(data, "", order), nil
+}
+
+func convertTOML(value interface{}, path string, order map[string]int) *node {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		local := make(map[string]int)
+		for key := range v {
+			keys = append(keys, key)
+			if i, ok := order[tomlPath(path, key)]; ok {
+				local[key] = i
+			}
+		}
+
+		n := newMap()
+		for _, key := range sortedKeys(keys, local) {
+			n.set(key, convertTOML(v[key], tomlPath(path, key), order))
+		}
+		return n
+	case []map[string]interface{}:
+		n := &node{kind: kindList}
+		for _, item := range v {
+			n.items = append(n.items, convertTOML(item, path, order))
+		}
+		return n
+	case []interface{}:
+		n := &node{kind: kindList}
+		for _, item := range v {
+			n.items = append(n.items, convertTOML(item, path, order))
+		}
+		return n
+	case time.Time:
+		return scalar(v.Format(time.RFC3339Nano))
+	case float64:
+		return scalar(strconv.FormatFloat(v, 'f', -1, 64))
+	default:
+		return scalar(fmt.Sprint(v))
+	}
+}
+
+func tomlPath(parent, key string) string {
+	key = toml.Key{key}.String()
+	if parent == "" {
+		return key
+	}
+	return parent + "." + key
+}
+
+// decodeProperties reads Java .properties files: "key=value", "key: value"
+// or "key value", # and ! comments, backslash line continuations and
+// \t, \n, \uXXXX escapes.
+func decodeProperties(content []byte) (*node, error) {
+	root := newMap()
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	lineNumber := 0
+
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimLeft(scanner.Text(), " \t\f")
+		if line == "" || line[0] == '#' || line[0] == '!' {
+			continue
+		}
+
+		for continuesOnNextLine(line) && scanner.Scan() {
+			lineNumber++
+			line = line[:len(line)-1] + strings.TrimLeft(scanner.Text(), " \t\f")
+		}
+
+		key, value := splitProperty(line)
+		unescapedKey, err := unescapeProperty(key)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+		unescapedValue, err := unescapeProperty(value)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+
+		// Dotted keys become nested maps so they flatten like the other formats
+		if err := insertProperty(root, strings.Split(unescapedKey, "."), unescapedValue); err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+	}
+
+	return root, scanner.Err()
+}
+
+// insertProperty stores value under the dotted path. A key that is both a
+// value and the prefix of other keys, like a=1 and a.b=2, cannot be
+// flattened without losing one of them and is an error.
+func insertProperty(root *node, path []string, value string) error {
+	current := root
+	for i, part := range path[:len(path)-1] {
+		child, ok := current.fields[part]
+		if !ok {
+			child = newMap()
+			current.set(part, child)
+		}
+		if child.kind != kindMap {
+			return fmt.Errorf("%s is set both as a value and as the parent of %s", strings.Join(path[:i+1], "."), strings.Join(path, "."))
+		}
+		current = child
+	}
+
+	last := path[len(path)-1]
+	if existing, ok := current.fields[last]; ok && existing.kind == kindMap {
+		return fmt.Errorf("%s is set both as a value and as the parent of %s", strings.Join(path, "."), strings.Join(path, ".")+"."+existing.keys[0])
+	}
+	current.set(last, scalar(value))
+	return nil
+}
+
+func continuesOnNextLine(line string) bool {
+	backslashes := 0
+	for i := len(line) - 1; i >= 0 && line[i] == '\\'; i-- {
+		backslashes++
+	}
+	return backslashes%2 == 1
+}
+
+func splitProperty(line string) (string, string) {
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			i++
+		case '=', ':', ' ', '\t', '\f':
+			rest := strings.TrimLeft(line[i:], " \t\f")
+			if rest != "" && (rest[0] == '=' || rest[0] == ':') {
+				rest = rest[1:]
+			}
+			return line[:i], strings.TrimLeft(rest, " \t\f")
+		}
+	}
+	return line, ""
+}
+
+func unescapeProperty(s string) (string, error) {
+	if !strings.Contains(s, `\`) {
+		return s, nil
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 >= len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 't':
+			b.WriteByte('\t')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 'f':
+			b.WriteByte('\f')
+		case 'u':
+			if i+5 > len(s) {
+				return "", fmt.Errorf("truncated \\u escape")
+			}
+			code, err := strconv.ParseUint(s[i+1:i+5], 16, 32)
+			if err != nil {
+				return "", fmt.Errorf("invalid \\u escape: %w", err)
+			}
+			b.WriteRune(rune(code))
+			i += 4
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,272 @@
+// Package importer converts structured configuration files into flat
+// dotenv variables.
+package importer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/crabest/envguard/internal/parser"
+)
+
+// Supported input formats.
+const (
+	FormatJSON       = "json"
+	FormatYAML       = "yaml"
+	FormatTOML       = "toml"
+	FormatProperties = "properties"
+)
+
+// Formats lists every supported input format.
+var Formats = []string{FormatJSON, FormatYAML, FormatTOML, FormatProperties}
+
+// Variable is a flattened dotenv variable. Path is where it came from in
+// the source document, e.g. "db.host" or "servers[0].port".
+type Variable struct {
+	Key   string
+	Value string
+	Path  string
+}
+
+// Options controls flattening.
+type Options struct {
+	// Format overrides detection from the file extension.
+	Format string
+	// Prefix is prepended to every key, e.g. "APP_".
+	Prefix string
+}
+
+// DetectFormat picks a format from the file extension.
+func DetectFormat(filename string) (string, error) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".json":
+		return FormatJSON, nil
+	case ".yaml", ".yml":
+		return FormatYAML, nil
+	case ".toml":
+		return FormatTOML, nil
+	case ".properties":
+		return FormatProperties, nil
+	}
+	return "", fmt.Errorf("cannot detect the format of %s (use --format %s)", filename, strings.Join(Formats, "|"))
+}
+
+// ImportFile reads filename and flattens it into variables in document order.
+func ImportFile(filename string, opts Options) ([]Variable, error) {
+	format := opts.Format
+	if format == "" {
+		detected, err := DetectFormat(filename)
+		if err != nil {
+			return nil, err
+		}
+		format = detected
+	}
+
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	return Import(content, format, opts.Prefix)
+}
+
+// Import flattens content in the given format. Nested keys are joined
+// with underscores and upper-cased (db.host → DB_HOST), lists of scalars
+// become comma-separated values, lists of objects are indexed
+// (servers[0].host → SERVERS_0_HOST) and every scalar is converted to its
+// string form. Two paths that flatten to the same key, or a path that does
+// not flatten to a valid variable name, are an error.
+func Import(content []byte, format, prefix string) ([]Variable, error) {
+	var root *node
+	var err error
+
+	switch format {
+	case FormatJSON:
+		root, err = decodeJSON(content)
+	case FormatYAML:
+		root, err = decodeYAML(content)
+	case FormatTOML:
+		root, err = decodeTOML(content)
+	case FormatProperties:
+		root, err = decodeProperties(content)
+	default:
+		return nil, fmt.Errorf("unknown format '%s' (use %s)", format, strings.Join(Formats, ", "))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", format, err)
+	}
+
+	if root.kind != kindMap {
+		return nil, fmt.Errorf("expected the top level of the %s document to be a map of keys", format)
+	}
+
+	var vars []Variable
+	if err := flatten(root, nil, nil, &vars); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]string)
+	for i := range vars {
+		vars[i].Key = prefix + vars[i].Key
+		if !validKey.MatchString(vars[i].Key) {
+			return nil, fmt.Errorf("%s maps to %q, which is not a valid variable name", describePath(vars[i].Path), vars[i].Key)
+		}
+		if previous, ok := seen[vars[i].Key]; ok {
+			return nil, fmt.Errorf("%s and %s both map to %s", previous, vars[i].Path, vars[i].Key)
+		}
+		seen[vars[i].Key] = vars[i].Path
+	}
+
+	return vars, nil
+}
+
+type nodeKind int
+
+const (
+	kindScalar nodeKind = iota
+	kindMap
+	kindList
+)
+
+// node is an ordered document tree shared by all decoders.
+type node struct {
+	kind   nodeKind
+	value  string
+	keys   []string
+	fields map[string]*node
+	items  []*node
+}
+
+func newMap() *node {
+	return &node{kind: kindMap, fields: make(map[string]*node)}
+}
+
+func (n *node) set(key string, value *node) {
+	if _, exists := n.fields[key]; !exists {
+		n.keys = append(n.keys, key)
+	}
+	n.fields[key] = value
+}
+
+func scalar(value string) *node {
+	return &node{kind: kindScalar, value: value}
+}
+
+// validKey matches the variable names envguard writes.
+var validKey = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+func flatten(n *node, keyParts, pathParts []string, vars *[]Variable) error {
+	switch n.kind {
+	case kindMap:
+		for _, key := range n.keys {
+			path := extend(pathParts, key)
+			name := KeyName(key)
+			if name == "" {
+				return fmt.Errorf("%s has no letters or digits to use in a variable name", describePath(strings.Join(path, ".")))
+			}
+			if err := flatten(n.fields[key], extend(keyParts, name), path, vars); err != nil {
+				return err
+			}
+		}
+	case kindList:
+		if values, ok := scalarList(n); ok {
+			*vars = append(*vars, Variable{Key: strings.Join(keyParts, "_"), Value: strings.Join(values, ","), Path: strings.Join(pathParts, ".")})
+			return nil
+		}
+		for i, item := range n.items {
+			index := strconv.Itoa(i)
+			path := extend(pathParts[:len(pathParts)-1], pathParts[len(pathParts)-1]+"["+index+"]")
+			if err := flatten(item, extend(keyParts, index), path, vars); err != nil {
+				return err
+			}
+		}
+	default:
+		*vars = append(*vars, Variable{Key: strings.Join(keyParts, "_"), Value: n.value, Path: strings.Join(pathParts, ".")})
+	}
+	return nil
+}
+
+// describePath names a source path in an error, quoting it so empty or
+// blank keys are visible.
+func describePath(path string) string {
+	return fmt.Sprintf("source key %q", path)
+}
+
+// extend returns a copy of parts with part appended, so sibling branches
+// never share a backing array.
+func extend(parts []string, part string) []string {
+	return append(append(make([]string, 0, len(parts)+1), parts...), part)
+}
+
+func scalarList(n *node) ([]string, bool) {
+	values := make([]string, 0, len(n.items))
+	for _, item := range n.items {
+		if item.kind != kindScalar {
+			return nil, false
+		}
+		values = append(values, item.value)
+	}
+	return values, true
+}
+
+// KeyName converts one segment of a source key into dotenv form:
+// camelCase is split (HTTPPort → HTTP_PORT), anything other than
+// letters and digits becomes an underscore, and the result is
+// upper-cased (apiKey → API_KEY, "max-conns" → MAX_CONNS).
+func KeyName(segment string) string {
+	var b strings.Builder
+	runes := []rune(segment)
+	for i, r := range runes {
+		switch {
+		case unicode.IsUpper(r) && i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) ||
+			(unicode.IsUpper(runes[i-1]) && i+1 < len(runes) && unicode.IsLower(runes[i+1]))):
+			b.WriteByte('_')
+			b.WriteRune(r)
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(unicode.ToUpper(r))
+		default:
+			b.WriteByte('_')
+		}
+	}
+
+	name := strings.Trim(b.String(), "_")
+	for strings.Contains(name, "__") {
+		name = strings.ReplaceAll(name, "__", "_")
+	}
+	return name
+}
+
+// sortedKeys orders map keys by their position in order, falling back to
+// alphabetical order for keys it does not list.
+func sortedKeys(keys []string, order map[string]int) []string {
+	sorted := append([]string{}, keys...)
+	sort.SliceStable(sorted, func(a, b int) bool {
+		ia, okA := order[sorted[a]]
+		ib, okB := order[sorted[b]]
+		switch {
+		case okA && okB:
+			return ia < ib
+		case okA != okB:
+			return okA
+		default:
+			return sorted[a] < sorted[b]
+		}
+	})
+	return sorted
+}
+
+// Render writes vars as dotenv text, quoting values where needed so they
+// read back unchanged.
+func Render(vars []Variable) string {
+	var b strings.Builder
+	for _, v := range vars {
+		b.WriteString(parser.FormatAssignment(v.Key, v.Value) + "\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,169 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+)
+
+func assertVars(t *testing.T, vars []Variable, expected ...string) {
+	t.Helper()
+	got := make([]string, len(vars))
+	for i, v := range vars {
+		got[i] = v.Key + "=" + v.Value
+	}
+	if strings.Join(got, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Unexpected variables:\n%s\nexpected:\n%s", strings.Join(got, "\n"), strings.Join(expected, "\n"))
+	}
+}
+
+func TestImportJSON(t *testing.T) {
+	content := `{
+  "port": 8080,
+  "ratio": 0.25,
+  "debug": true,
+  "db": {"host": "localhost", "maxConns": 10, "password": null},
+  "hosts": ["a", "b"],
+  "servers": [{"name": "x"}, {"name": "y"}]
+}`
+
+	vars, err := Import([]byte(content), FormatJSON, "")
+	if err != nil {
+		t.Fatalf("Failed to import: %v", err)
+	}
+	assertVars(t, vars,
+		"PORT=8080",
+		"RATIO=0.25",
+		"DEBUG=true",
+		"DB_HOST=localhost",
+		"DB_MAX_CONNS=10",
+		"DB_PASSWORD=",
+		"HOSTS=a,b",
+		"SERVERS_0_NAME=x",
+		"SERVERS_1_NAME=y",
+	)
+	if vars[7].Path != "servers[0].name" {
+		t.Errorf("Unexpected path: %s", vars[7].Path)
+	}
+}
+
+func TestImportYAML(t *testing.T) {
+	content := `defaults: &defaults
+  timeout: 30s
+api:
+  <<: *defaults
+  url: https://api.example.com
+  enabled: yes
+empty: ~
+`
+	vars, err := Import([]byte(content), FormatYAML, "APP_")
+	if err != nil {
+		t.Fatalf("Failed to import: %v", err)
+	}
+	assertVars(t, vars,
+		"APP_DEFAULTS_TIMEOUT=30s",
+		"APP_API_TIMEOUT=30s",
+		"APP_API_URL=https://api.example.com",
+		"APP_API_ENABLED=yes",
+		"APP_EMPTY=",
+	)
+}
+
+func TestImportTOML(t *testing.T) {
+	content := `title = "api"
+port = 8080
+
+[database]
+host = "db"
+ports = [5432, 5433]
+ratio = 1.5
+
+[[workers]]
+name = "mail"
+`
+	vars, err := Import([]byte(content), FormatTOML, "")
+	if err != nil {
+		t.Fatalf("Failed to import: %v", err)
+	}
+	assertVars(t, vars,
+		"TITLE=api",
+		"PORT=8080",
+		"DATABASE_HOST=db",
+		"DATABASE_PORTS=5432,5433",
+		"DATABASE_RATIO=1.5",
+		"WORKERS_0_NAME=mail",
+	)
+}
+
+func TestImportProperties(t *testing.T) {
+	content := `# comment
+! also a comment
+db.host = localhost
+db.port: 5432
+app.name My App
+message=line one\
+    continued
+greeting=café\tok
+`
+	vars, err := Import([]byte(content), FormatProperties, "")
+	if err != nil {
+		t.Fatalf("Failed to import: %v", err)
+	}
+	assertVars(t, vars,
+		"DB_HOST=localhost",
+		"DB_PORT=5432",
+		"APP_NAME=My App",
+		"MESSAGE=line onecontinued",
+		"GREETING=café\tok",
+	)
+}
+
+func TestImportErrors(t *testing.T) {
+	if _, err := Import([]byte(`{"db.host": 1, "db": {"host": 2}}`), FormatJSON, ""); err == nil || !strings.Contains(err.Error(), "both map to DB_HOST") {
+		t.Errorf("Expected a key collision error, got %v", err)
+	}
+	if _, err := Import([]byte(`[1, 2]`), FormatJSON, ""); err == nil {
+		t.Error("Expected an error for a top-level list")
+	}
+	if _, err := DetectFormat("config.ini"); err == nil {
+		t.Error("Expected an error for an unknown extension")
+	}
+
+	invalid := map[string]string{
+		`{"": 1}`:             `source key ""`,
+		`{"a": {"-": 1}}`:     `source key "a.-"`,
+		`{"größe": 1}`:        `source key "größe"`,
+		`{"0": {"port": 1}}`:  `source key "0.port"`,
+		`{"ok": 1, "-": "x"}`: `source key "-"`,
+	}
+	for content, want := range invalid {
+		_, err := Import([]byte(content), FormatJSON, "")
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Import(%s): expected an error naming %s, got %v", content, want, err)
+		}
+	}
+	if _, err := Import([]byte(`{"0": {"port": 1}}`), FormatJSON, "APP_"); err != nil {
+		t.Errorf("A prefix should make a leading digit valid, got %v", err)
+	}
+
+	for _, content := range []string{"a=1\na.b=2\n", "a.b=2\na=1\n"} {
+		_, err := Import([]byte(content), FormatProperties, "")
+		if err == nil || !strings.Contains(err.Error(), "line 2: a is set both as a value and as the parent of a.b") {
+			t.Errorf("Import(%q): expected a collision error, got %v", content, err)
+		}
+	}
+}
+
+func TestKeyName(t *testing.T) {
+	tests := map[string]string{
+		"host":      "HOST",
+		"apiKey":    "API_KEY",
+		"max-conns": "MAX_CONNS",
+		"HTTPPort":  "HTTP_PORT",
+		"v2Enabled": "V2_ENABLED",
+	}
+	for input, expected := range tests {
+		if got := KeyName(input); got != expected {
+			t.Errorf("KeyName(%q) = %q, expected %q", input, got, expected)
+		}
+	}
+}
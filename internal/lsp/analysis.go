@@ -0,0 +1,431 @@
+package lsp
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/crabest/envguard/internal/envmanager"
+	"github.com/crabest/envguard/internal/parser"
+	"github.com/crabest/envguard/internal/validator"
+)
+
+// source names envguard as the origin of diagnostics.
+const source = "envguard"
+
+// Diagnostic codes added by the server on top of the validator's issues.
+const (
+	CodeParseError      = "parse-error"
+	CodeMissingVariable = "missing-variable"
+	CodeExtraVariable   = "extra-variable"
+)
+
+// analysis is everything known about one document: its entries, the
+// schema of its example file and the diagnostics for it.
+type analysis struct {
+	doc         *document
+	example     bool
+	entries     []parser.Entry
+	schema      *validator.Schema
+	missing     []string
+	diagnostics []Diagnostic
+}
+
+// isExample reports whether path is a template such as .env.example
+// rather than an env file.
+func isExample(path string) bool {
+	return strings.HasSuffix(filepath.Base(path), ".example")
+}
+
+// examplePath returns the example file an env file is validated against:
+// .env.example next to it, or in the project root for environments stored
+// in .envguard/.
+func examplePath(path string) string {
+	dir := filepath.Dir(path)
+	if filepath.Base(dir) == envmanager.EnvGuardDir {
+		dir = filepath.Dir(dir)
+	}
+	return filepath.Join(dir, ".env.example")
+}
+
+// environmentFor picks the environment @required-in rules are checked
+// against, as validation on the command line does: the name of a stored
+// environment, or the active environment for a project's root .env.
+func environmentFor(path string) string {
+	dir, base := filepath.Split(path)
+	if filepath.Base(dir) == envmanager.EnvGuardDir {
+		return strings.TrimSuffix(base, ".env")
+	}
+	if base != ".env" {
+		return ""
+	}
+
+	manager, err := envmanager.NewManagerAt(dir)
+	if err != nil {
+		return ""
+	}
+	active, err := manager.GetActiveEnvironment()
+	if err != nil {
+		return ""
+	}
+	return active
+}
+
+var errorLinePattern = regexp.MustCompile(`line (\d+)`)
+
+// errorLine finds the 0-based line a parse error refers to, defaulting to
+// the first line.
+func errorLine(err error) int {
+	match := errorLinePattern.FindStringSubmatch(err.Error())
+	if match == nil {
+		return 0
+	}
+	line, _ := strconv.Atoi(match[1])
+	return line - 1
+}
+
+// analyze parses doc, loads the schema from its example (an open document
+// takes precedence over the file on disk) and computes its diagnostics.
+func (s *Server) analyze(doc *document) *analysis {
+	a := &analysis{doc: doc, example: isExample(doc.path), diagnostics: []Diagnostic{}}
+
+	entries, err := parser.ParseEntries([]byte(doc.text))
+	if err != nil {
+		a.diagnostics = append(a.diagnostics, Diagnostic{
+			Range:    doc.lineRange(errorLine(err)),
+			Severity: SeverityError,
+			Code:     CodeParseError,
+			Source:   source,
+			Message:  err.Error(),
+		})
+	}
+	a.entries = entries
+
+	if a.example {
+		if err == nil {
+			a.schema = validator.NewSchema(entries)
+			a.schema.File = doc.path
+		}
+	} else {
+		a.schema = s.loadSchema(examplePath(doc.path))
+	}
+
+	if err != nil {
+		return a
+	}
+
+	issues, _ := validator.CheckDialect(doc.path, []byte(doc.text), s.dialect)
+	issues = append(issues, validator.CheckReferences(doc.path, entries, s.interpolation)...)
+
+	switch {
+	case a.example:
+		issues = append(issues, a.schema.CheckRules()...)
+	case a.schema != nil:
+		issues = append(issues, a.validate()...)
+		issues = append(issues, validator.CheckDeprecations(doc.path, entries, a.schema)...)
+		issues = append(issues, validator.CheckValues(doc.path, entries, a.schema)...)
+	}
+
+	for _, issue := range issues {
+		if issue.File == doc.path {
+			a.diagnostics = append(a.diagnostics, a.diagnostic(issue))
+		}
+	}
+	sort.SliceStable(a.diagnostics, func(i, j int) bool {
+		return a.diagnostics[i].Range.Start.Line < a.diagnostics[j].Range.Start.Line
+	})
+	return a
+}
+
+// loadSchema reads the example at path, returning nil when there is none
+// or it cannot be parsed.
+func (s *Server) loadSchema(path string) *validator.Schema {
+	var content []byte
+	if doc := s.documentAt(path); doc != nil {
+		content = []byte(doc.text)
+	} else {
+		var err error
+		if content, err = os.ReadFile(path); err != nil {
+			return nil
+		}
+	}
+
+	entries, err := parser.ParseEntries(content)
+	if err != nil {
+		return nil
+	}
+	schema := validator.NewSchema(entries)
+	schema.File = path
+	return schema
+}
+
+// validate compares the document with its schema, adding a diagnostic on
+// the first line for every missing key and returning issues for extra
+// keys.
+func (a *analysis) validate() []validator.Issue {
+	vars := make(parser.EnvVars, len(a.entries))
+	for _, entry := range a.entries {
+		vars[entry.Key] = entry.Value
+	}
+
+	result := validator.ValidateWithSchema(vars, a.schema, environmentFor(a.doc.path))
+	exampleName := filepath.Base(a.schema.File)
+
+	a.missing = result.MissingVars
+	for _, key := range result.MissingVars {
+		message := fmt.Sprintf("%s is missing (declared in %s)", key, exampleName)
+		if reason, ok := result.Reasons[key]; ok {
+			message = fmt.Sprintf("%s is missing: %s", key, reason)
+		}
+		a.diagnostics = append(a.diagnostics, Diagnostic{
+			Range:    a.doc.lineRange(0),
+			Severity: SeverityError,
+			Code:     CodeMissingVariable,
+			Source:   source,
+			Message:  message,
+			Data:     &DiagnosticData{Key: key},
+		})
+	}
+
+	issues := result.Issues
+	first := make(map[string]parser.Entry)
+	for _, entry := range a.entries {
+		if _, seen := first[entry.Key]; !seen {
+			first[entry.Key] = entry
+		}
+	}
+	for _, key := range result.ExtraVars {
+		issues = append(issues, validator.Issue{
+			Severity: validator.SeverityWarning,
+			Code:     CodeExtraVariable,
+			Key:      key,
+			File:     a.doc.path,
+			Line:     first[key].Line,
+			Message:  fmt.Sprintf("%s is not declared in %s", key, exampleName),
+		})
+	}
+	return issues
+}
+
+// diagnostic converts issue, highlighting the key when the issue is about
+// an assignment on its line and the whole line otherwise.
+func (a *analysis) diagnostic(issue validator.Issue) Diagnostic {
+	line := issue.Line - 1
+	if line < 0 {
+		line = 0
+	}
+
+	r := a.doc.lineRange(line)
+	for _, entry := range a.entries {
+		if entry.Line == issue.Line && entry.Key == issue.Key {
+			r = a.keyRange(entry)
+			break
+		}
+	}
+
+	severity := SeverityError
+	if issue.Severity == validator.SeverityWarning {
+		severity = SeverityWarning
+	}
+	return Diagnostic{Range: r, Severity: severity, Code: issue.Code, Source: source, Message: issue.Message}
+}
+
+func (a *analysis) keyRange(entry parser.Entry) Range {
+	return Range{
+		Start: a.doc.position(entry.Line-1, entry.Column),
+		End:   a.doc.position(entry.Line-1, entry.Column+len(entry.Key)),
+	}
+}
+
+// hover describes the key under pos from the example file.
+func (a *analysis) hover(pos Position) *Hover {
+	if a.schema == nil {
+		return nil
+	}
+
+	word, start, end := a.doc.wordAt(pos)
+	spec, ok := a.schema.Specs[word]
+	if !ok {
+		return nil
+	}
+
+	return &Hover{
+		Contents: MarkupContent{Kind: "markdown", Value: describeKey(a.schema, spec)},
+		Range:    Range{Start: a.doc.position(pos.Line, start), End: a.doc.position(pos.Line, end)},
+	}
+}
+
+// describeKey renders the example's comment and annotations for a key as
+// markdown. Example values of secret keys are left out.
+func describeKey(schema *validator.Schema, spec *validator.KeySpec) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "**%s**", spec.Key)
+	if valueType, ok := spec.Annotations[validator.AnnotationType]; ok {
+		fmt.Fprintf(&b, " · `%s`", valueType)
+	}
+	b.WriteString("\n")
+
+	if len(spec.Description) > 0 {
+		fmt.Fprintf(&b, "\n%s\n", strings.Join(spec.Description, "\n"))
+	}
+	if values := schema.Enum(spec.Key); len(values) > 0 {
+		fmt.Fprintf(&b, "\nOne of: `%s`\n", strings.Join(values, "`, `"))
+	}
+
+	var annotations []string
+	for name, args := range spec.Annotations {
+		if name == validator.AnnotationType || name == validator.AnnotationEnum {
+			continue
+		}
+		annotations = append(annotations, strings.TrimSpace("@"+name+" "+args))
+	}
+	if len(annotations) > 0 {
+		sort.Strings(annotations)
+		fmt.Fprintf(&b, "\n`%s`\n", strings.Join(annotations, "` `"))
+	}
+
+	if spec.Example != "" && !schema.IsSecret(spec.Key, spec.Example) {
+		fmt.Fprintf(&b, "\nExample: `%s`\n", spec.Example)
+	}
+	fmt.Fprintf(&b, "\nDeclared in %s:%d", filepath.Base(schema.File), spec.Line)
+	return b.String()
+}
+
+// completion proposes keys from the example at the start of a line and
+// known variables inside ${...}.
+func (a *analysis) completion(pos Position) []CompletionItem {
+	items := []CompletionItem{}
+	prefix := a.doc.line(pos.Line)[:a.doc.byteColumn(pos)]
+
+	if i := strings.LastIndex(prefix, "${"); i >= 0 && isName(prefix[i+2:]) {
+		seen := make(map[string]bool)
+		for _, entry := range a.entries {
+			if entry.Line-1 != pos.Line && !seen[entry.Key] {
+				seen[entry.Key] = true
+				items = append(items, CompletionItem{Label: entry.Key, Kind: CompletionItemKindVariable})
+			}
+		}
+		if a.schema != nil {
+			for _, key := range a.schema.Keys {
+				if !seen[key] {
+					seen[key] = true
+					items = append(items, CompletionItem{Label: key, Kind: CompletionItemKindVariable, Detail: "from " + filepath.Base(a.schema.File)})
+				}
+			}
+		}
+		return items
+	}
+
+	word := strings.TrimPrefix(strings.TrimLeft(prefix, " \t"), "export ")
+	if a.example || a.schema == nil || !isName(word) {
+		return items
+	}
+
+	defined := make(map[string]bool)
+	for _, entry := range a.entries {
+		if entry.Line-1 != pos.Line {
+			defined[entry.Key] = true
+		}
+	}
+	missing := make(map[string]bool)
+	for _, key := range a.missing {
+		missing[key] = true
+	}
+
+	for _, key := range a.schema.Keys {
+		if defined[key] {
+			continue
+		}
+		spec := a.schema.Specs[key]
+
+		detail := a.schema.Type(key)
+		if a.schema.Optional(key) {
+			detail += ", optional"
+		}
+		sortText := "1" + key
+		if missing[key] {
+			sortText = "0" + key
+		}
+		items = append(items, CompletionItem{
+			Label:         key,
+			Kind:          CompletionItemKindVariable,
+			Detail:        detail,
+			Documentation: &MarkupContent{Kind: "markdown", Value: describeKey(a.schema, spec)},
+			SortText:      sortText,
+			InsertText:    key + "=",
+		})
+	}
+	return items
+}
+
+func isName(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if !isNameByte(s[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// codeActions offers to append missing keys: one action per missing-key
+// diagnostic in the request, and one adding all of them.
+func (a *analysis) codeActions(diagnostics []Diagnostic) []CodeAction {
+	actions := []CodeAction{}
+	if len(a.missing) == 0 {
+		return actions
+	}
+
+	missing := make(map[string]bool)
+	for _, key := range a.missing {
+		missing[key] = true
+	}
+
+	for _, diagnostic := range diagnostics {
+		if diagnostic.Code != CodeMissingVariable || diagnostic.Data == nil || !missing[diagnostic.Data.Key] {
+			continue
+		}
+		key := diagnostic.Data.Key
+		actions = append(actions, CodeAction{
+			Title:       fmt.Sprintf("Add %s from %s", key, filepath.Base(a.schema.File)),
+			Kind:        "quickfix",
+			Diagnostics: []Diagnostic{diagnostic},
+			IsPreferred: true,
+			Edit:        a.appendKeys([]string{key}),
+		})
+	}
+
+	if len(a.missing) > 1 {
+		actions = append(actions, CodeAction{
+			Title: fmt.Sprintf("Add all %d missing variables from %s", len(a.missing), filepath.Base(a.schema.File)),
+			Kind:  "quickfix",
+			Edit:  a.appendKeys(a.missing),
+		})
+	}
+	return actions
+}
+
+// appendKeys adds keys at the end of the document with the example's
+// values, leaving secrets and @env-specific values empty.
+func (a *analysis) appendKeys(keys []string) WorkspaceEdit {
+	var b strings.Builder
+	if a.doc.text != "" && !strings.HasSuffix(a.doc.text, "\n") {
+		b.WriteString("\n")
+	}
+	for _, key := range keys {
+		value := a.schema.Specs[key].Example
+		if a.schema.IsSecret(key, value) || a.schema.IsEnvSpecific(key) {
+			value = ""
+		}
+		b.WriteString(parser.FormatAssignment(key, value))
+		b.WriteString("\n")
+	}
+
+	end := a.doc.end()
+	return WorkspaceEdit{Changes: map[string][]TextEdit{
+		a.doc.uri: {{Range: Range{Start: end, End: end}, NewText: b.String()}},
+	}}
+}
@@ -0,0 +1,119 @@
+package lsp
+
+import (
+	"net/url"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+// document is an open text document. Positions from the client count
+// UTF-16 code units; the parser works in bytes, so every position crosses
+// position or byteColumn.
+type document struct {
+	uri     string
+	path    string
+	version int
+	text    string
+	lines   []string
+}
+
+func newDocument(uri string, version int, text string) *document {
+	return &document{
+		uri:     uri,
+		path:    uriToPath(uri),
+		version: version,
+		text:    text,
+		lines:   strings.Split(text, "\n"),
+	}
+}
+
+// line returns the content of line without its line break, or "" past the
+// end of the document.
+func (d *document) line(line int) string {
+	if line < 0 || line >= len(d.lines) {
+		return ""
+	}
+	return strings.TrimSuffix(d.lines[line], "\r")
+}
+
+// position converts a 0-based line and byte column to a protocol position.
+func (d *document) position(line, column int) Position {
+	text := d.line(line)
+	if column > len(text) {
+		column = len(text)
+	}
+	return Position{Line: line, Character: utf16Length(text[:column])}
+}
+
+// byteColumn converts a protocol position to a byte column on its line.
+func (d *document) byteColumn(pos Position) int {
+	text := d.line(pos.Line)
+	units := 0
+	for i, r := range text {
+		if units >= pos.Character {
+			return i
+		}
+		if r >= 0x10000 {
+			units += 2
+		} else {
+			units++
+		}
+	}
+	return len(text)
+}
+
+// lineRange covers line from its first to its last character.
+func (d *document) lineRange(line int) Range {
+	return Range{Start: d.position(line, 0), End: d.position(line, len(d.line(line)))}
+}
+
+// end is the position after the last character of the document.
+func (d *document) end() Position {
+	last := len(d.lines) - 1
+	return d.position(last, len(d.line(last)))
+}
+
+// wordAt returns the variable name under pos and its byte span.
+func (d *document) wordAt(pos Position) (string, int, int) {
+	text := d.line(pos.Line)
+	column := d.byteColumn(pos)
+
+	start := column
+	for start > 0 && isNameByte(text[start-1]) {
+		start--
+	}
+	end := column
+	for end < len(text) && isNameByte(text[end]) {
+		end++
+	}
+	return text[start:end], start, end
+}
+
+func isNameByte(c byte) bool {
+	return c == '_' || c == '.' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+func utf16Length(s string) int {
+	n := 0
+	for len(s) > 0 {
+		r, size := utf8.DecodeRuneInString(s)
+		s = s[size:]
+		if r >= 0x10000 {
+			n += 2
+		} else {
+			n++
+		}
+	}
+	return n
+}
+
+// uriToPath returns the file path of a file:// URI, or the URI itself for
+// other schemes.
+func uriToPath(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return uri
+	}
+	return filepath.FromSlash(u.Path)
+}
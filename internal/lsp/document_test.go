@@ -0,0 +1,29 @@
+package lsp
+
+import "testing"
+
+func TestDocumentPositions(t *testing.T) {
+	// é is two bytes and one UTF-16 unit; 🔑 is four bytes and two units.
+	doc := newDocument("file:///project/.env", 1, "NAME=é🔑x\r\nPORT=1")
+
+	if got := doc.position(0, len("NAME=é🔑")); got != (Position{Line: 0, Character: 8}) {
+		t.Errorf("Expected character 8, got %+v", got)
+	}
+	if got := doc.byteColumn(Position{Line: 0, Character: 8}); got != len("NAME=é🔑") {
+		t.Errorf("Expected byte column %d, got %d", len("NAME=é🔑"), got)
+	}
+	if got := doc.lineRange(0).End; got != (Position{Line: 0, Character: 9}) {
+		t.Errorf("Expected the line to end before \\r, got %+v", got)
+	}
+	if got := doc.end(); got != (Position{Line: 1, Character: 6}) {
+		t.Errorf("Unexpected end %+v", got)
+	}
+
+	word, start, end := doc.wordAt(Position{Line: 1, Character: 2})
+	if word != "PORT" || start != 0 || end != 4 {
+		t.Errorf("Expected PORT at 0-4, got %q at %d-%d", word, start, end)
+	}
+	if doc.path != "/project/.env" {
+		t.Errorf("Unexpected path %s", doc.path)
+	}
+}
@@ -0,0 +1,268 @@
+// Package lsp implements a language server for .env and .env.example
+// files. It reports the lint and validation problems envguard finds as
+// diagnostics, shows a key's documentation from the example file on
+// hover, completes keys declared in the example and offers to add missing
+// ones.
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/crabest/envguard/internal/parser"
+)
+
+// ErrExitWithoutShutdown is returned by Run when the client sent exit
+// without a shutdown request first; the server should exit with status 1.
+var ErrExitWithoutShutdown = errors.New("exit received before shutdown")
+
+// Server speaks the Language Server Protocol over a pair of streams,
+// normally stdin and stdout. Documents are synchronized in full on every
+// change.
+type Server struct {
+	in  *bufio.Reader
+	out io.Writer
+
+	docs          map[string]*document
+	dialect       parser.Dialect
+	interpolation parser.Interpolation
+	shutdown      bool
+}
+
+// NewServer creates a server reading requests from in and writing
+// responses and notifications to out.
+func NewServer(in io.Reader, out io.Writer) *Server {
+	return &Server{
+		in:            bufio.NewReader(in),
+		out:           out,
+		docs:          make(map[string]*document),
+		dialect:       parser.DialectGodotenv,
+		interpolation: parser.InterpolateGodotenv,
+	}
+}
+
+// Run serves requests until the client sends exit or closes the input.
+func (s *Server) Run() error {
+	for {
+		msg, err := readMessage(s.in)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			var rpcErr *responseError
+			if errors.As(err, &rpcErr) {
+				if err := s.reply(nil, nil, rpcErr); err != nil {
+					return err
+				}
+				continue
+			}
+			return err
+		}
+
+		switch {
+		case msg.Method == "exit":
+			if !s.shutdown {
+				return ErrExitWithoutShutdown
+			}
+			return nil
+		case msg.Method == "":
+			// A response to a request we never send.
+		case msg.ID == nil:
+			if err := s.notification(msg); err != nil {
+				s.logError(err)
+			}
+		default:
+			result, err := s.request(msg)
+			var rpcErr *responseError
+			if err != nil && !errors.As(err, &rpcErr) {
+				rpcErr = &responseError{Code: codeInvalidParams, Message: err.Error()}
+			}
+			if err := s.reply(msg.ID, result, rpcErr); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (s *Server) request(msg *message) (interface{}, error) {
+	if s.shutdown {
+		return nil, &responseError{Code: codeInvalidRequest, Message: "server is shutting down"}
+	}
+
+	switch msg.Method {
+	case "initialize":
+		return s.initialize(msg.Params)
+	case "shutdown":
+		s.shutdown = true
+		return nil, nil
+	case "textDocument/hover":
+		var params textDocumentPositionParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return nil, err
+		}
+		doc := s.docs[params.TextDocument.URI]
+		if doc == nil {
+			return nil, nil
+		}
+		if hover := s.analyze(doc).hover(params.Position); hover != nil {
+			return hover, nil
+		}
+		return nil, nil
+	case "textDocument/completion":
+		var params textDocumentPositionParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return nil, err
+		}
+		doc := s.docs[params.TextDocument.URI]
+		if doc == nil {
+			return []CompletionItem{}, nil
+		}
+		return s.analyze(doc).completion(params.Position), nil
+	case "textDocument/codeAction":
+		var params codeActionParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return nil, err
+		}
+		doc := s.docs[params.TextDocument.URI]
+		if doc == nil {
+			return []CodeAction{}, nil
+		}
+		return s.analyze(doc).codeActions(params.Context.Diagnostics), nil
+	}
+	return nil, &responseError{Code: codeMethodNotFound, Message: fmt.Sprintf("method %s is not supported", msg.Method)}
+}
+
+// initialize reads the optional "dialect" and "interpolation" settings,
+// which match the lint --dialect and --interpolation flags.
+func (s *Server) initialize(raw json.RawMessage) (interface{}, error) {
+	var params initializeParams
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &params); err != nil {
+			return nil, err
+		}
+	}
+
+	if value := params.InitializationOptions.Dialect; value != "" {
+		dialect, err := parser.ParseDialect(value)
+		if err != nil {
+			return nil, err
+		}
+		s.dialect = dialect
+	}
+	if value := params.InitializationOptions.Interpolation; value != "" {
+		mode, err := parser.ParseInterpolation(value)
+		if err != nil {
+			return nil, err
+		}
+		s.interpolation = mode
+	}
+
+	return map[string]interface{}{
+		"capabilities": map[string]interface{}{
+			"textDocumentSync": map[string]interface{}{
+				"openClose": true,
+				"change":    1,
+				"save":      map[string]interface{}{"includeText": false},
+			},
+			"hoverProvider":      true,
+			"completionProvider": map[string]interface{}{"triggerCharacters": []string{"{"}},
+			"codeActionProvider": map[string]interface{}{"codeActionKinds": []string{"quickfix"}},
+		},
+		"serverInfo": map[string]interface{}{"name": "envguard"},
+	}, nil
+}
+
+func (s *Server) notification(msg *message) error {
+	switch msg.Method {
+	case "textDocument/didOpen":
+		var params didOpenParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return err
+		}
+		item := params.TextDocument
+		s.docs[item.URI] = newDocument(item.URI, item.Version, item.Text)
+	case "textDocument/didChange":
+		var params didChangeParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return err
+		}
+		if len(params.ContentChanges) == 0 {
+			return nil
+		}
+		item := params.TextDocument
+		text := params.ContentChanges[len(params.ContentChanges)-1].Text
+		s.docs[item.URI] = newDocument(item.URI, item.Version, text)
+	case "textDocument/didClose":
+		var params didCloseParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return err
+		}
+		delete(s.docs, params.TextDocument.URI)
+		if err := s.publish(params.TextDocument.URI, []Diagnostic{}); err != nil {
+			return err
+		}
+	case "textDocument/didSave":
+		// Saving an example may change what other documents are
+		// validated against; republish below.
+	default:
+		return nil
+	}
+
+	// Any change can affect every open document, since env files are
+	// validated against their example, so all of them are republished.
+	return s.publishAll()
+}
+
+func (s *Server) publishAll() error {
+	uris := make([]string, 0, len(s.docs))
+	for uri := range s.docs {
+		uris = append(uris, uri)
+	}
+	sort.Strings(uris)
+
+	for _, uri := range uris {
+		if err := s.publish(uri, s.analyze(s.docs[uri]).diagnostics); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Server) publish(uri string, diagnostics []Diagnostic) error {
+	return writeMessage(s.out, request{
+		JSONRPC: "2.0",
+		Method:  "textDocument/publishDiagnostics",
+		Params:  publishDiagnosticsParams{URI: uri, Diagnostics: diagnostics},
+	})
+}
+
+// logError reports a failed notification through window/logMessage, as
+// there is no response to carry it.
+func (s *Server) logError(err error) {
+	writeMessage(s.out, request{
+		JSONRPC: "2.0",
+		Method:  "window/logMessage",
+		Params:  map[string]interface{}{"type": 1, "message": "envguard: " + err.Error()},
+	})
+}
+
+func (s *Server) reply(id *json.RawMessage, result interface{}, rpcErr *responseError) error {
+	if rpcErr != nil {
+		return writeMessage(s.out, errorResponse{JSONRPC: "2.0", ID: id, Error: rpcErr})
+	}
+	return writeMessage(s.out, response{JSONRPC: "2.0", ID: id, Result: result})
+}
+
+// documentAt returns the open document for path, if any.
+func (s *Server) documentAt(path string) *document {
+	for _, doc := range s.docs {
+		if doc.path == path {
+			return doc
+		}
+	}
+	return nil
+}
@@ -0,0 +1,376 @@
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testExample = `# Port the server listens on
+# @type int
+PORT=3000
+# @enum debug,info,warn
+LOG_LEVEL=info
+# @optional
+SENTRY_DSN=
+API_TOKEN=<your-token>
+`
+
+// client drives a Server through the protocol the way an editor does.
+type client struct {
+	t        *testing.T
+	messages chan *message
+	out      io.WriteCloser
+	nextID   int
+	queue    []*message
+	done     chan error
+}
+
+func newClient(t *testing.T) *client {
+	t.Helper()
+	serverIn, clientOut := io.Pipe()
+	clientIn, serverOut := io.Pipe()
+
+	c := &client{t: t, messages: make(chan *message, 100), out: clientOut, done: make(chan error, 1)}
+	go func() {
+		err := NewServer(serverIn, serverOut).Run()
+		serverOut.Close()
+		c.done <- err
+	}()
+
+	// Read continuously, as an editor does, so the server never blocks
+	// writing while the client writes a request.
+	go func() {
+		in := bufio.NewReader(clientIn)
+		for {
+			msg, err := readMessage(in)
+			if err != nil {
+				close(c.messages)
+				return
+			}
+			c.messages <- msg
+		}
+	}()
+	return c
+}
+
+func (c *client) send(v interface{}) {
+	c.t.Helper()
+	if err := writeMessage(c.out, v); err != nil {
+		c.t.Fatalf("Failed to send: %v", err)
+	}
+}
+
+func (c *client) read() *message {
+	c.t.Helper()
+	select {
+	case msg := <-c.messages:
+		if msg == nil {
+			c.t.Fatal("Server closed the connection")
+		}
+		return msg
+	case <-time.After(5 * time.Second):
+		c.t.Fatal("Timed out waiting for the server")
+		return nil
+	}
+}
+
+func (c *client) notify(method string, params interface{}) {
+	c.t.Helper()
+	c.send(request{JSONRPC: "2.0", Method: method, Params: params})
+}
+
+// call sends a request and decodes its result into result, queueing the
+// notifications received meanwhile.
+func (c *client) call(method string, params, result interface{}) *responseError {
+	c.t.Helper()
+	c.nextID++
+	id := json.RawMessage(strconv.Itoa(c.nextID))
+	c.send(request{JSONRPC: "2.0", ID: &id, Method: method, Params: params})
+
+	for {
+		msg := c.read()
+		if msg.ID == nil || string(*msg.ID) != string(id) {
+			c.queue = append(c.queue, msg)
+			continue
+		}
+		if msg.Error != nil {
+			return msg.Error
+		}
+		if result != nil {
+			if err := json.Unmarshal(msg.Result, result); err != nil {
+				c.t.Fatalf("Failed to decode %s result %s: %v", method, msg.Result, err)
+			}
+		}
+		return nil
+	}
+}
+
+// diagnostics returns the next diagnostics published for uri, keeping
+// those for other documents queued.
+func (c *client) diagnostics(uri string) []Diagnostic {
+	c.t.Helper()
+	for i := 0; ; i++ {
+		if i == len(c.queue) {
+			c.queue = append(c.queue, c.read())
+		}
+		msg := c.queue[i]
+		if msg.Method != "textDocument/publishDiagnostics" {
+			continue
+		}
+
+		var params publishDiagnosticsParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			c.t.Fatalf("Failed to decode diagnostics: %v", err)
+		}
+		if params.URI == uri {
+			c.queue = append(c.queue[:i], c.queue[i+1:]...)
+			return params.Diagnostics
+		}
+	}
+}
+
+func (c *client) shutdown() {
+	c.t.Helper()
+	if err := c.call("shutdown", nil, nil); err != nil {
+		c.t.Fatalf("shutdown failed: %v", err)
+	}
+	c.notify("exit", nil)
+	if err := <-c.done; err != nil {
+		c.t.Errorf("Run returned %v", err)
+	}
+}
+
+func newProject(t *testing.T) string {
+	t.Helper()
+	tmpDir, err := os.MkdirTemp("", "envguard-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(tmpDir) })
+
+	if err := os.WriteFile(filepath.Join(tmpDir, ".env.example"), []byte(testExample), 0644); err != nil {
+		t.Fatalf("Failed to write .env.example: %v", err)
+	}
+	return tmpDir
+}
+
+func fileURI(path string) string {
+	return "file://" + filepath.ToSlash(path)
+}
+
+func codes(diagnostics []Diagnostic) string {
+	var list []string
+	for _, d := range diagnostics {
+		list = append(list, d.Code)
+		if d.Data != nil {
+			list[len(list)-1] += ":" + d.Data.Key
+		}
+	}
+	return strings.Join(list, ",")
+}
+
+func (c *client) open(uri, text string) {
+	c.t.Helper()
+	c.notify("textDocument/didOpen", map[string]interface{}{
+		"textDocument": map[string]interface{}{"uri": uri, "languageId": "dotenv", "version": 1, "text": text},
+	})
+}
+
+func (c *client) change(uri string, version int, text string) {
+	c.t.Helper()
+	c.notify("textDocument/didChange", map[string]interface{}{
+		"textDocument":   map[string]interface{}{"uri": uri, "version": version},
+		"contentChanges": []map[string]interface{}{{"text": text}},
+	})
+}
+
+func at(uri string, line, character int) map[string]interface{} {
+	return map[string]interface{}{
+		"textDocument": map[string]interface{}{"uri": uri},
+		"position":     Position{Line: line, Character: character},
+	}
+}
+
+func TestServerSession(t *testing.T) {
+	dir := newProject(t)
+	uri := fileURI(filepath.Join(dir, ".env"))
+	c := newClient(t)
+
+	var init struct {
+		Capabilities map[string]interface{} `json:"capabilities"`
+	}
+	if err := c.call("initialize", map[string]interface{}{"rootUri": fileURI(dir)}, &init); err != nil {
+		t.Fatalf("initialize failed: %v", err)
+	}
+	for _, capability := range []string{"textDocumentSync", "hoverProvider", "completionProvider", "codeActionProvider"} {
+		if init.Capabilities[capability] == nil {
+			t.Errorf("Expected capability %s", capability)
+		}
+	}
+	c.notify("initialized", map[string]interface{}{})
+
+	text := "PORT=eighty\nAPI_TOKEN=<your-token>\nDEBUG=true\n"
+	c.open(uri, text)
+
+	diagnostics := c.diagnostics(uri)
+	want := "missing-variable:LOG_LEVEL,invalid-value,placeholder-value,extra-variable"
+	if got := codes(diagnostics); got != want {
+		t.Fatalf("Expected diagnostics %s, got %s (%+v)", want, got, diagnostics)
+	}
+	if r := diagnostics[1].Range; r.Start != (Position{0, 0}) || r.End != (Position{0, 4}) {
+		t.Errorf("Expected the invalid value diagnostic on PORT, got %+v", r)
+	}
+	if r := diagnostics[3].Range; r.Start != (Position{2, 0}) || r.End != (Position{2, 5}) {
+		t.Errorf("Expected the extra variable diagnostic on DEBUG, got %+v", r)
+	}
+	for _, d := range diagnostics {
+		if strings.Contains(d.Message, "eighty") {
+			t.Errorf("Expected no values in messages, got %q", d.Message)
+		}
+	}
+
+	var hover Hover
+	if err := c.call("textDocument/hover", at(uri, 0, 2), &hover); err != nil {
+		t.Fatalf("hover failed: %v", err)
+	}
+	for _, part := range []string{"**PORT** · `int`", "Port the server listens on", "Example: `3000`", "Declared in .env.example:3"} {
+		if !strings.Contains(hover.Contents.Value, part) {
+			t.Errorf("Expected hover to contain %q, got %q", part, hover.Contents.Value)
+		}
+	}
+	if hover.Range.Start != (Position{0, 0}) || hover.Range.End != (Position{0, 4}) {
+		t.Errorf("Unexpected hover range %+v", hover.Range)
+	}
+
+	var tokenHover Hover
+	if err := c.call("textDocument/hover", at(uri, 1, 3), &tokenHover); err != nil {
+		t.Fatalf("hover failed: %v", err)
+	}
+	if strings.Contains(tokenHover.Contents.Value, "Example") {
+		t.Errorf("Expected no example for a secret, got %q", tokenHover.Contents.Value)
+	}
+
+	var none json.RawMessage
+	if err := c.call("textDocument/hover", at(uri, 2, 1), &none); err != nil || string(none) != "null" {
+		t.Errorf("Expected no hover for an undeclared key, got %s (%v)", none, err)
+	}
+
+	// Complete on a new empty line.
+	c.change(uri, 2, text+"\n")
+	c.diagnostics(uri)
+
+	var items []CompletionItem
+	if err := c.call("textDocument/completion", at(uri, 3, 0), &items); err != nil {
+		t.Fatalf("completion failed: %v", err)
+	}
+	var labels []string
+	for _, item := range items {
+		labels = append(labels, item.Label+"/"+item.SortText)
+	}
+	if got := strings.Join(labels, ","); got != "LOG_LEVEL/0LOG_LEVEL,SENTRY_DSN/1SENTRY_DSN" {
+		t.Errorf("Unexpected completions %s", got)
+	}
+	if items[0].InsertText != "LOG_LEVEL=" || items[1].Detail != "string, optional" {
+		t.Errorf("Unexpected completion items %+v", items)
+	}
+
+	var actions []CodeAction
+	params := map[string]interface{}{
+		"textDocument": map[string]interface{}{"uri": uri},
+		"range":        diagnostics[0].Range,
+		"context":      map[string]interface{}{"diagnostics": diagnostics[:1]},
+	}
+	if err := c.call("textDocument/codeAction", params, &actions); err != nil {
+		t.Fatalf("codeAction failed: %v", err)
+	}
+	if len(actions) != 1 || actions[0].Title != "Add LOG_LEVEL from .env.example" {
+		t.Fatalf("Unexpected code actions %+v", actions)
+	}
+	edit := actions[0].Edit.Changes[uri]
+	if len(edit) != 1 || edit[0].NewText != "LOG_LEVEL=info\n" || edit[0].Range.Start != (Position{4, 0}) {
+		t.Fatalf("Unexpected edit %+v", edit)
+	}
+
+	// Applying the fix and correcting PORT leaves the warnings only.
+	c.change(uri, 3, "PORT=8080\nAPI_TOKEN=<your-token>\nDEBUG=true\n\nLOG_LEVEL=info\n")
+	if got := codes(c.diagnostics(uri)); got != "placeholder-value,extra-variable" {
+		t.Errorf("Expected only warnings after the fix, got %s", got)
+	}
+
+	// Editing the open example revalidates the env file against it.
+	exampleURI := fileURI(filepath.Join(dir, ".env.example"))
+	c.open(exampleURI, testExample+"# @required-if MODE is on\nREDIS_URL=\nDEBUG=false\n")
+	if got := codes(c.diagnostics(exampleURI)); got != "invalid-rule" {
+		t.Errorf("Expected the malformed rule to be reported, got %s", got)
+	}
+	if got := codes(c.diagnostics(uri)); got != "missing-variable:REDIS_URL,placeholder-value" {
+		t.Errorf("Expected the env file to follow the open example, got %s", got)
+	}
+
+	var exampleItems []CompletionItem
+	c.change(uri, 4, "PORT=8080\nURL=${PO\n")
+	c.diagnostics(exampleURI)
+	c.diagnostics(uri)
+	if err := c.call("textDocument/completion", at(uri, 1, 8), &exampleItems); err != nil {
+		t.Fatalf("completion failed: %v", err)
+	}
+	if len(exampleItems) == 0 || exampleItems[0].Label != "PORT" {
+		t.Errorf("Expected references to complete defined keys first, got %+v", exampleItems)
+	}
+
+	c.notify("textDocument/didClose", map[string]interface{}{"textDocument": map[string]interface{}{"uri": uri}})
+	if got := c.diagnostics(uri); len(got) != 0 {
+		t.Errorf("Expected diagnostics to be cleared on close, got %+v", got)
+	}
+
+	c.shutdown()
+}
+
+func TestServerParseErrorAndDialect(t *testing.T) {
+	dir := newProject(t)
+	uri := fileURI(filepath.Join(dir, "docker.env"))
+	c := newClient(t)
+
+	if err := c.call("initialize", map[string]interface{}{"initializationOptions": map[string]string{"dialect": "docker"}}, nil); err != nil {
+		t.Fatalf("initialize failed: %v", err)
+	}
+
+	c.open(uri, "PORT=3000\nLOG_LEVEL=\"info\"\nAPI_TOKEN=abc\n")
+	if got := codes(c.diagnostics(uri)); !strings.Contains(got, "dialect-mismatch") {
+		t.Errorf("Expected docker lint diagnostics, got %s", got)
+	}
+
+	c.change(uri, 2, "PORT=3000\nnot an assignment\n")
+	diagnostics := c.diagnostics(uri)
+	if len(diagnostics) != 1 || diagnostics[0].Code != CodeParseError {
+		t.Fatalf("Expected a single parse error, got %+v", diagnostics)
+	}
+
+	if err := c.call("textDocument/unknown", nil, nil); err == nil || err.Code != codeMethodNotFound {
+		t.Errorf("Expected method not found, got %v", err)
+	}
+
+	var bad struct{}
+	c2 := newClient(t)
+	if err := c2.call("initialize", map[string]interface{}{"initializationOptions": map[string]string{"dialect": "cmd"}}, &bad); err == nil {
+		t.Error("Expected an unknown dialect to be rejected")
+	}
+	c2.shutdown()
+
+	c.shutdown()
+}
+
+func TestExitWithoutShutdown(t *testing.T) {
+	c := newClient(t)
+	c.notify("exit", nil)
+	if err := <-c.done; err != ErrExitWithoutShutdown {
+		t.Errorf("Expected ErrExitWithoutShutdown, got %v", err)
+	}
+}
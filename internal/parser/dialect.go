@@ -0,0 +1,326 @@
+package parser
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Dialect names a runtime that reads dotenv-style files with its own rules.
+type Dialect string
+
+const (
+	// DialectGodotenv is how envguard itself reads files.
+	DialectGodotenv Dialect = "godotenv"
+	// DialectDocker is docker run --env-file: every line is KEY=value taken
+	// literally, with no quotes, interpolation, inline comments or
+	// multi-line values.
+	DialectDocker Dialect = "docker"
+	// DialectCompose is docker compose env_file and .env: quotes and
+	// multi-line values like godotenv, with compose-style ${VAR:-default}
+	// interpolation.
+	DialectCompose Dialect = "compose"
+	// DialectShell is sourcing the file from a POSIX shell (set -a; . ./.env).
+	DialectShell Dialect = "shell"
+	// DialectSystemd is a systemd EnvironmentFile=: shell-like quoting and
+	// escapes but no interpolation, no export prefix and ; comments.
+	DialectSystemd Dialect = "systemd"
+)
+
+// Dialects lists every supported dialect.
+var Dialects = []Dialect{DialectGodotenv, DialectDocker, DialectCompose, DialectShell, DialectSystemd}
+
+// ParseDialect validates a dialect name.
+func ParseDialect(value string) (Dialect, error) {
+	for _, dialect := range Dialects {
+		if string(dialect) == value {
+			return dialect, nil
+		}
+	}
+
+	names := make([]string, len(Dialects))
+	for i, dialect := range Dialects {
+		names[i] = string(dialect)
+	}
+	return "", fmt.Errorf("unknown dialect '%s' (use %s)", value, strings.Join(names, ", "))
+}
+
+// DialectEntry is an assignment as a dialect reads it. Problem describes a
+// line the runtime rejects or misreads; Value is then unreliable.
+type DialectEntry struct {
+	Key     string
+	Value   string
+	Line    int
+	Problem string
+}
+
+// ReadDialect reads content the way dialect does. Interpolation falls back
+// to the process environment for variables the file does not define.
+func ReadDialect(content []byte, dialect Dialect) ([]DialectEntry, error) {
+	text := strings.ReplaceAll(string(content), "\r\n", "\n")
+
+	switch dialect {
+	case DialectGodotenv, DialectCompose:
+		entries, err := ParseEntries(content)
+		if err != nil {
+			return nil, err
+		}
+
+		var vars EnvVars
+		if dialect == DialectCompose {
+			vars, err = ResolveEntries(entries, InterpolateCompose, os.LookupEnv)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		result := make([]DialectEntry, len(entries))
+		for i, entry := range entries {
+			result[i] = DialectEntry{Key: entry.Key, Value: entry.Value, Line: entry.Line}
+			if vars != nil {
+				result[i].Value = vars[entry.Key]
+			}
+		}
+		return result, nil
+	case DialectDocker:
+		return readDocker(text), nil
+	case DialectShell:
+		return readShellLike(text, true), nil
+	case DialectSystemd:
+		return readShellLike(text, false), nil
+	}
+	return nil, fmt.Errorf("unknown dialect '%s'", dialect)
+}
+
+func readDocker(text string) []DialectEntry {
+	var result []DialectEntry
+	for i, line := range strings.Split(text, "\n") {
+		line = strings.TrimLeft(line, " \t")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		entry := DialectEntry{Line: i + 1}
+		key, value, hasValue := strings.Cut(line, "=")
+		entry.Key, entry.Value = key, value
+		switch {
+		case !isName(key):
+			entry.Problem = fmt.Sprintf("docker rejects %q as a variable name", key)
+		case !hasValue:
+			entry.Problem = fmt.Sprintf("docker copies %s from the host environment instead of setting it", key)
+		}
+		result = append(result, entry)
+	}
+	return result
+}
+
+// readShellLike reads text as a POSIX shell (shell) or a systemd
+// EnvironmentFile (!shell) would.
+func readShellLike(text string, shell bool) []DialectEntry {
+	var result []DialectEntry
+	vars := make(map[string]string)
+	lookup := func(name string) (string, bool) {
+		if value, ok := vars[name]; ok {
+			return value, true
+		}
+		return os.LookupEnv(name)
+	}
+
+	runtime := "systemd"
+	if shell {
+		runtime = "sh"
+	}
+
+	line := 1
+	for pos := 0; pos < len(text); {
+		end := strings.IndexByte(text[pos:], '\n')
+		if end < 0 {
+			end = len(text) - pos
+		}
+		current := strings.TrimLeft(text[pos:pos+end], " \t")
+		startLine := line
+
+		if current == "" || current[0] == '#' || (!shell && current[0] == ';') {
+			pos += end + 1
+			line++
+			continue
+		}
+
+		if strings.HasPrefix(current, "export ") {
+			if !shell {
+				result = append(result, DialectEntry{Line: startLine, Key: assignmentKey(current[7:]),
+					Problem: "systemd does not support the export prefix and ignores the line"})
+				pos += end + 1
+				line++
+				continue
+			}
+			current = strings.TrimLeft(current[7:], " \t")
+		}
+
+		key, _, hasValue := strings.Cut(current, "=")
+		if !hasValue || !isName(key) {
+			result = append(result, DialectEntry{Line: startLine, Key: firstWord(current),
+				Problem: fmt.Sprintf("%s does not read this line as an assignment", runtime)})
+			pos += end + 1
+			line++
+			continue
+		}
+
+		// The value may run over several lines inside quotes
+		valueStart := pos + end - len(current) + len(key) + 1
+		value, consumed, problem := scanShellValue(text[valueStart:], shell, lookup)
+
+		entry := DialectEntry{Key: key, Value: value, Line: startLine, Problem: problem}
+		if problem != "" {
+			entry.Problem = fmt.Sprintf("%s %s", runtime, problem)
+		}
+		result = append(result, entry)
+		vars[key] = value
+
+		line += strings.Count(text[valueStart:valueStart+consumed], "\n")
+		pos = valueStart + consumed
+		if pos < len(text) && text[pos] == '\n' {
+			pos++
+			line++
+		} else if next := strings.IndexByte(text[pos:], '\n'); next >= 0 {
+			pos += next + 1
+			line++
+		} else {
+			pos = len(text)
+		}
+	}
+	return result
+}
+
+// scanShellValue reads one assignment value starting right after '=' and
+// returns it with the number of bytes consumed, stopping before the
+// newline that ends it.
+func scanShellValue(s string, shell bool, lookup func(string) (string, bool)) (string, int, string) {
+	var b strings.Builder
+	i := 0
+
+	for i < len(s) {
+		c := s[i]
+		switch {
+		case c == '\n':
+			return finishShellValue(b.String(), shell), i, ""
+		case c == '\\':
+			if i+1 < len(s) {
+				if s[i+1] != '\n' {
+					b.WriteByte(s[i+1])
+				}
+				i += 2
+				continue
+			}
+			i++
+		case c == '\'':
+			end := strings.IndexByte(s[i+1:], '\'')
+			if end < 0 {
+				return b.String(), len(s), "reports an unterminated single quote"
+			}
+			b.WriteString(s[i+1 : i+1+end])
+			i += end + 2
+		case c == '"':
+			i++
+			closed := false
+			for i < len(s) {
+				if s[i] == '"' {
+					closed = true
+					i++
+					break
+				}
+				if s[i] == '\\' && i+1 < len(s) && strings.IndexByte("$`\"\\\n", s[i+1]) >= 0 {
+					if s[i+1] != '\n' {
+						b.WriteByte(s[i+1])
+					}
+					i += 2
+					continue
+				}
+				if shell && s[i] == '$' {
+					expanded, n, problem := expandShell(s[i:], lookup)
+					if problem != "" {
+						return b.String(), i, problem
+					}
+					b.WriteString(expanded)
+					i += n
+					continue
+				}
+				if shell && s[i] == '`' {
+					return b.String(), i, "runs a command substitution"
+				}
+				b.WriteByte(s[i])
+				i++
+			}
+			if !closed {
+				return b.String(), len(s), "reports an unterminated double quote"
+			}
+		case shell && (c == ' ' || c == '\t'):
+			rest := strings.TrimLeft(s[i:], " \t")
+			if rest == "" || rest[0] == '\n' || rest[0] == '#' {
+				return b.String(), i + indexOrLen(s[i:], '\n'), ""
+			}
+			return b.String(), i + indexOrLen(s[i:], '\n'), fmt.Sprintf("stops the value at whitespace and runs %q as a command", firstWord(rest))
+		case shell && c == '#' && b.Len() == 0 && i == 0:
+			return "", indexOrLen(s, '\n'), ""
+		case shell && c == '$':
+			expanded, n, problem := expandShell(s[i:], lookup)
+			if problem != "" {
+				return b.String(), i, problem
+			}
+			b.WriteString(expanded)
+			i += n
+		case shell && (c == '`' || c == ';' || c == '&' || c == '|' || c == '<' || c == '>' || c == '(' || c == ')'):
+			return b.String(), i + indexOrLen(s[i:], '\n'), fmt.Sprintf("treats the unquoted %q as shell syntax", string(c))
+		default:
+			b.WriteByte(c)
+			i++
+		}
+	}
+
+	return finishShellValue(b.String(), shell), i, ""
+}
+
+func finishShellValue(value string, shell bool) string {
+	if shell {
+		return value
+	}
+	return strings.TrimRight(value, " \t")
+}
+
+// expandShell expands the parameter reference at the start of s.
+func expandShell(s string, lookup func(string) (string, bool)) (string, int, string) {
+	if strings.HasPrefix(s, "$(") {
+		return "", 0, "runs a command substitution"
+	}
+
+	ref, ok := scanReference(s, 0, InterpolateCompose)
+	if !ok {
+		return "$", 1, ""
+	}
+
+	value, err := Expand(s[:ref.End], InterpolateCompose, lookup)
+	if err != nil {
+		return "", 0, fmt.Sprintf("fails to expand: %v", err)
+	}
+	return value, ref.End, ""
+}
+
+func indexOrLen(s string, c byte) int {
+	if i := strings.IndexByte(s, c); i >= 0 {
+		return i
+	}
+	return len(s)
+}
+
+// assignmentKey is the text before '=' in the first word of s.
+func assignmentKey(s string) string {
+	key, _, _ := strings.Cut(firstWord(s), "=")
+	return key
+}
+
+func firstWord(s string) string {
+	if fields := strings.Fields(s); len(fields) > 0 {
+		return fields[0]
+	}
+	return ""
+}
@@ -0,0 +1,112 @@
+package parser
+
+import (
+	"testing"
+)
+
+const dialectSample = `# comment
+PLAIN=value
+QUOTED="hello world"
+SINGLE='$HOME stays'
+export EXPORTED=yes
+REF=${PLAIN}-suffix
+INLINE=abc # note
+MULTI="line one
+line two"
+LAST=end
+`
+
+func dialectValues(t *testing.T, dialect Dialect) map[string]DialectEntry {
+	t.Helper()
+	entries, err := ReadDialect([]byte(dialectSample), dialect)
+	if err != nil {
+		t.Fatalf("Failed to read %s dialect: %v", dialect, err)
+	}
+	values := make(map[string]DialectEntry)
+	for _, entry := range entries {
+		values[entry.Key] = entry
+	}
+	return values
+}
+
+func TestReadDialectDocker(t *testing.T) {
+	values := dialectValues(t, DialectDocker)
+
+	if values["QUOTED"].Value != `"hello world"` {
+		t.Errorf("Expected docker to keep quotes, got %q", values["QUOTED"].Value)
+	}
+	if values["REF"].Value != "${PLAIN}-suffix" {
+		t.Errorf("Expected docker not to interpolate, got %q", values["REF"].Value)
+	}
+	if values["INLINE"].Value != "abc # note" {
+		t.Errorf("Expected docker to keep inline comments, got %q", values["INLINE"].Value)
+	}
+	if values["export EXPORTED"].Problem == "" {
+		t.Error("Expected docker to reject the export prefix")
+	}
+	if values["line two\""].Problem == "" {
+		t.Error("Expected docker to reject the second line of a multi-line value")
+	}
+}
+
+func TestReadDialectShell(t *testing.T) {
+	values := dialectValues(t, DialectShell)
+
+	expected := map[string]string{
+		"PLAIN":    "value",
+		"QUOTED":   "hello world",
+		"SINGLE":   "$HOME stays",
+		"EXPORTED": "yes",
+		"REF":      "value-suffix",
+		"INLINE":   "abc",
+		"MULTI":    "line one\nline two",
+		"LAST":     "end",
+	}
+	for key, value := range expected {
+		if values[key].Value != value || values[key].Problem != "" {
+			t.Errorf("Expected shell to read %s as %q, got %+v", key, value, values[key])
+		}
+	}
+	if values["LAST"].Line != 10 {
+		t.Errorf("Expected LAST on line 10, got %d", values["LAST"].Line)
+	}
+
+	entries, err := ReadDialect([]byte("GREETING=hello world\n"), DialectShell)
+	if err != nil {
+		t.Fatalf("Failed to read shell dialect: %v", err)
+	}
+	if entries[0].Problem == "" {
+		t.Error("Expected unquoted whitespace to be a problem for sh")
+	}
+}
+
+func TestReadDialectSystemd(t *testing.T) {
+	values := dialectValues(t, DialectSystemd)
+
+	if values["REF"].Value != "${PLAIN}-suffix" {
+		t.Errorf("Expected systemd not to interpolate, got %q", values["REF"].Value)
+	}
+	if values["INLINE"].Value != "abc # note" {
+		t.Errorf("Expected systemd to keep inline comments, got %q", values["INLINE"].Value)
+	}
+	if values["EXPORTED"].Problem == "" {
+		t.Error("Expected systemd to reject the export prefix")
+	}
+	if values["MULTI"].Value != "line one\nline two" {
+		t.Errorf("Expected systemd to read quoted multi-line values, got %q", values["MULTI"].Value)
+	}
+}
+
+func TestReadDialectCompose(t *testing.T) {
+	entries, err := ReadDialect([]byte("A=1\nB=${A:-x}${MISSING_ENVGUARD_TEST:-y}\n"), DialectCompose)
+	if err != nil {
+		t.Fatalf("Failed to read compose dialect: %v", err)
+	}
+	if entries[1].Value != "1y" {
+		t.Errorf("Expected compose interpolation, got %q", entries[1].Value)
+	}
+
+	if _, err := ParseDialect("podman"); err == nil {
+		t.Error("Expected an unknown dialect to fail")
+	}
+}
@@ -0,0 +1,233 @@
+package parser
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/joho/godotenv"
+)
+
+// Entry is a single assignment in a dotenv file, kept in file order
+// together with its position and the comment block directly above it.
+// RawValue is the value with quotes removed but before any interpolation;
+// Quote is the quote character used, or 0 for unquoted values. Column and
+// ValueColumn are the 0-based byte offsets of the key and of the value
+// (including any opening quote) on Line. Comments holds the comment text
+// without "#"; RawComments holds the same lines exactly as written.
+type Entry struct {
+	Key         string
+	Value       string
+	RawValue    string
+	Quote       byte
+	Line        int
+	EndLine     int
+	Column      int
+	ValueColumn int
+	Raw         string
+	Comments    []string
+	RawComments []string
+	Inline      string
+}
+
+// ParseEnvFileEntries reads filename and returns its assignments in order.
+func ParseEnvFileEntries(filename string) ([]Entry, error) {
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseEntries(content)
+}
+
+// ParseEntries splits dotenv content into ordered entries. Values are taken
+// from godotenv so they match what ParseEnvFile returns for the same input.
+func ParseEntries(content []byte) ([]Entry, error) {
+	values, err := godotenv.Unmarshal(string(content))
+	if err != nil {
+		return nil, err
+	}
+
+	lines := strings.Split(strings.ReplaceAll(string(content), "\r\n", "\n"), "\n")
+
+	var entries []Entry
+	var comments, rawComments []string
+
+	for i := 0; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+
+		if trimmed == "" {
+			comments, rawComments = nil, nil
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, "#") {
+			comments = append(comments, strings.TrimSpace(strings.TrimPrefix(trimmed, "#")))
+			rawComments = append(rawComments, strings.TrimRight(lines[i], "\r"))
+			continue
+		}
+
+		key, rest, ok := splitAssignment(trimmed)
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected KEY=value, got %q", i+1, trimmed)
+		}
+
+		start := i
+		column, valueColumn := assignmentColumns(lines[i], key)
+		inline := ""
+		rawValue := ""
+		quote := leadingQuote(rest)
+		if quote != 0 {
+			body := rest[1:]
+			var parts []string
+			for {
+				if end := closingQuote(body, quote); end >= 0 {
+					parts = append(parts, body[:end])
+					inline = inlineComment(body[end+1:], false)
+					break
+				}
+				if i+1 >= len(lines) {
+					return nil, fmt.Errorf("line %d: unterminated quoted value for %s", start+1, key)
+				}
+				parts = append(parts, body)
+				i++
+				body = lines[i]
+			}
+			rawValue = strings.Join(parts, "\n")
+			if quote == '"' {
+				rawValue = unescapeDoubleQuoted(rawValue)
+			}
+		} else {
+			inline = inlineComment(rest, true)
+			rawValue = strings.TrimSpace(stripInlineComment(rest))
+		}
+
+		entries = append(entries, Entry{
+			Key:         key,
+			Value:       values[key],
+			RawValue:    rawValue,
+			Quote:       quote,
+			Line:        start + 1,
+			EndLine:     i + 1,
+			Column:      column,
+			ValueColumn: valueColumn,
+			Raw:         strings.Join(lines[start:i+1], "\n"),
+			Comments:    comments,
+			RawComments: rawComments,
+			Inline:      inline,
+		})
+		comments, rawComments = nil, nil
+	}
+
+	return entries, nil
+}
+
+// EntryKeys returns the keys of entries in file order.
+func EntryKeys(entries []Entry) []string {
+	keys := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		keys = append(keys, entry.Key)
+	}
+	return keys
+}
+
+func splitAssignment(line string) (string, string, bool) {
+	line = strings.TrimPrefix(line, "export ")
+
+	idx := strings.IndexAny(line, "=:")
+	if idx <= 0 {
+		return "", "", false
+	}
+
+	key := strings.TrimSpace(line[:idx])
+	if key == "" || strings.ContainsAny(key, " \t") {
+		return "", "", false
+	}
+
+	return key, strings.TrimLeft(line[idx+1:], " \t"), true
+}
+
+// assignmentColumns returns where key and its value start in line, which
+// splitAssignment has already accepted.
+func assignmentColumns(line, key string) (int, int) {
+	indent := len(line) - len(strings.TrimLeft(line, " \t"))
+	rest := strings.TrimPrefix(line[indent:], "export ")
+	column := len(line) - len(rest) + strings.Index(rest, key)
+
+	separator := column + len(key) + strings.IndexAny(line[column+len(key):], "=:")
+	value := line[separator+1:]
+	return column, len(line) - len(strings.TrimLeft(value, " \t"))
+}
+
+func leadingQuote(value string) byte {
+	if value == "" {
+		return 0
+	}
+	switch value[0] {
+	case '"', '\'', '`':
+		return value[0]
+	}
+	return 0
+}
+
+func closingQuote(s string, quote byte) int {
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && quote == '"' {
+			i++
+			continue
+		}
+		if s[i] == quote {
+			return i
+		}
+	}
+	return -1
+}
+
+// unescapeDoubleQuoted resolves the escapes godotenv understands inside
+// double quotes, leaving \$ for the interpolation step.
+func unescapeDoubleQuoted(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 >= len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 't':
+			b.WriteByte('\t')
+		case '"', '\\':
+			b.WriteByte(s[i])
+		default:
+			b.WriteByte('\\')
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+func stripInlineComment(s string) string {
+	for i := 0; i < len(s); i++ {
+		if s[i] == '#' && (i == 0 || s[i-1] == ' ' || s[i-1] == '\t') {
+			return s[:i]
+		}
+	}
+	return s
+}
+
+func inlineComment(s string, needSpace bool) string {
+	for i := 0; i < len(s); i++ {
+		if s[i] != '#' {
+			continue
+		}
+		if needSpace && i > 0 && s[i-1] != ' ' && s[i-1] != '\t' {
+			continue
+		}
+		return strings.TrimSpace(s[i+1:])
+	}
+	return ""
+}
@@ -0,0 +1,53 @@
+package parser
+
+import "strings"
+
+// FormatValue renders value so that ParseEnvFile reads it back unchanged.
+// Plain values are left bare, values without single quotes or newlines are
+// single-quoted so nothing inside them is interpolated, and anything else
+// is double-quoted with escapes.
+func FormatValue(value string) string {
+	if isPlainValue(value) {
+		return value
+	}
+
+	if !strings.ContainsAny(value, "'\n\r") {
+		return "'" + value + "'"
+	}
+
+	var b strings.Builder
+	b.WriteByte('"')
+	for i := 0; i < len(value); i++ {
+		switch c := value[i]; c {
+		case '\\', '"', '$':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// FormatAssignment renders a single KEY=value line.
+func FormatAssignment(key, value string) string {
+	return key + "=" + FormatValue(value)
+}
+
+func isPlainValue(value string) bool {
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case strings.IndexByte("_-.,/:@+=%", c) >= 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,34 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFormatValueRoundTrip(t *testing.T) {
+	values := []string{
+		"", "plain", "postgres://u:p@host:5432/db", "with space", "hash # inside",
+		"it's", "multi\nline", `back\slash`, "$HOME", "it's $HOME\n", `quote"d`, "a=b",
+	}
+
+	tempDir, err := os.MkdirTemp("", "envguard-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	for _, value := range values {
+		path := filepath.Join(tempDir, ".env")
+		if err := os.WriteFile(path, []byte(FormatAssignment("KEY", value)+"\n"), 0644); err != nil {
+			t.Fatalf("Failed to write env file: %v", err)
+		}
+		vars, err := ParseEnvFile(path)
+		if err != nil {
+			t.Fatalf("Failed to parse %q: %v", FormatValue(value), err)
+		}
+		if vars["KEY"] != value {
+			t.Errorf("FormatValue(%q) = %s, read back as %q", value, FormatValue(value), vars["KEY"])
+		}
+	}
+}
@@ -0,0 +1,250 @@
+package parser
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/joho/godotenv"
+)
+
+// Interpolation selects how ${VAR} references inside values are resolved.
+type Interpolation string
+
+const (
+	// InterpolateNone keeps values exactly as written.
+	InterpolateNone Interpolation = "none"
+	// InterpolateGodotenv expands $VAR and ${VAR}, as godotenv does.
+	InterpolateGodotenv Interpolation = "godotenv"
+	// InterpolateCompose also supports ${VAR:-default}, ${VAR-default},
+	// ${VAR:?error}, ${VAR?error}, ${VAR:+alt}, ${VAR+alt} and $$ escapes,
+	// as docker compose does.
+	InterpolateCompose Interpolation = "compose"
+)
+
+// Interpolations lists the supported modes.
+var Interpolations = []Interpolation{InterpolateNone, InterpolateGodotenv, InterpolateCompose}
+
+// ParseInterpolation validates an interpolation mode name.
+func ParseInterpolation(value string) (Interpolation, error) {
+	for _, mode := range Interpolations {
+		if string(mode) == value {
+			return mode, nil
+		}
+	}
+	return "", fmt.Errorf("unknown interpolation '%s' (use none, godotenv or compose)", value)
+}
+
+// Reference is a variable reference found in a value.
+type Reference struct {
+	Name string
+	// Operator is the compose modifier (":-", "-", ":?", "?", ":+", "+"), if any.
+	Operator string
+	// Argument is the default, error message or alternative after Operator.
+	Argument string
+	Start    int
+	End      int
+}
+
+// HasFallback reports whether the reference supplies its own value when
+// the variable is unset.
+func (r Reference) HasFallback() bool {
+	return r.Operator == ":-" || r.Operator == "-" || r.Operator == ":+" || r.Operator == "+"
+}
+
+// Required reports whether the reference fails when the variable is unset.
+func (r Reference) Required() bool {
+	return r.Operator == ":?" || r.Operator == "?"
+}
+
+// FindReferences returns the variable references in value for mode.
+func FindReferences(value string, mode Interpolation) []Reference {
+	if mode == InterpolateNone {
+		return nil
+	}
+
+	var refs []Reference
+	for i := 0; i < len(value); i++ {
+		switch {
+		case mode == InterpolateGodotenv && value[i] == '\\' && i+1 < len(value) && value[i+1] == '$':
+			i++
+		case mode == InterpolateCompose && value[i] == '$' && i+1 < len(value) && value[i+1] == '$':
+			i++
+		case value[i] == '$':
+			if ref, ok := scanReference(value, i, mode); ok {
+				refs = append(refs, ref)
+				i = ref.End - 1
+			}
+		}
+	}
+	return refs
+}
+
+func scanReference(value string, start int, mode Interpolation) (Reference, bool) {
+	rest := value[start+1:]
+
+	if strings.HasPrefix(rest, "{") {
+		end := strings.IndexByte(rest, '}')
+		if end < 0 {
+			return Reference{}, false
+		}
+		body := rest[1:end]
+		ref := Reference{Name: body, Start: start, End: start + 1 + end + 1}
+
+		if mode == InterpolateCompose {
+			for _, op := range []string{":-", ":?", ":+", "-", "?", "+"} {
+				if idx := strings.Index(body, op); idx > 0 && isName(body[:idx]) {
+					ref.Name, ref.Operator, ref.Argument = body[:idx], op, body[idx+len(op):]
+					break
+				}
+			}
+		}
+
+		if !isName(ref.Name) {
+			return Reference{}, false
+		}
+		return ref, true
+	}
+
+	n := 0
+	for n < len(rest) && isNameByte(rest[n], n == 0) {
+		n++
+	}
+	if n == 0 {
+		return Reference{}, false
+	}
+	return Reference{Name: rest[:n], Start: start, End: start + 1 + n}, true
+}
+
+func isName(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if !isNameByte(s[i], i == 0) {
+			return false
+		}
+	}
+	return true
+}
+
+func isNameByte(c byte, first bool) bool {
+	if c == '_' || (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') {
+		return true
+	}
+	return !first && c >= '0' && c <= '9'
+}
+
+// Expand resolves the references in value. lookup reports the value of a
+// variable and whether it is set.
+func Expand(value string, mode Interpolation, lookup func(string) (string, bool)) (string, error) {
+	if mode == InterpolateNone {
+		return value, nil
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, ref := range FindReferences(value, mode) {
+		b.WriteString(unescapeDollars(value[last:ref.Start], mode))
+		last = ref.End
+
+		current, set := lookup(ref.Name)
+		switch ref.Operator {
+		case ":-":
+			if !set || current == "" {
+				current = ref.Argument
+			}
+		case "-":
+			if !set {
+				current = ref.Argument
+			}
+		case ":?", "?":
+			if !set || (ref.Operator == ":?" && current == "") {
+				message := ref.Argument
+				if message == "" {
+					message = "is required"
+				}
+				return "", fmt.Errorf("%s: %s", ref.Name, message)
+			}
+		case ":+":
+			if set && current != "" {
+				current = ref.Argument
+			}
+		case "+":
+			if set {
+				current = ref.Argument
+			}
+		}
+		b.WriteString(current)
+	}
+	b.WriteString(unescapeDollars(value[last:], mode))
+
+	return b.String(), nil
+}
+
+func unescapeDollars(s string, mode Interpolation) string {
+	switch mode {
+	case InterpolateCompose:
+		return strings.ReplaceAll(s, "$$", "$")
+	case InterpolateGodotenv:
+		return strings.ReplaceAll(s, `\$`, "$")
+	}
+	return s
+}
+
+// ResolveEntries computes the final value of every entry under mode.
+// References resolve to variables defined earlier in the file, then to
+// fallback (which may be nil). Single-quoted values are never expanded.
+func ResolveEntries(entries []Entry, mode Interpolation, fallback func(string) (string, bool)) (EnvVars, error) {
+	vars := make(EnvVars)
+	lookup := func(name string) (string, bool) {
+		if value, ok := vars[name]; ok {
+			return value, true
+		}
+		if fallback != nil {
+			return fallback(name)
+		}
+		return "", false
+	}
+
+	for _, entry := range entries {
+		if entry.Quote == '\'' {
+			vars[entry.Key] = entry.RawValue
+			continue
+		}
+
+		value, err := Expand(entry.RawValue, mode, lookup)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", entry.Line, err)
+		}
+		vars[entry.Key] = value
+	}
+
+	return vars, nil
+}
+
+// ParseEnvFileWith parses filename resolving references with mode.
+// godotenv mode is identical to ParseEnvFile; compose mode falls back to
+// the process environment for variables the file does not define.
+func ParseEnvFileWith(filename string, mode Interpolation) (EnvVars, error) {
+	if mode == InterpolateGodotenv || mode == "" {
+		return ParseEnvFile(filename)
+	}
+
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	// Let godotenv reject malformed files with its usual errors first
+	if _, err := godotenv.Unmarshal(string(content)); err != nil {
+		return nil, err
+	}
+
+	entries, err := ParseEntries(content)
+	if err != nil {
+		return nil, err
+	}
+
+	return ResolveEntries(entries, mode, os.LookupEnv)
+}
@@ -0,0 +1,105 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFindReferences(t *testing.T) {
+	value := `postgresql://${DB_USER}:$DB_PASSWORD@${DB_HOST:-localhost}/\$LITERAL`
+
+	refs := FindReferences(value, InterpolateGodotenv)
+	names := []string{}
+	for _, ref := range refs {
+		names = append(names, ref.Name)
+	}
+	// godotenv has no default syntax, so DB_HOST:-localhost is not a valid name
+	if len(names) != 2 || names[0] != "DB_USER" || names[1] != "DB_PASSWORD" {
+		t.Errorf("Expected [DB_USER DB_PASSWORD], got %v", names)
+	}
+
+	refs = FindReferences(value, InterpolateCompose)
+	if len(refs) != 4 {
+		t.Fatalf("Expected 4 compose references, got %d", len(refs))
+	}
+	if refs[2].Name != "DB_HOST" || refs[2].Operator != ":-" || refs[2].Argument != "localhost" {
+		t.Errorf("Unexpected default reference: %+v", refs[2])
+	}
+
+	if refs := FindReferences("cost $$5 ${PRICE}", InterpolateCompose); len(refs) != 1 || refs[0].Name != "PRICE" {
+		t.Errorf("Expected $$ to escape in compose mode, got %+v", refs)
+	}
+
+	if refs := FindReferences("${A}", InterpolateNone); refs != nil {
+		t.Errorf("Expected no references without interpolation, got %+v", refs)
+	}
+}
+
+func TestExpandCompose(t *testing.T) {
+	vars := map[string]string{"SET": "value", "EMPTY": ""}
+	lookup := func(name string) (string, bool) {
+		value, ok := vars[name]
+		return value, ok
+	}
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"${SET}", "value"},
+		{"${UNSET:-fallback}", "fallback"},
+		{"${EMPTY:-fallback}", "fallback"},
+		{"${EMPTY-fallback}", ""},
+		{"${SET:+alt}", "alt"},
+		{"${UNSET:+alt}", ""},
+		{"$$SET", "$SET"},
+	}
+
+	for _, test := range tests {
+		got, err := Expand(test.input, InterpolateCompose, lookup)
+		if err != nil {
+			t.Fatalf("Failed to expand %q: %v", test.input, err)
+		}
+		if got != test.expected {
+			t.Errorf("Expand(%q) = %q, expected %q", test.input, got, test.expected)
+		}
+	}
+
+	if _, err := Expand("${UNSET:?must be set}", InterpolateCompose, lookup); err == nil || err.Error() != "UNSET: must be set" {
+		t.Errorf("Expected required error, got %v", err)
+	}
+}
+
+func TestParseEnvFileWith(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "envguard-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	envFile := filepath.Join(tempDir, ".env")
+	content := "HOST=db\nURL=\"postgres://${HOST}:${PORT:-5432}\"\nRAW='${HOST}'\n"
+	if err := os.WriteFile(envFile, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write env file: %v", err)
+	}
+
+	vars, err := ParseEnvFileWith(envFile, InterpolateCompose)
+	if err != nil {
+		t.Fatalf("Failed to parse env file: %v", err)
+	}
+	if vars["URL"] != "postgres://db:5432" {
+		t.Errorf("Expected compose expansion, got %q", vars["URL"])
+	}
+	if vars["RAW"] != "${HOST}" {
+		t.Errorf("Expected single-quoted value to stay literal, got %q", vars["RAW"])
+	}
+
+	vars, err = ParseEnvFileWith(envFile, InterpolateNone)
+	if err != nil {
+		t.Fatalf("Failed to parse env file: %v", err)
+	}
+	if vars["URL"] != "postgres://${HOST}:${PORT:-5432}" {
+		t.Errorf("Expected raw value without interpolation, got %q", vars["URL"])
+	}
+}
@@ -97,3 +97,76 @@ func TestHasVariable(t *testing.T) {
 		t.Error("Expected NONEXISTENT to not exist")
 	}
 }
+
+func TestParseEntries(t *testing.T) {
+	content := `# Database
+DATABASE_URL=postgresql://localhost:5432/test
+export API_KEY="quoted # not a comment"
+
+# Multiline certificate
+CERT="line1
+line2"
+PORT=3000 # server port
+`
+
+	entries, err := ParseEntries([]byte(content))
+	if err != nil {
+		t.Fatalf("Failed to parse entries: %v", err)
+	}
+
+	expectedKeys := []string{"DATABASE_URL", "API_KEY", "CERT", "PORT"}
+	keys := EntryKeys(entries)
+	if len(keys) != len(expectedKeys) {
+		t.Fatalf("Expected keys %v, got %v", expectedKeys, keys)
+	}
+	for i, key := range expectedKeys {
+		if keys[i] != key {
+			t.Errorf("Expected key %d to be %s, got %s", i, key, keys[i])
+		}
+	}
+
+	if entries[0].Line != 2 || len(entries[0].Comments) != 1 || entries[0].Comments[0] != "Database" {
+		t.Errorf("Unexpected DATABASE_URL entry: %+v", entries[0])
+	}
+	if len(entries[0].RawComments) != 1 || entries[0].RawComments[0] != "# Database" {
+		t.Errorf("Expected raw comment %q, got %q", "# Database", entries[0].RawComments)
+	}
+
+	if entries[1].Value != "quoted # not a comment" || entries[1].Inline != "" {
+		t.Errorf("Unexpected API_KEY entry: %+v", entries[1])
+	}
+
+	if entries[2].Line != 6 || entries[2].EndLine != 7 || entries[2].Value != "line1\nline2" {
+		t.Errorf("Unexpected CERT entry: %+v", entries[2])
+	}
+
+	if entries[3].Value != "3000" || entries[3].Inline != "server port" {
+		t.Errorf("Unexpected PORT entry: %+v", entries[3])
+	}
+}
+
+func TestParseEntriesColumns(t *testing.T) {
+	content := "PORT=3000\n  export  API_KEY = \"abc\"\nHOST:localhost\nEMPTY=\n"
+
+	entries, err := ParseEntries([]byte(content))
+	if err != nil {
+		t.Fatalf("Failed to parse entries: %v", err)
+	}
+
+	expected := []struct {
+		key                 string
+		column, valueColumn int
+	}{
+		{"PORT", 0, 5},
+		{"API_KEY", 10, 20},
+		{"HOST", 0, 5},
+		{"EMPTY", 0, 6},
+	}
+	for i, want := range expected {
+		entry := entries[i]
+		if entry.Key != want.key || entry.Column != want.column || entry.ValueColumn != want.valueColumn {
+			t.Errorf("Expected %s at %d with value at %d, got %s at %d with value at %d",
+				want.key, want.column, want.valueColumn, entry.Key, entry.Column, entry.ValueColumn)
+		}
+	}
+}
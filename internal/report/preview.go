@@ -0,0 +1,50 @@
+package report
+
+import "fmt"
+
+// Preview emits the lines of a change plan. Every line is an info event
+// whose colour comes from its Style; when Pending, a confirmation follows
+// and lines are marked Prompt so --quiet cannot hide part of what is being
+// agreed to.
+type Preview struct {
+	Reporter Reporter
+	Pending  bool
+}
+
+// Heading emits the title of the plan.
+func (p Preview) Heading(name, icon string, fields Fields, format string, args ...interface{}) {
+	p.Reporter.Report(Event{
+		Name:    name,
+		Level:   LevelInfo,
+		Message: fmt.Sprintf(format, args...),
+		Fields:  fields,
+		Icon:    icon,
+		Heading: true,
+		Prompt:  p.Pending,
+	})
+}
+
+// Item emits one indented line of the plan, such as a line of a diff.
+func (p Preview) Item(style Style, name string, fields Fields, format string, args ...interface{}) {
+	p.Reporter.Report(Event{
+		Name:    name,
+		Level:   LevelInfo,
+		Message: fmt.Sprintf(format, args...),
+		Fields:  fields,
+		Indent:  true,
+		Style:   style,
+		Prompt:  p.Pending,
+	})
+}
+
+// Note emits an unindented line of the plan, such as a summary.
+func (p Preview) Note(name, icon string, fields Fields, format string, args ...interface{}) {
+	p.Reporter.Report(Event{
+		Name:    name,
+		Level:   LevelInfo,
+		Message: fmt.Sprintf(format, args...),
+		Fields:  fields,
+		Icon:    icon,
+		Prompt:  p.Pending,
+	})
+}
@@ -0,0 +1,175 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/fatih/color"
+)
+
+// Level is the severity of an event.
+type Level string
+
+const (
+	LevelDebug   Level = "debug"
+	LevelInfo    Level = "info"
+	LevelSuccess Level = "success"
+	LevelWarning Level = "warning"
+	LevelError   Level = "error"
+)
+
+// Fields holds the structured data attached to an event.
+type Fields = map[string]interface{}
+
+// Event is a single user-facing message. Name identifies the event for
+// machine consumers, e.g. "environment.created"; Fields carries its data.
+type Event struct {
+	Name    string `json:"event"`
+	Level   Level  `json:"level"`
+	Message string `json:"message"`
+	Fields  Fields `json:"fields,omitempty"`
+
+	// Presentation hints used by the text reporter only. Style colours a
+	// line independently of its level; Prompt marks lines the user needs
+	// to answer a following confirmation, which are shown even when Quiet.
+	Icon    string `json:"-"`
+	Heading bool   `json:"-"`
+	Indent  bool   `json:"-"`
+	Style   Style  `json:"-"`
+	Prompt  bool   `json:"-"`
+}
+
+// Style is the colour of a line in a preview, such as one line of a diff.
+type Style string
+
+const (
+	StyleAdded   Style = "added"
+	StyleChanged Style = "changed"
+	StyleRemoved Style = "removed"
+)
+
+// Reporter receives events emitted by envguard.
+type Reporter interface {
+	Report(event Event)
+}
+
+// Silent discards every event.
+type Silent struct{}
+
+func (Silent) Report(Event) {}
+
+// TextReporter renders events as colored terminal output. Quiet keeps
+// only warnings and errors; Verbose adds debug events.
+type TextReporter struct {
+	Out     io.Writer
+	Quiet   bool
+	Verbose bool
+}
+
+func NewTextReporter(out io.Writer) *TextReporter {
+	return &TextReporter{Out: out}
+}
+
+func (r *TextReporter) Report(event Event) {
+	if !r.shows(event) {
+		return
+	}
+
+	if event.Heading {
+		fmt.Fprintln(r.Out, color.CyanString(joinIcon(event.Icon, event.Message)))
+		fmt.Fprintln(r.Out, color.CyanString(Rule))
+		return
+	}
+
+	line := joinIcon(event.Icon, event.Message)
+	switch {
+	case event.Style == StyleAdded:
+		line = color.GreenString(line)
+	case event.Style == StyleChanged:
+		line = color.YellowString(line)
+	case event.Style == StyleRemoved:
+		line = color.RedString(line)
+	case event.Level == LevelSuccess:
+		line = color.GreenString(line)
+	case event.Level == LevelInfo:
+		if !event.Indent {
+			line = color.BlueString(line)
+		}
+	case event.Level == LevelWarning:
+		line = color.YellowString(line)
+	case event.Level == LevelError:
+		line = color.RedString(line)
+	case event.Level == LevelDebug:
+		line = color.New(color.Faint).Sprint(line)
+	}
+
+	if event.Indent {
+		line = "   " + line
+	}
+	fmt.Fprintln(r.Out, line)
+}
+
+func (r *TextReporter) shows(event Event) bool {
+	switch event.Level {
+	case LevelDebug:
+		return r.Verbose
+	case LevelWarning, LevelError:
+		return true
+	default:
+		return !r.Quiet || event.Prompt
+	}
+}
+
+// JSONReporter writes one JSON object per event. Debug events are only
+// written when Verbose is set.
+type JSONReporter struct {
+	Out     io.Writer
+	Verbose bool
+
+	mu sync.Mutex
+}
+
+func NewJSONReporter(out io.Writer) *JSONReporter {
+	return &JSONReporter{Out: out}
+}
+
+func (r *JSONReporter) Report(event Event) {
+	if event.Level == LevelDebug && !r.Verbose {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	json.NewEncoder(r.Out).Encode(event)
+}
+
+// Recorder keeps events in memory, for tests and for callers that want to
+// inspect what happened.
+type Recorder struct {
+	Events []Event
+}
+
+func (r *Recorder) Report(event Event) {
+	r.Events = append(r.Events, event)
+}
+
+// Names returns the names of the recorded events in order.
+func (r *Recorder) Names() []string {
+	names := make([]string, 0, len(r.Events))
+	for _, event := range r.Events {
+		names = append(names, event.Name)
+	}
+	return names
+}
+
+// Rule is the horizontal line printed under headings.
+const Rule = "━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━"
+
+func joinIcon(icon, message string) string {
+	if icon == "" {
+		return message
+	}
+	return icon + " " + message
+}
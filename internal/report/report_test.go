@@ -0,0 +1,116 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+)
+
+func TestTextReporterLevels(t *testing.T) {
+	color.NoColor = true
+
+	events := []Event{
+		{Name: "a", Level: LevelDebug, Message: "debug"},
+		{Name: "b", Level: LevelInfo, Message: "info", Icon: "📁"},
+		{Name: "c", Level: LevelWarning, Message: "warning"},
+		{Name: "d", Level: LevelError, Message: "error"},
+	}
+
+	cases := []struct {
+		reporter *TextReporter
+		expected string
+	}{
+		{&TextReporter{}, "📁 info\nwarning\nerror\n"},
+		{&TextReporter{Quiet: true}, "warning\nerror\n"},
+		{&TextReporter{Verbose: true}, "debug\n📁 info\nwarning\nerror\n"},
+	}
+
+	for _, c := range cases {
+		var out bytes.Buffer
+		c.reporter.Out = &out
+		for _, event := range events {
+			c.reporter.Report(event)
+		}
+		if out.String() != c.expected {
+			t.Errorf("Expected %q, got %q", c.expected, out.String())
+		}
+	}
+}
+
+func TestTextReporterHeadingAndItems(t *testing.T) {
+	color.NoColor = true
+
+	var out bytes.Buffer
+	reporter := NewTextReporter(&out)
+	reporter.Report(Event{Level: LevelInfo, Message: "Plan:", Icon: "🚀", Heading: true})
+	reporter.Report(Event{Level: LevelSuccess, Message: "+ KEY", Indent: true})
+
+	expected := "🚀 Plan:\n" + Rule + "\n   + KEY\n"
+	if out.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, out.String())
+	}
+}
+
+func TestTextReporterPromptAndStyle(t *testing.T) {
+	color.NoColor = false
+	defer func() { color.NoColor = true }()
+
+	var out bytes.Buffer
+	reporter := &TextReporter{Out: &out, Quiet: true}
+	reporter.Report(Event{Level: LevelInfo, Message: "hidden", Indent: true, Style: StyleAdded})
+	reporter.Report(Event{Level: LevelInfo, Message: "+ A", Indent: true, Style: StyleAdded, Prompt: true})
+	reporter.Report(Event{Level: LevelInfo, Message: "- B", Indent: true, Style: StyleRemoved, Prompt: true})
+
+	expected := "   " + color.GreenString("+ A") + "\n   " + color.RedString("- B") + "\n"
+	if out.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, out.String())
+	}
+}
+
+func TestPreviewPending(t *testing.T) {
+	color.NoColor = true
+
+	var out bytes.Buffer
+	reporter := &TextReporter{Out: &out, Quiet: true}
+
+	Preview{Reporter: reporter}.Note("plan.summary", "📊", nil, "hidden")
+	pending := Preview{Reporter: reporter, Pending: true}
+	pending.Heading("plan", "📥", nil, "Plan:")
+	pending.Item("", "plan.item", nil, "KEY=%s", "****")
+	pending.Note("plan.summary", "📊", nil, "%d variables", 1)
+
+	expected := "📥 Plan:\n" + Rule + "\n   KEY=****\n📊 1 variables\n"
+	if out.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, out.String())
+	}
+}
+
+func TestJSONReporter(t *testing.T) {
+	var out bytes.Buffer
+	reporter := NewJSONReporter(&out)
+
+	reporter.Report(Event{Name: "debug", Level: LevelDebug, Message: "hidden"})
+	reporter.Report(Event{Name: "environment.created", Level: LevelSuccess, Message: "Created", Icon: "✅", Fields: Fields{"environment": "dev"}})
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("Expected 1 JSON line, got %q", out.String())
+	}
+
+	var decoded map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &decoded); err != nil {
+		t.Fatalf("Invalid JSON line: %v", err)
+	}
+	if decoded["event"] != "environment.created" || decoded["level"] != "success" {
+		t.Errorf("Unexpected event: %v", decoded)
+	}
+	if _, ok := decoded["Icon"]; ok {
+		t.Error("Presentation hints should not be encoded")
+	}
+	if fields, ok := decoded["fields"].(map[string]interface{}); !ok || fields["environment"] != "dev" {
+		t.Errorf("Unexpected fields: %v", decoded["fields"])
+	}
+}
@@ -0,0 +1,66 @@
+package shell
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/crabest/envguard/internal/parser"
+)
+
+// DirenvStdlib defines use_envguard for direnvrc, so that an .envrc can
+// say "use envguard" or "use envguard staging".
+func DirenvStdlib(executable string) string {
+	return fmt.Sprintf(`# use envguard [environment]
+#   Loads the active environment from .env, or the named environment
+#   stored in .envguard/, and reloads when "envguard use" switches or the
+#   loaded file changes.
+use_envguard() {
+  eval "$(%s direnv "$@")"
+}
+`, quote(Bash, executable))
+}
+
+// Direnv returns the bash an .envrc evaluates to load envFile: a
+// watch_file call for the watched paths, so direnv reloads after
+// "envguard use", a sync or an edit, followed by an export for every
+// variable.
+func Direnv(envFile, envName string, warn bool, watched ...string) (string, error) {
+	vars, err := parser.ParseEnvFile(envFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse %s: %w", envFile, err)
+	}
+
+	keys := make([]string, 0, len(vars))
+	for key := range vars {
+		if isIdentifier(key) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	w := &scriptWriter{shell: Bash}
+	quoted := make([]string, len(watched))
+	for i, path := range watched {
+		quoted[i] = quote(Bash, path)
+	}
+	fmt.Fprintf(&w.b, "watch_file %s;\n", strings.Join(quoted, " "))
+	fmt.Fprintf(&w.b, "log_status %s;\n", quote(Bash, fmt.Sprintf("envguard: loading %s (%d variables)", envName, len(keys))))
+	if warn {
+		fmt.Fprintf(&w.b, "log_error %s;\n", quote(Bash, fmt.Sprintf("envguard: ⚠️  %s variables are now set in this shell", envName)))
+	}
+	for _, key := range keys {
+		w.export(key, vars[key])
+	}
+	w.export(EnvVar, envName)
+	return w.String(), nil
+}
+
+// DirenvError returns bash that reports message through direnv and still
+// watches activePath, so the .envrc recovers once an environment is used.
+func DirenvError(activePath, message string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "watch_file %s;\n", quote(Bash, activePath))
+	fmt.Fprintf(&b, "log_error %s;\n", quote(Bash, "envguard: "+message))
+	return b.String()
+}
@@ -0,0 +1,249 @@
+package shell
+
+import (
+	"encoding/base64"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/crabest/envguard/internal/parser"
+	"github.com/crabest/envguard/internal/trust"
+)
+
+// Hook returns the script that installs the hook in shell. executable is
+// the envguard binary the hook calls on every prompt and directory change.
+func Hook(shell, executable string) (string, error) {
+	command := quote(Bash, executable)
+
+	switch shell {
+	case Bash:
+		return fmt.Sprintf(`_envguard_hook() {
+  local previous_exit_status=$?
+  eval "$(%[1]s hook-env bash)"
+  return $previous_exit_status
+}
+if [[ ";${PROMPT_COMMAND[*]:-};" != *";_envguard_hook;"* ]]; then
+  PROMPT_COMMAND="_envguard_hook${PROMPT_COMMAND:+;$PROMPT_COMMAND}"
+fi
+`, command), nil
+	case Zsh:
+		return fmt.Sprintf(`_envguard_hook() {
+  eval "$(%[1]s hook-env zsh)"
+}
+typeset -ag precmd_functions chpwd_functions
+if (( ! ${precmd_functions[(I)_envguard_hook]} )); then
+  precmd_functions=(_envguard_hook $precmd_functions)
+fi
+if (( ! ${chpwd_functions[(I)_envguard_hook]} )); then
+  chpwd_functions=(_envguard_hook $chpwd_functions)
+fi
+`, command), nil
+	case Fish:
+		return fmt.Sprintf(`function __envguard_hook --on-event fish_prompt --on-variable PWD
+    %[1]s hook-env fish | source
+end
+`, quote(Fish, executable)), nil
+	}
+	return "", fmt.Errorf("unsupported shell '%s' (use bash, zsh or fish)", shell)
+}
+
+// Update is the outcome of comparing the loaded state with the project.
+type Update struct {
+	// Script is shell code to evaluate; empty when nothing changed.
+	Script string
+	// Loaded and Unloaded name the environments that were loaded and
+	// unloaded, empty when none.
+	Loaded   string
+	Unloaded string
+	// Dir is the project Loaded belongs to.
+	Dir string
+	// Count is the number of variables loaded.
+	Count int
+	// Blocked is the project that was not loaded because its .env is not
+	// allowed, reported once per change of the project's state.
+	Blocked string
+	// Skipped lists the dangerous names in .env that were not loaded.
+	Skipped []string
+}
+
+// Diff returns the shell code that brings the shell from the state
+// recorded in its environment to the project containing dir: it restores
+// the variables of the previously loaded environment and exports the
+// variables of .env when the project has an active environment and its
+// .env is allowed. Dangerous names are skipped unless permitted.
+func Diff(shell, dir string, lookup func(string) (string, bool)) (Update, error) {
+	getenv := func(key string) string {
+		value, _ := lookup(key)
+		return value
+	}
+
+	loaded := LoadedState(getenv)
+	current, inProject := CurrentState(dir, loaded)
+
+	if inProject && current == loaded {
+		return Update{}, nil
+	}
+
+	var allowance trust.Allowance
+	blocked := ""
+	if inProject {
+		var allowed bool
+		if allowance, allowed = trust.Allowed(current.Dir); !allowed {
+			inProject = false
+			blocked = current.String()
+		}
+	}
+	if !inProject && loaded == (State{}) && getenv(blockedVar) == blocked {
+		return Update{}, nil
+	}
+
+	w := &scriptWriter{shell: shell}
+	update := Update{Unloaded: loaded.Environment}
+
+	// Restore what the previous environment overwrote, and compute the
+	// environment as it was before loading.
+	restored := make(map[string]*string)
+	backup := parseBackup(getenv(backupVar))
+	for _, key := range strings.Fields(getenv(keysVar)) {
+		if value, ok := backup[key]; ok {
+			restored[key] = &value
+			w.export(key, value)
+		} else {
+			restored[key] = nil
+			w.unset(key)
+		}
+	}
+	original := func(key string) (string, bool) {
+		if value, ok := restored[key]; ok {
+			if value == nil {
+				return "", false
+			}
+			return *value, true
+		}
+		return lookup(key)
+	}
+
+	if !inProject {
+		if loaded != (State{}) {
+			for _, name := range []string{stateVar, keysVar, backupVar, EnvVar, DirVar} {
+				w.unset(name)
+			}
+		}
+		if blocked != "" {
+			w.export(blockedVar, blocked)
+			update.Blocked = current.Dir
+		} else {
+			w.unset(blockedVar)
+		}
+		update.Script = w.String()
+		return update, nil
+	}
+
+	// A project without a .env still records its state, so the next
+	// prompt does not look again.
+	vars, err := parser.ParseEnvFile(filepath.Join(current.Dir, ".env"))
+	if err != nil && current.EnvModTime != 0 {
+		return Update{}, fmt.Errorf("failed to parse .env: %w", err)
+	}
+
+	keys := make([]string, 0, len(vars))
+	for key := range vars {
+		switch {
+		case !isIdentifier(key):
+			// Keys such as "app.name" cannot be shell variables.
+		case trust.IsDangerous(key) && !allowance.Permits(key):
+			update.Skipped = append(update.Skipped, key)
+		default:
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	sort.Strings(update.Skipped)
+
+	var saved []string
+	for _, key := range keys {
+		if value, ok := original(key); ok {
+			saved = append(saved, key+"="+base64.StdEncoding.EncodeToString([]byte(value)))
+		}
+		w.export(key, vars[key])
+	}
+
+	w.export(EnvVar, current.Environment)
+	w.export(DirVar, current.Dir)
+	w.export(stateVar, current.String())
+	w.export(keysVar, strings.Join(keys, " "))
+	w.export(backupVar, strings.Join(saved, ":"))
+	if getenv(blockedVar) != "" {
+		w.unset(blockedVar)
+	}
+
+	update.Script = w.String()
+	update.Loaded = current.Environment
+	update.Dir = current.Dir
+	update.Count = len(keys)
+	if update.Loaded == update.Unloaded && current.Dir == loaded.Dir {
+		// A reload of the same environment after .env changed.
+		update.Unloaded = ""
+	}
+	return update, nil
+}
+
+// parseBackup decodes __ENVGUARD_BACKUP: KEY=base64 entries joined by ":".
+func parseBackup(value string) map[string]string {
+	backup := make(map[string]string)
+	for _, entry := range strings.Split(value, ":") {
+		key, encoded, ok := strings.Cut(entry, "=")
+		if !ok {
+			continue
+		}
+		if decoded, err := base64.StdEncoding.DecodeString(encoded); err == nil {
+			backup[key] = string(decoded)
+		}
+	}
+	return backup
+}
+
+func isIdentifier(key string) bool {
+	for i, c := range key {
+		letter := c == '_' || (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z')
+		if !letter && (i == 0 || c < '0' || c > '9') {
+			return false
+		}
+	}
+	return key != ""
+}
+
+type scriptWriter struct {
+	shell string
+	b     strings.Builder
+}
+
+func (w *scriptWriter) export(key, value string) {
+	if w.shell == Fish {
+		fmt.Fprintf(&w.b, "set -gx %s %s;\n", key, quote(Fish, value))
+		return
+	}
+	fmt.Fprintf(&w.b, "export %s=%s;\n", key, quote(w.shell, value))
+}
+
+func (w *scriptWriter) unset(key string) {
+	if w.shell == Fish {
+		fmt.Fprintf(&w.b, "set -e %s;\n", key)
+		return
+	}
+	fmt.Fprintf(&w.b, "unset %s;\n", key)
+}
+
+func (w *scriptWriter) String() string {
+	return w.b.String()
+}
+
+// quote single-quotes value for shell.
+func quote(shell, value string) string {
+	if shell == Fish {
+		value = strings.ReplaceAll(value, `\`, `\\`)
+		return "'" + strings.ReplaceAll(value, "'", `\'`) + "'"
+	}
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
@@ -0,0 +1,82 @@
+package shell
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// DefaultPromptFormat shows the active environment.
+const DefaultPromptFormat = "%e"
+
+// Prompt is the information a prompt format can show.
+type Prompt struct {
+	Environment string
+	Project     string
+	// Warning is set for production-like environments.
+	Warning bool
+}
+
+// PromptFor describes the project containing dir. It reuses the state the
+// hook recorded in the environment while .active is unchanged, and
+// returns false outside a project or without an active environment.
+func PromptFor(dir string, getenv func(string) string, needWarning bool) (Prompt, bool) {
+	state, ok := CurrentState(dir, LoadedState(getenv))
+	if !ok {
+		return Prompt{}, false
+	}
+
+	prompt := Prompt{Environment: state.Environment, Project: filepath.Base(state.Dir)}
+	if needWarning {
+		prompt.Warning = IsProductionLike(state.Dir, state.Environment)
+	}
+	return prompt, true
+}
+
+// FormatPrompt expands format for prompt:
+//
+//	%e  active environment
+//	%p  project directory name
+//	%w  "⚠ " for production-like environments, otherwise nothing
+//	%E  active environment, upper-cased for production-like ones
+//	%%  a literal %
+//
+// Unknown verbs are kept as written.
+func FormatPrompt(format string, prompt Prompt) string {
+	var b strings.Builder
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' || i+1 == len(format) {
+			b.WriteByte(format[i])
+			continue
+		}
+
+		i++
+		switch format[i] {
+		case 'e':
+			b.WriteString(prompt.Environment)
+		case 'p':
+			b.WriteString(prompt.Project)
+		case 'w':
+			if prompt.Warning {
+				b.WriteString("⚠ ")
+			}
+		case 'E':
+			if prompt.Warning {
+				b.WriteString(strings.ToUpper(prompt.Environment))
+			} else {
+				b.WriteString(prompt.Environment)
+			}
+		case '%':
+			b.WriteByte('%')
+		default:
+			b.WriteByte('%')
+			b.WriteByte(format[i])
+		}
+	}
+	return b.String()
+}
+
+// NeedsWarning reports whether format uses a verb that depends on
+// Prompt.Warning, which costs reading the project config.
+func NeedsWarning(format string) bool {
+	return strings.Contains(format, "%w") || strings.Contains(format, "%E")
+}
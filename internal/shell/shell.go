@@ -0,0 +1,156 @@
+// Package shell integrates envguard with interactive shells: a prompt
+// segment showing the active environment, a hook that loads the active
+// environment's variables when entering a project and unloads them on
+// leaving, and the equivalent output for direnv. Everything here only
+// reads the project; it never syncs or creates files, so it is safe to run
+// on every prompt.
+package shell
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/crabest/envguard/internal/envmanager"
+)
+
+// Supported shells.
+const (
+	Bash = "bash"
+	Zsh  = "zsh"
+	Fish = "fish"
+)
+
+// Shells lists the supported shells.
+var Shells = []string{Bash, Zsh, Fish}
+
+// ParseShell validates a shell name.
+func ParseShell(name string) (string, error) {
+	for _, shell := range Shells {
+		if shell == name {
+			return shell, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported shell '%s' (use bash, zsh or fish)", name)
+}
+
+// Variables the hook keeps in the shell. EnvVar and DirVar are meant for
+// prompts and scripts; the others are bookkeeping.
+const (
+	EnvVar    = "ENVGUARD_ENV"
+	DirVar    = "ENVGUARD_DIR"
+	stateVar  = "__ENVGUARD_STATE"
+	keysVar   = "__ENVGUARD_KEYS"
+	backupVar = "__ENVGUARD_BACKUP"
+	// blockedVar holds the state of a project the hook refused to load,
+	// so the refusal is reported once rather than on every prompt.
+	blockedVar = "__ENVGUARD_BLOCKED"
+)
+
+// FindProject returns the closest directory at or above dir containing an
+// .envguard directory.
+func FindProject(dir string) (string, bool) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", false
+	}
+
+	for {
+		if info, err := os.Stat(filepath.Join(dir, envmanager.EnvGuardDir)); err == nil && info.IsDir() {
+			return dir, true
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+// State identifies what the hook loaded: the project, its active
+// environment and the modification times of .env and .active. Comparing
+// it with the files on disk needs two stats and no reads.
+type State struct {
+	Dir         string
+	Environment string
+	EnvModTime  int64
+	ActiveTime  int64
+}
+
+// CurrentState describes the project containing dir as it is on disk. ok
+// is false outside a project or without an active environment.
+func CurrentState(dir string, cached State) (State, bool) {
+	root, found := FindProject(dir)
+	if !found {
+		return State{}, false
+	}
+
+	activePath := filepath.Join(root, envmanager.EnvGuardDir, envmanager.ActiveFile)
+	activeInfo, err := os.Stat(activePath)
+	if err != nil {
+		return State{}, false
+	}
+
+	state := State{Dir: root, ActiveTime: activeInfo.ModTime().UnixNano()}
+	if info, err := os.Stat(filepath.Join(root, ".env")); err == nil {
+		state.EnvModTime = info.ModTime().UnixNano()
+	}
+
+	// .active is only read when it changed since the cached state.
+	if cached.Dir == state.Dir && cached.ActiveTime == state.ActiveTime && cached.Environment != "" {
+		state.Environment = cached.Environment
+		return state, true
+	}
+
+	content, err := os.ReadFile(activePath)
+	if err != nil {
+		return State{}, false
+	}
+	state.Environment = strings.TrimSpace(string(content))
+	return state, state.Environment != ""
+}
+
+// String encodes the state for the __ENVGUARD_STATE variable. Dir comes
+// last since it is the only part that may contain the separator.
+func (s State) String() string {
+	return fmt.Sprintf("%s|%d|%d|%s", s.Environment, s.EnvModTime, s.ActiveTime, s.Dir)
+}
+
+// ParseState decodes a value written by State.String, returning the zero
+// State for anything else.
+func ParseState(value string) State {
+	parts := strings.SplitN(value, "|", 4)
+	if len(parts) != 4 {
+		return State{}
+	}
+
+	envMod, err1 := strconv.ParseInt(parts[1], 10, 64)
+	activeMod, err2 := strconv.ParseInt(parts[2], 10, 64)
+	if err1 != nil || err2 != nil {
+		return State{}
+	}
+	return State{Environment: parts[0], EnvModTime: envMod, ActiveTime: activeMod, Dir: parts[3]}
+}
+
+// LoadedState returns the state the hook recorded in the environment.
+func LoadedState(getenv func(string) string) State {
+	return ParseState(getenv(stateVar))
+}
+
+// IsProductionLike reports whether envName deserves a warning: it is
+// named production or prod, or it is protected in the project at dir.
+func IsProductionLike(dir, envName string) bool {
+	switch strings.ToLower(envName) {
+	case "production", "prod":
+		return true
+	}
+
+	manager, err := envmanager.NewManagerAt(dir)
+	if err != nil {
+		return false
+	}
+	protected, err := manager.IsProtected(envName)
+	return protected || err != nil
+}
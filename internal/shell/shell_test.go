@@ -0,0 +1,342 @@
+package shell
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/crabest/envguard/internal/envmanager"
+	"github.com/crabest/envguard/internal/report"
+	"github.com/crabest/envguard/internal/trust"
+)
+
+func newProject(t *testing.T, active, env string) string {
+	t.Helper()
+	tmpDir, err := os.MkdirTemp("", "envguard-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(tmpDir) })
+
+	if err := os.MkdirAll(filepath.Join(tmpDir, ".envguard"), 0755); err != nil {
+		t.Fatalf("Failed to create .envguard: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(tmpDir, "src", "app"), 0755); err != nil {
+		t.Fatalf("Failed to create src: %v", err)
+	}
+	if active != "" {
+		if err := os.WriteFile(filepath.Join(tmpDir, ".envguard", ".active"), []byte(active+"\n"), 0644); err != nil {
+			t.Fatalf("Failed to write .active: %v", err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, ".env"), []byte(env), 0644); err != nil {
+		t.Fatalf("Failed to write .env: %v", err)
+	}
+	return tmpDir
+}
+
+// allowProject stores allowances in a temporary config directory and
+// allows the project at dir.
+func allowProject(t *testing.T, dir string, permit ...string) {
+	t.Helper()
+	config := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", config)
+	t.Setenv("HOME", config)
+	if _, err := trust.Allow(dir, permit); err != nil {
+		t.Fatalf("Failed to allow %s: %v", dir, err)
+	}
+}
+
+// fakeEnv applies the exports and unsets of a generated bash script.
+type fakeEnv map[string]string
+
+func (e fakeEnv) lookup(key string) (string, bool) {
+	value, ok := e[key]
+	return value, ok
+}
+
+func (e fakeEnv) get(key string) string {
+	return e[key]
+}
+
+func (e fakeEnv) apply(t *testing.T, script string) {
+	t.Helper()
+	for _, line := range strings.Split(strings.TrimSpace(script), "\n") {
+		line = strings.TrimSuffix(line, ";")
+		switch {
+		case line == "":
+		case strings.HasPrefix(line, "unset "):
+			delete(e, strings.TrimPrefix(line, "unset "))
+		case strings.HasPrefix(line, "export "):
+			key, value, _ := strings.Cut(strings.TrimPrefix(line, "export "), "=")
+			e[key] = strings.ReplaceAll(strings.Trim(value, "'"), `'\''`, "'")
+		default:
+			t.Fatalf("Unexpected script line %q", line)
+		}
+	}
+}
+
+func TestFormatPrompt(t *testing.T) {
+	prompt := Prompt{Environment: "production", Project: "api", Warning: true}
+
+	tests := map[string]string{
+		"%e":          "production",
+		"[%w%e] ":     "[⚠ production] ",
+		"%p:%E 100%%": "api:PRODUCTION 100%",
+		"%x%":         "%x%",
+	}
+	for format, expected := range tests {
+		if got := FormatPrompt(format, prompt); got != expected {
+			t.Errorf("FormatPrompt(%q) = %q, expected %q", format, got, expected)
+		}
+	}
+
+	if got := FormatPrompt("[%w%E]", Prompt{Environment: "dev"}); got != "[dev]" {
+		t.Errorf("Expected no warning for dev, got %q", got)
+	}
+}
+
+func TestPromptFor(t *testing.T) {
+	dir := newProject(t, "staging", "")
+
+	prompt, ok := PromptFor(filepath.Join(dir, "src", "app"), fakeEnv{}.get, true)
+	if !ok || prompt.Environment != "staging" || prompt.Project != filepath.Base(dir) || prompt.Warning {
+		t.Errorf("Unexpected prompt: %+v, %v", prompt, ok)
+	}
+
+	// The hook's state is trusted while .active keeps its modification time.
+	state, _ := CurrentState(dir, State{})
+	state.Environment = "cached"
+	prompt, _ = PromptFor(dir, fakeEnv{stateVar: state.String()}.get, false)
+	if prompt.Environment != "cached" {
+		t.Errorf("Expected the cached environment, got %+v", prompt)
+	}
+
+	if _, ok := PromptFor(os.TempDir(), fakeEnv{}.get, false); ok {
+		t.Error("Expected no prompt outside a project")
+	}
+	if _, ok := PromptFor(newProject(t, "", ""), fakeEnv{}.get, false); ok {
+		t.Error("Expected no prompt without an active environment")
+	}
+}
+
+func TestParseState(t *testing.T) {
+	state := State{Dir: "/home/me/a|b", Environment: "dev", EnvModTime: 1, ActiveTime: 2}
+	if parsed := ParseState(state.String()); parsed != state {
+		t.Errorf("Expected %+v, got %+v", state, parsed)
+	}
+	if parsed := ParseState("garbage"); parsed != (State{}) {
+		t.Errorf("Expected zero state, got %+v", parsed)
+	}
+}
+
+func TestDiffLoadsAndRestores(t *testing.T) {
+	dir := newProject(t, "production", "PORT=3000\nNAME=\"it's\"\napp.name=x\n")
+	allowProject(t, dir)
+	env := fakeEnv{"PORT": "1", "PATH": "/bin"}
+
+	update, err := Diff(Bash, filepath.Join(dir, "src"), env.lookup)
+	if err != nil {
+		t.Fatalf("Failed to diff: %v", err)
+	}
+	if update.Loaded != "production" || update.Count != 2 || update.Dir != dir {
+		t.Errorf("Unexpected update: %+v", update)
+	}
+	env.apply(t, update.Script)
+
+	if env["PORT"] != "3000" || env["NAME"] != "it's" || env[EnvVar] != "production" {
+		t.Errorf("Expected .env to be loaded, got %v", env)
+	}
+
+	// Nothing changed: no output.
+	if update, err := Diff(Bash, dir, env.lookup); err != nil || update.Script != "" {
+		t.Errorf("Expected no script on an unchanged project, got %+v, %v", update, err)
+	}
+
+	update, err = Diff(Bash, os.TempDir(), env.lookup)
+	if err != nil {
+		t.Fatalf("Failed to diff: %v", err)
+	}
+	if update.Unloaded != "production" {
+		t.Errorf("Expected production to be unloaded, got %+v", update)
+	}
+	env.apply(t, update.Script)
+
+	expected := fakeEnv{"PORT": "1", "PATH": "/bin"}
+	if len(env) != len(expected) || env["PORT"] != "1" {
+		t.Errorf("Expected the original environment back, got %v", env)
+	}
+}
+
+func TestDiffRequiresAllow(t *testing.T) {
+	dir := newProject(t, "development", "PORT=3000\nPATH=/tmp/evil\nLD_PRELOAD=/tmp/evil.so\n")
+	allowProject(t, dir)
+	if err := trust.Revoke(dir); err != nil {
+		t.Fatalf("Failed to revoke: %v", err)
+	}
+	env := fakeEnv{"PATH": "/bin"}
+
+	update, err := Diff(Bash, dir, env.lookup)
+	if err != nil {
+		t.Fatalf("Failed to diff: %v", err)
+	}
+	if update.Blocked != dir || update.Loaded != "" {
+		t.Errorf("Expected the project to be blocked, got %+v", update)
+	}
+	env.apply(t, update.Script)
+	if _, ok := env["PORT"]; ok {
+		t.Errorf("Expected nothing loaded before allow, got %v", env)
+	}
+
+	// The refusal is reported once.
+	if update, err := Diff(Bash, dir, env.lookup); err != nil || update.Script != "" || update.Blocked != "" {
+		t.Errorf("Expected no repeated report, got %+v, %v", update, err)
+	}
+
+	if _, err := trust.Allow(dir, nil); err != nil {
+		t.Fatalf("Failed to allow: %v", err)
+	}
+	update, err = Diff(Bash, dir, env.lookup)
+	if err != nil {
+		t.Fatalf("Failed to diff: %v", err)
+	}
+	env.apply(t, update.Script)
+	if env["PORT"] != "3000" || env["PATH"] != "/bin" || env[blockedVar] != "" {
+		t.Errorf("Expected PORT loaded and PATH kept, got %v", env)
+	}
+	if strings.Join(update.Skipped, " ") != "LD_PRELOAD PATH" {
+		t.Errorf("Expected dangerous names skipped, got %v", update.Skipped)
+	}
+
+	// Editing .env withdraws the allowance and unloads the project.
+	if err := os.WriteFile(filepath.Join(dir, ".env"), []byte("PORT=4000\n"), 0644); err != nil {
+		t.Fatalf("Failed to write .env: %v", err)
+	}
+	if err := os.Chtimes(filepath.Join(dir, ".env"), time.Now(), time.Now().Add(time.Second)); err != nil {
+		t.Fatalf("Failed to touch .env: %v", err)
+	}
+	update, err = Diff(Bash, dir, env.lookup)
+	if err != nil {
+		t.Fatalf("Failed to diff: %v", err)
+	}
+	env.apply(t, update.Script)
+	if update.Blocked != dir || update.Unloaded != "development" || env["PORT"] != "" {
+		t.Errorf("Expected the edited .env to be unloaded and blocked, got %+v, %v", update, env)
+	}
+
+	if _, err := trust.Allow(dir, []string{"PATH"}); err != nil {
+		t.Fatalf("Failed to allow: %v", err)
+	}
+	if allowance, ok := trust.Allowed(dir); !ok || !allowance.Permits("PATH") {
+		t.Errorf("Expected PATH to be permitted, got %+v, %v", allowance, ok)
+	}
+	if _, err := trust.Allow(dir, []string{"PORT"}); err == nil {
+		t.Error("Expected error permitting a name that is not dangerous")
+	}
+}
+
+func TestDiffKeepsLoadingAfterUse(t *testing.T) {
+	dir := newProject(t, "", "")
+	for name, content := range map[string]string{"dev": "MODE=dev\n", "prod": "MODE=prod\n"} {
+		if err := os.WriteFile(filepath.Join(dir, ".envguard", name+".env"), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	manager, err := envmanager.NewManagerAt(dir)
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	manager.SetReporter(report.NewJSONReporter(io.Discard))
+	if err := manager.UseEnvironment("dev"); err != nil {
+		t.Fatalf("Failed to use dev: %v", err)
+	}
+	allowProject(t, dir)
+	env := fakeEnv{}
+
+	update, err := Diff(Bash, dir, env.lookup)
+	if err != nil {
+		t.Fatalf("Failed to diff: %v", err)
+	}
+	env.apply(t, update.Script)
+
+	// envguard rewriting .env is not an edit that needs a new allow
+	if err := manager.UseEnvironment("prod"); err != nil {
+		t.Fatalf("Failed to use prod: %v", err)
+	}
+	later := time.Now().Add(time.Second)
+	os.Chtimes(filepath.Join(dir, ".envguard", ".active"), later, later)
+	update, err = Diff(Bash, dir, env.lookup)
+	if err != nil {
+		t.Fatalf("Failed to diff: %v", err)
+	}
+	env.apply(t, update.Script)
+	if update.Blocked != "" || env["MODE"] != "prod" {
+		t.Errorf("Expected prod to be loaded after use, got %+v, %v", update, env)
+	}
+}
+
+func TestHook(t *testing.T) {
+	for _, name := range Shells {
+		script, err := Hook(name, "/opt/env guard/envguard")
+		if err != nil {
+			t.Fatalf("Failed to generate %s hook: %v", name, err)
+		}
+		if !strings.Contains(script, "hook-env "+name) {
+			t.Errorf("Expected %s hook to call hook-env:\n%s", name, script)
+		}
+	}
+
+	script, _ := Hook(Bash, "/opt/env guard/envguard")
+	if !strings.Contains(script, `"$('/opt/env guard/envguard' hook-env bash)"`) {
+		t.Errorf("Expected the executable to be quoted:\n%s", script)
+	}
+
+	if _, err := Hook("tcsh", "envguard"); err == nil {
+		t.Error("Expected error for an unsupported shell")
+	}
+	if quote(Fish, `a'b\c`) != `'a\'b\\c'` {
+		t.Errorf("Unexpected fish quoting: %s", quote(Fish, `a'b\c`))
+	}
+}
+
+func TestDirenv(t *testing.T) {
+	dir := newProject(t, "staging", "")
+	envFile := filepath.Join(dir, ".envguard", "staging.env")
+	if err := os.WriteFile(envFile, []byte("PORT=3000\nGREETING=\"it's ${PORT}\"\n"), 0644); err != nil {
+		t.Fatalf("Failed to write env file: %v", err)
+	}
+	activePath := filepath.Join(dir, ".envguard", ".active")
+
+	script, err := Direnv(envFile, "staging", false, activePath, envFile)
+	if err != nil {
+		t.Fatalf("Failed to generate direnv output: %v", err)
+	}
+
+	for _, line := range []string{
+		"watch_file '" + activePath + "' '" + envFile + "';",
+		`export GREETING='it'\''s 3000';`,
+		"export PORT='3000';",
+		"export ENVGUARD_ENV='staging';",
+	} {
+		if !strings.Contains(script, line+"\n") {
+			t.Errorf("Expected %q in:\n%s", line, script)
+		}
+	}
+	if strings.Contains(script, "log_error") {
+		t.Errorf("Expected no warning for staging:\n%s", script)
+	}
+
+	if _, err := Direnv(filepath.Join(dir, "missing.env"), "missing", false, activePath); err == nil {
+		t.Error("Expected error for a missing env file")
+	}
+
+	if errScript := DirenvError(activePath, "no active environment"); !strings.Contains(errScript, "watch_file") {
+		t.Errorf("Expected errors to keep watching .active:\n%s", errScript)
+	}
+	if stdlib := DirenvStdlib("envguard"); !strings.Contains(stdlib, "use_envguard() {") {
+		t.Errorf("Unexpected stdlib:\n%s", stdlib)
+	}
+}
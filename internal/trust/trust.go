@@ -0,0 +1,169 @@
+// Package trust records which projects' .env files the user allowed the
+// shell hook to load, like "direnv allow". An allowance is kept per project
+// under the user's config directory and holds a hash of .env, so any edit
+// outside envguard has to be allowed again.
+package trust
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Allowance records that the user trusts a project's .env: the hook only
+// loads it while the file still has the hash it had when allowed or when
+// envguard last wrote it. Permit
+// lists the dangerous names (see IsDangerous) the .env may set.
+type Allowance struct {
+	Dir     string   `json:"dir"`
+	EnvHash string   `json:"env_hash"`
+	Permit  []string `json:"permit,omitempty"`
+}
+
+// dangerousNames change how the shell itself runs commands; a .env from
+// an untrusted checkout setting them could run code in the user's shell.
+var dangerousNames = map[string]bool{
+	"PATH":           true,
+	"BASH_ENV":       true,
+	"ENV":            true,
+	"PROMPT_COMMAND": true,
+	"IFS":            true,
+}
+
+// IsDangerous reports whether the shell hook skips key unless it is
+// permitted.
+func IsDangerous(key string) bool {
+	return dangerousNames[key] || strings.HasPrefix(key, "LD_") || strings.HasPrefix(key, "DYLD_")
+}
+
+// allowPath returns the file recording the allowance for the project at
+// dir, under the user's config directory.
+func allowPath(dir string) (string, error) {
+	config, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find the user config directory: %w", err)
+	}
+	sum := sha256.Sum256([]byte(dir))
+	return filepath.Join(config, "envguard", "allow", hex.EncodeToString(sum[:])), nil
+}
+
+// hashEnv hashes the .env of the project at dir; a missing .env hashes
+// like an empty one.
+func hashEnv(dir string) (string, error) {
+	content, err := os.ReadFile(filepath.Join(dir, ".env"))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return "", err
+	}
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Allow trusts the current .env of the project at dir, permitting the
+// dangerous names in permit. It replaces any earlier allowance.
+func Allow(dir string, permit []string) (Allowance, error) {
+	hash, err := hashEnv(dir)
+	if err != nil {
+		return Allowance{}, fmt.Errorf("failed to read .env: %w", err)
+	}
+	for _, name := range permit {
+		if !IsDangerous(name) {
+			return Allowance{}, fmt.Errorf("'%s' does not need to be permitted", name)
+		}
+	}
+	permit = append([]string(nil), permit...)
+	sort.Strings(permit)
+
+	path, err := allowPath(dir)
+	if err != nil {
+		return Allowance{}, err
+	}
+	allowance := Allowance{Dir: dir, EnvHash: hash, Permit: permit}
+	content, err := json.MarshalIndent(allowance, "", "  ")
+	if err != nil {
+		return Allowance{}, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return Allowance{}, fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, append(content, '\n'), 0600); err != nil {
+		return Allowance{}, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return allowance, nil
+}
+
+// Revoke removes the allowance of the project at dir, if any.
+func Revoke(dir string) error {
+	path, err := allowPath(dir)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// Refresh re-hashes the .env of the project at dir after envguard itself
+// rewrote it, such as on "envguard use", so that only edits made outside
+// envguard need a new allow. Projects that were never allowed stay so.
+func Refresh(dir string) error {
+	path, err := allowPath(dir)
+	if err != nil {
+		// Without a config directory nothing can have been allowed.
+		return nil
+	}
+	allowance, ok := read(path, dir)
+	if !ok {
+		return nil
+	}
+	_, err = Allow(dir, allowance.Permit)
+	return err
+}
+
+// Allowed returns the allowance of the project at dir. ok is false when
+// the project was never allowed or its .env changed since.
+func Allowed(dir string) (Allowance, bool) {
+	path, err := allowPath(dir)
+	if err != nil {
+		return Allowance{}, false
+	}
+	allowance, ok := read(path, dir)
+	if !ok {
+		return Allowance{}, false
+	}
+
+	hash, err := hashEnv(dir)
+	if err != nil || hash != allowance.EnvHash {
+		return Allowance{}, false
+	}
+	return allowance, true
+}
+
+// read returns the allowance stored at path for the project at dir.
+func read(path, dir string) (Allowance, bool) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return Allowance{}, false
+	}
+	var allowance Allowance
+	if err := json.Unmarshal(content, &allowance); err != nil || allowance.Dir != dir {
+		return Allowance{}, false
+	}
+	return allowance, true
+}
+
+// Permits reports whether the allowance lets .env set the dangerous key.
+func (a Allowance) Permits(key string) bool {
+	for _, name := range a.Permit {
+		if name == key {
+			return true
+		}
+	}
+	return false
+}
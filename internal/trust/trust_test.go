@@ -0,0 +1,77 @@
+package trust
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func newProject(t *testing.T, env string) string {
+	t.Helper()
+	config := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", config)
+	t.Setenv("HOME", config)
+
+	dir := t.TempDir()
+	writeEnv(t, dir, env)
+	return dir
+}
+
+func writeEnv(t *testing.T, dir, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, ".env"), []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write .env: %v", err)
+	}
+}
+
+func TestAllowTracksEnvContent(t *testing.T) {
+	dir := newProject(t, "PORT=3000\n")
+
+	if _, ok := Allowed(dir); ok {
+		t.Error("Expected a new project not to be allowed")
+	}
+	if _, err := Allow(dir, []string{"LD_PRELOAD", "PATH"}); err != nil {
+		t.Fatalf("Failed to allow: %v", err)
+	}
+	allowance, ok := Allowed(dir)
+	if !ok || !allowance.Permits("PATH") || allowance.Permits("IFS") {
+		t.Errorf("Unexpected allowance: %+v, %v", allowance, ok)
+	}
+
+	writeEnv(t, dir, "PORT=4000\n")
+	if _, ok := Allowed(dir); ok {
+		t.Error("Expected an edited .env to need a new allow")
+	}
+
+	if err := Refresh(dir); err != nil {
+		t.Fatalf("Failed to refresh: %v", err)
+	}
+	if allowance, ok := Allowed(dir); !ok || !allowance.Permits("LD_PRELOAD") {
+		t.Errorf("Expected a refresh to keep the allowance and its permits, got %+v, %v", allowance, ok)
+	}
+
+	if err := Revoke(dir); err != nil {
+		t.Fatalf("Failed to revoke: %v", err)
+	}
+	if err := Refresh(dir); err != nil {
+		t.Fatalf("Failed to refresh: %v", err)
+	}
+	if _, ok := Allowed(dir); ok {
+		t.Error("Expected a refresh not to allow a project that was not allowed")
+	}
+}
+
+func TestIsDangerous(t *testing.T) {
+	for key, expected := range map[string]bool{
+		"PATH":            true,
+		"LD_PRELOAD":      true,
+		"DYLD_INSERT_LIB": true,
+		"PROMPT_COMMAND":  true,
+		"PORT":            false,
+		"MY_PATH":         false,
+	} {
+		if IsDangerous(key) != expected {
+			t.Errorf("IsDangerous(%q) = %v, expected %v", key, !expected, expected)
+		}
+	}
+}
@@ -0,0 +1,219 @@
+// Package usage finds the environment variables source code reads, by
+// matching the usual access patterns of each language. Only reads with a
+// literal variable name are found; os.Getenv(name) with a computed name
+// is invisible to it.
+package usage
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Supported languages.
+const (
+	LanguageGo         = "go"
+	LanguageJavaScript = "javascript"
+	LanguageTypeScript = "typescript"
+	LanguagePython     = "python"
+	LanguageRuby       = "ruby"
+)
+
+// Read is a single place where source code reads a variable.
+type Read struct {
+	Key      string `json:"key"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Language string `json:"language"`
+}
+
+// Result is the outcome of a scan.
+type Result struct {
+	Reads []Read
+	// Files counts the scanned files per language.
+	Files map[string]int
+}
+
+// Keys returns the distinct variables read, sorted.
+func (r *Result) Keys() []string {
+	seen := make(map[string]bool)
+	var keys []string
+	for _, read := range r.Reads {
+		if !seen[read.Key] {
+			seen[read.Key] = true
+			keys = append(keys, read.Key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// SkippedDirs are never descended into: dependencies, build output and
+// version control metadata.
+var SkippedDirs = []string{".git", ".envguard", "node_modules", "vendor", "dist", "build", ".next", "__pycache__", ".venv", "venv", "target"}
+
+// maxFileSize keeps bundles and generated blobs out of the scan.
+const maxFileSize = 1 << 20
+
+var extensions = map[string]string{
+	".go":      LanguageGo,
+	".js":      LanguageJavaScript,
+	".jsx":     LanguageJavaScript,
+	".mjs":     LanguageJavaScript,
+	".cjs":     LanguageJavaScript,
+	".ts":      LanguageTypeScript,
+	".tsx":     LanguageTypeScript,
+	".mts":     LanguageTypeScript,
+	".cts":     LanguageTypeScript,
+	".py":      LanguagePython,
+	".rb":      LanguageRuby,
+	".rake":    LanguageRuby,
+	".erb":     LanguageRuby,
+	".gemspec": LanguageRuby,
+}
+
+const name = `([A-Za-z_][A-Za-z0-9_]*)`
+
+var (
+	goPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`\b(?:os|syscall)\.(?:Getenv|LookupEnv)\(\s*["` + "`" + `]` + name + `["` + "`" + `]\s*\)`),
+		// Struct tags of env loaders such as caarlos0/env and envconfig.
+		regexp.MustCompile(`\b(?:env|envconfig):"` + name + `[",]`),
+	}
+
+	jsPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`\b(?:process\.env|import\.meta\.env)\.` + name),
+		regexp.MustCompile(`\b(?:process\.env|import\.meta\.env)\[\s*["'` + "`" + `]` + name + `["'` + "`" + `]\s*\]`),
+	}
+	jsDestructuring = regexp.MustCompile(`\{([^{}]*)\}\s*=\s*(?:process\.env|import\.meta\.env)\b`)
+
+	pythonPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`\b(?:os\.)?environ\s*\[\s*["']` + name + `["']\s*\]`),
+		regexp.MustCompile(`\b(?:os\.)?environ\.(?:get|setdefault|pop)\(\s*["']` + name + `["']`),
+		regexp.MustCompile(`\b(?:os\.)?getenv\(\s*["']` + name + `["']`),
+	}
+
+	rubyPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`\bENV\s*\[\s*["']` + name + `["']\s*\]`),
+		regexp.MustCompile(`\bENV\.(?:fetch|key\?|has_key\?|include\?)[\s(]\s*["']` + name + `["']`),
+	}
+)
+
+// Language returns the language of path from its extension, or "" when it
+// is not scanned.
+func Language(path string) string {
+	return extensions[strings.ToLower(filepath.Ext(path))]
+}
+
+// Scan walks roots, which may be directories or files, and returns every
+// variable read found in files of a supported language.
+func Scan(roots []string) (*Result, error) {
+	result := &Result{Files: make(map[string]int)}
+
+	for _, root := range roots {
+		err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if entry.IsDir() {
+				if path != root && isSkippedDir(entry.Name()) {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+
+			language := Language(path)
+			if language == "" {
+				return nil
+			}
+			if info, err := entry.Info(); err != nil || info.Size() > maxFileSize {
+				return err
+			}
+
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			result.Files[language]++
+			result.Reads = append(result.Reads, ScanSource(path, content, language)...)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// ScanSource returns the variable reads in content, in file order.
+func ScanSource(path string, content []byte, language string) []Read {
+	text := string(content)
+
+	var patterns []*regexp.Regexp
+	switch language {
+	case LanguageGo:
+		patterns = goPatterns
+	case LanguageJavaScript, LanguageTypeScript:
+		patterns = jsPatterns
+	case LanguagePython:
+		patterns = pythonPatterns
+	case LanguageRuby:
+		patterns = rubyPatterns
+	}
+
+	var reads []Read
+	add := func(key string, offset int) {
+		reads = append(reads, Read{Key: key, File: path, Line: lineAt(text, offset), Language: language})
+	}
+
+	for _, pattern := range patterns {
+		for _, match := range pattern.FindAllStringSubmatchIndex(text, -1) {
+			add(text[match[2]:match[3]], match[2])
+		}
+	}
+
+	if language == LanguageJavaScript || language == LanguageTypeScript {
+		// const { API_URL, PORT: port = 3000 } = process.env
+		for _, match := range jsDestructuring.FindAllStringSubmatchIndex(text, -1) {
+			offset := match[2]
+			for _, part := range strings.Split(text[match[2]:match[3]], ",") {
+				key := strings.TrimSpace(part)
+				key = strings.TrimSpace(key[:indexAny(key, ":=")])
+				key = strings.Trim(key, `"'`)
+				if key != "" && !strings.HasPrefix(key, "...") {
+					add(key, offset+strings.Index(part, key))
+				}
+				offset += len(part) + 1
+			}
+		}
+	}
+
+	sort.SliceStable(reads, func(a, b int) bool {
+		return reads[a].Line < reads[b].Line
+	})
+	return reads
+}
+
+func isSkippedDir(name string) bool {
+	for _, skipped := range SkippedDirs {
+		if name == skipped {
+			return true
+		}
+	}
+	return false
+}
+
+func lineAt(text string, offset int) int {
+	return strings.Count(text[:offset], "\n") + 1
+}
+
+func indexAny(s, chars string) int {
+	if i := strings.IndexAny(s, chars); i >= 0 {
+		return i
+	}
+	return len(s)
+}
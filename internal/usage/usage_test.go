@@ -0,0 +1,84 @@
+package usage
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func keys(reads []Read) []string {
+	var names []string
+	for _, read := range reads {
+		names = append(names, read.Key)
+	}
+	return names
+}
+
+func TestScanSource(t *testing.T) {
+	tests := []struct {
+		language string
+		source   string
+		expected []string
+	}{
+		{LanguageGo, "port := os.Getenv(\"PORT\")\nif v, ok := os.LookupEnv(`DEBUG`); ok {}\nname := os.Getenv(key)\n", []string{"PORT", "DEBUG"}},
+		{LanguageGo, "type Config struct {\n\tURL string `env:\"API_URL,required\"`\n\tTTL int `envconfig:\"CACHE_TTL\"`\n}\n", []string{"API_URL", "CACHE_TTL"}},
+		{LanguageJavaScript, "const url = process.env.API_URL;\nconst key = process.env['STRIPE_KEY'];\nconst mode = import.meta.env.VITE_MODE;\n", []string{"API_URL", "STRIPE_KEY", "VITE_MODE"}},
+		{LanguageTypeScript, "const {\n  DB_HOST,\n  DB_PORT: port = '5432',\n  ...rest\n} = process.env;\n", []string{"DB_HOST", "DB_PORT"}},
+		{LanguagePython, "a = os.environ[\"A\"]\nb = os.environ.get('B', 'x')\nc = os.getenv(\"C\")\nd = environ['D']\n", []string{"A", "B", "C", "D"}},
+		{LanguageRuby, "ENV['A']\nENV.fetch(\"B\")\nENV.fetch 'C', nil\n", []string{"A", "B", "C"}},
+	}
+
+	for _, test := range tests {
+		reads := ScanSource("file", []byte(test.source), test.language)
+		if !reflect.DeepEqual(keys(reads), test.expected) {
+			t.Errorf("%s: expected %v, got %+v", test.language, test.expected, reads)
+		}
+	}
+
+	reads := ScanSource("app.ts", []byte("const {\n  DB_HOST,\n  DB_PORT,\n} = process.env;\n"), LanguageTypeScript)
+	if len(reads) != 2 || reads[0].Line != 2 || reads[1].Line != 3 {
+		t.Errorf("Expected destructured keys on lines 2 and 3, got %+v", reads)
+	}
+}
+
+func TestScan(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "envguard-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	files := map[string]string{
+		"main.go":                   "os.Getenv(\"PORT\")\n",
+		"web/app.ts":                "process.env.API_URL\n",
+		"web/node_modules/lib/x.js": "process.env.IGNORED\n",
+		"scripts/job.py":            "os.getenv('QUEUE')\n",
+		"README.md":                 "process.env.DOCS\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(tmpDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	result, err := Scan([]string{tmpDir})
+	if err != nil {
+		t.Fatalf("Failed to scan: %v", err)
+	}
+
+	if !reflect.DeepEqual(result.Keys(), []string{"API_URL", "PORT", "QUEUE"}) {
+		t.Errorf("Unexpected keys: %v", result.Keys())
+	}
+	if result.Files[LanguageGo] != 1 || result.Files[LanguageTypeScript] != 1 || result.Files[LanguageJavaScript] != 0 {
+		t.Errorf("Unexpected file counts: %v", result.Files)
+	}
+
+	if _, err := Scan([]string{filepath.Join(tmpDir, "missing")}); err == nil {
+		t.Error("Expected error for a missing root")
+	}
+}
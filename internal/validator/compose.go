@@ -0,0 +1,195 @@
+package validator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/crabest/envguard/internal/compose"
+	"github.com/crabest/envguard/internal/parser"
+)
+
+// Issue codes reported by CheckCompose.
+const (
+	CodeComposeUndefined  = "compose-undefined"
+	CodeComposeUndeclared = "compose-undeclared"
+	CodeComposeUnused     = "compose-unused"
+)
+
+// CheckCompose compares the variables a compose file reads with the keys
+// of envFile and exampleFile. It reports variables compose needs that
+// envFile does not define, variables missing from exampleFile, and keys no
+// service consumes. An empty exampleFile skips the comparison with it.
+func CheckCompose(file *compose.File, envFile string, envEntries []parser.Entry, exampleFile string, exampleEntries []parser.Entry) []Issue {
+	defined := firstEntries(envEntries)
+	declared := firstEntries(exampleEntries)
+
+	var order []string
+	usages := make(map[string][]compose.Usage)
+	for _, usage := range file.Usages {
+		if _, seen := usages[usage.Name]; !seen {
+			order = append(order, usage.Name)
+		}
+		usages[usage.Name] = append(usages[usage.Name], usage)
+	}
+
+	var issues []Issue
+	for _, name := range order {
+		users := describeUsers(usages[name])
+
+		if _, ok := defined[name]; !ok && !isSetInProcess(name) {
+			if issue, needed := undefinedIssue(file.Path, envFile, usages[name]); needed {
+				issues = append(issues, issue)
+			}
+		}
+
+		if _, ok := declared[name]; exampleFile != "" && !ok {
+			issues = append(issues, Issue{
+				Severity: SeverityWarning,
+				Code:     CodeComposeUndeclared,
+				Key:      name,
+				File:     file.Path,
+				Line:     usages[name][0].Line,
+				Message:  fmt.Sprintf("%s is used by %s but not declared in %s", name, users, exampleFile),
+			})
+		}
+	}
+
+	// A service loading the .env itself receives every key in it.
+	if len(file.EnvFileUsers(envFile)) > 0 {
+		return issues
+	}
+
+	used := consumedKeys(usages, envEntries)
+	var unused []Issue
+	for _, entry := range envEntries {
+		if used[entry.Key] || defined[entry.Key].Line != entry.Line {
+			continue
+		}
+		unused = append(unused, Issue{
+			Severity: SeverityWarning,
+			Code:     CodeComposeUnused,
+			Key:      entry.Key,
+			File:     envFile,
+			Line:     entry.Line,
+			Message:  fmt.Sprintf("%s is defined but no service uses it", entry.Key),
+		})
+	}
+	for _, entry := range exampleEntries {
+		if _, inEnv := defined[entry.Key]; used[entry.Key] || inEnv || declared[entry.Key].Line != entry.Line {
+			continue
+		}
+		unused = append(unused, Issue{
+			Severity: SeverityWarning,
+			Code:     CodeComposeUnused,
+			Key:      entry.Key,
+			File:     exampleFile,
+			Line:     entry.Line,
+			Message:  fmt.Sprintf("%s is declared but no service uses it", entry.Key),
+		})
+	}
+
+	return append(issues, unused...)
+}
+
+// undefinedIssue reports a variable envFile does not define, unless every
+// usage has a fallback. Pass-through variables only warn: the container
+// starts without them, while an empty ${VAR} ends up in the configuration.
+func undefinedIssue(composeFile, envFile string, usages []compose.Usage) (Issue, bool) {
+	var needed []compose.Usage
+	var first *compose.Usage
+	severity := SeverityWarning
+	for i, usage := range usages {
+		if usage.HasFallback() {
+			continue
+		}
+		needed = append(needed, usage)
+		if first == nil {
+			first = &usages[i]
+		}
+		if !usage.Passthrough && severity == SeverityWarning {
+			severity = SeverityError
+			first = &usages[i]
+		}
+	}
+	if first == nil {
+		return Issue{}, false
+	}
+
+	users := describeUsers(needed)
+
+	message := fmt.Sprintf("%s is used by %s but not defined in %s", first.Name, users, envFile)
+	switch {
+	case first.Passthrough:
+		message = fmt.Sprintf("%s is passed to %s but not defined in %s", first.Name, users, envFile)
+	case first.Reference.Required() && first.Reference.Argument != "":
+		message += ": " + first.Reference.Argument
+	}
+
+	return Issue{
+		Severity: severity,
+		Code:     CodeComposeUndefined,
+		Key:      first.Name,
+		File:     composeFile,
+		Line:     first.Line,
+		Message:  message,
+	}, true
+}
+
+// consumedKeys returns the variables compose reads, together with the keys
+// their values in entries reference.
+func consumedKeys(usages map[string][]compose.Usage, entries []parser.Entry) map[string]bool {
+	byKey := firstEntries(entries)
+	used := make(map[string]bool)
+
+	var pending []string
+	for name := range usages {
+		pending = append(pending, name)
+	}
+	for len(pending) > 0 {
+		name := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		if used[name] {
+			continue
+		}
+		used[name] = true
+
+		if entry, ok := byKey[name]; ok && entry.Quote != '\'' {
+			for _, ref := range parser.FindReferences(entry.RawValue, parser.InterpolateCompose) {
+				pending = append(pending, ref.Name)
+			}
+		}
+	}
+	return used
+}
+
+// describeUsers names the services in usages, e.g. "services api, worker".
+func describeUsers(usages []compose.Usage) string {
+	var services []string
+	seen := make(map[string]bool)
+	for _, usage := range usages {
+		if usage.Service != "" && !seen[usage.Service] {
+			seen[usage.Service] = true
+			services = append(services, usage.Service)
+		}
+	}
+	sort.Strings(services)
+
+	switch len(services) {
+	case 0:
+		return "the compose file"
+	case 1:
+		return "service " + services[0]
+	}
+	return "services " + strings.Join(services, ", ")
+}
+
+func firstEntries(entries []parser.Entry) map[string]parser.Entry {
+	byKey := make(map[string]parser.Entry)
+	for _, entry := range entries {
+		if _, seen := byKey[entry.Key]; !seen {
+			byKey[entry.Key] = entry
+		}
+	}
+	return byKey
+}
@@ -0,0 +1,82 @@
+package validator
+
+import (
+	"testing"
+
+	"github.com/crabest/envguard/internal/compose"
+	"github.com/crabest/envguard/internal/parser"
+)
+
+func TestCheckCompose(t *testing.T) {
+	file, err := compose.Parse([]byte(`services:
+  api:
+    image: api:${ENVGUARD_TEST_TAG}
+    environment:
+      DATABASE_URL: ${DATABASE_URL}
+      SECRET: ${ENVGUARD_TEST_SECRET:?SECRET is needed}
+      LEVEL: ${ENVGUARD_TEST_LEVEL:-info}
+      ENVGUARD_TEST_PASS:
+  worker:
+    image: api:${ENVGUARD_TEST_TAG}
+`))
+	if err != nil {
+		t.Fatalf("Failed to parse compose file: %v", err)
+	}
+	file.Path = "docker-compose.yml"
+
+	envEntries, err := parser.ParseEntries([]byte("DATABASE_URL=postgres://${DB_HOST}/app\nDB_HOST=db\nUNUSED=1\n"))
+	if err != nil {
+		t.Fatalf("Failed to parse entries: %v", err)
+	}
+	exampleEntries, err := parser.ParseEntries([]byte("DATABASE_URL=\nDB_HOST=\nUNUSED=\nDECLARED_ONLY=\n"))
+	if err != nil {
+		t.Fatalf("Failed to parse entries: %v", err)
+	}
+
+	issues := CheckCompose(file, ".env", envEntries, ".env.example", exampleEntries)
+
+	codes := map[string]Issue{}
+	for _, issue := range issues {
+		codes[issue.Code+":"+issue.Key] = issue
+	}
+
+	tag, ok := codes[CodeComposeUndefined+":ENVGUARD_TEST_TAG"]
+	if !ok || tag.Severity != SeverityError {
+		t.Fatalf("Expected an undefined error for ENVGUARD_TEST_TAG, got %+v", issues)
+	}
+	if tag.String() != "docker-compose.yml:3: ENVGUARD_TEST_TAG is used by services api, worker but not defined in .env" {
+		t.Errorf("Unexpected message: %s", tag)
+	}
+
+	secret := codes[CodeComposeUndefined+":ENVGUARD_TEST_SECRET"]
+	if secret.Message != "ENVGUARD_TEST_SECRET is used by service api but not defined in .env: SECRET is needed" {
+		t.Errorf("Expected the :? message to be included, got %+v", secret)
+	}
+
+	if pass := codes[CodeComposeUndefined+":ENVGUARD_TEST_PASS"]; pass.Severity != SeverityWarning {
+		t.Errorf("Expected a pass-through warning, got %+v", issues)
+	}
+	if _, ok := codes[CodeComposeUndefined+":ENVGUARD_TEST_LEVEL"]; ok {
+		t.Error("A reference with a default should not be reported as undefined")
+	}
+	if _, ok := codes[CodeComposeUndeclared+":ENVGUARD_TEST_LEVEL"]; !ok {
+		t.Error("Expected ENVGUARD_TEST_LEVEL to be reported as undeclared")
+	}
+
+	if unused := codes[CodeComposeUnused+":UNUSED"]; unused.File != ".env" || unused.Line != 3 {
+		t.Errorf("Expected UNUSED to be reported at .env:3, got %+v", issues)
+	}
+	if unused := codes[CodeComposeUnused+":DECLARED_ONLY"]; unused.File != ".env.example" || unused.Line != 4 {
+		t.Errorf("Expected DECLARED_ONLY to be reported at .env.example:4, got %+v", issues)
+	}
+	if _, ok := codes[CodeComposeUnused+":DB_HOST"]; ok {
+		t.Error("DB_HOST is used through DATABASE_URL and should not be unused")
+	}
+
+	file.EnvFiles = []compose.EnvFile{{Service: "api", Path: ".env", Required: true}}
+	for _, issue := range CheckCompose(file, ".env", envEntries, "", nil) {
+		if issue.Code == CodeComposeUnused || issue.Code == CodeComposeUndeclared {
+			t.Errorf("Unexpected issue when .env is an env_file and no example is given: %s", issue)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/crabest/envguard/internal/parser"
+)
+
+// Annotations that retire keys.
+const (
+	// AnnotationDeprecated marks a key that should no longer be set. Any
+	// arguments are shown as the deprecation message.
+	AnnotationDeprecated = "deprecated"
+	// AnnotationRenamedFrom lists the former names of a key, e.g.
+	// "@renamed-from REDIS_URL".
+	AnnotationRenamedFrom = "renamed-from"
+)
+
+// Issue codes reported by CheckDeprecations.
+const (
+	CodeDeprecated = "deprecated"
+	CodeRenamed    = "renamed"
+)
+
+// Deprecated reports whether key is @deprecated, with its message if any.
+func (s *Schema) Deprecated(key string) (string, bool) {
+	spec, ok := s.Specs[key]
+	if !ok {
+		return "", false
+	}
+	message, ok := spec.Annotations[AnnotationDeprecated]
+	return message, ok
+}
+
+// DeprecatedKeys returns every @deprecated key in schema order.
+func (s *Schema) DeprecatedKeys() []string {
+	var keys []string
+	for _, key := range s.Keys {
+		if _, ok := s.Deprecated(key); ok {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// Renames maps every former name declared with @renamed-from to the key
+// that replaced it.
+func (s *Schema) Renames() map[string]string {
+	renames := make(map[string]string)
+	for _, key := range s.Keys {
+		args, ok := s.Specs[key].Annotations[AnnotationRenamedFrom]
+		if !ok {
+			continue
+		}
+		for _, old := range strings.Split(args, ",") {
+			if old = strings.TrimSpace(old); old != "" && old != key {
+				renames[old] = key
+			}
+		}
+	}
+	return renames
+}
+
+// CheckDeprecations warns about entries of file that set deprecated keys
+// or keys that have since been renamed.
+func CheckDeprecations(file string, entries []parser.Entry, schema *Schema) []Issue {
+	renames := schema.Renames()
+
+	var issues []Issue
+	for _, entry := range entries {
+		if message, ok := schema.Deprecated(entry.Key); ok {
+			text := fmt.Sprintf("%s is deprecated", entry.Key)
+			if message != "" {
+				text += ": " + message
+			}
+			issues = append(issues, Issue{
+				Severity: SeverityWarning,
+				Code:     CodeDeprecated,
+				Key:      entry.Key,
+				File:     file,
+				Line:     entry.Line,
+				Message:  text,
+			})
+		}
+
+		if newName, ok := renames[entry.Key]; ok {
+			issues = append(issues, Issue{
+				Severity: SeverityWarning,
+				Code:     CodeRenamed,
+				Key:      entry.Key,
+				File:     file,
+				Line:     entry.Line,
+				Message:  fmt.Sprintf("%s has been renamed to %s (run envguard migrate)", entry.Key, newName),
+			})
+		}
+	}
+	return issues
+}
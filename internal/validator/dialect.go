@@ -0,0 +1,126 @@
+package validator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/crabest/envguard/internal/parser"
+)
+
+// Issue codes reported by CheckDialect.
+const (
+	CodeDialectRejected = "dialect-rejected"
+	CodeDialectMismatch = "dialect-mismatch"
+)
+
+// CheckDialect reports every assignment in content that dialect rejects or
+// reads differently from envguard, so a file that validates here is also
+// what the runtime sees. Values are never included in messages.
+func CheckDialect(file string, content []byte, dialect parser.Dialect) ([]Issue, error) {
+	expected, err := parser.ParseEntries(content)
+	if err != nil {
+		return nil, err
+	}
+
+	actual, err := parser.ReadDialect(content, dialect)
+	if err != nil {
+		return nil, err
+	}
+
+	var issues []Issue
+	read := make(map[string]parser.DialectEntry)
+	rejected := make(map[int]bool)
+	for _, entry := range actual {
+		if entry.Problem != "" {
+			issues = append(issues, Issue{
+				Severity: SeverityError,
+				Code:     CodeDialectRejected,
+				Key:      entry.Key,
+				File:     file,
+				Line:     entry.Line,
+				Message:  entry.Problem,
+			})
+			rejected[entry.Line] = true
+			continue
+		}
+		read[entry.Key] = entry
+	}
+
+	last := make(map[string]parser.Entry)
+	for _, entry := range expected {
+		last[entry.Key] = entry
+	}
+
+	for _, entry := range expected {
+		if last[entry.Key].Line != entry.Line {
+			continue
+		}
+
+		got, ok := read[entry.Key]
+		switch {
+		case !ok:
+			if !rejectedWithin(rejected, entry) {
+				issues = append(issues, Issue{
+					Severity: SeverityError,
+					Code:     CodeDialectMismatch,
+					Key:      entry.Key,
+					File:     file,
+					Line:     entry.Line,
+					Message:  fmt.Sprintf("%s does not set %s", dialect, entry.Key),
+				})
+			}
+		case got.Value != entry.Value:
+			issues = append(issues, Issue{
+				Severity: SeverityError,
+				Code:     CodeDialectMismatch,
+				Key:      entry.Key,
+				File:     file,
+				Line:     entry.Line,
+				Message:  fmt.Sprintf("%s reads %s differently: it %s", dialect, entry.Key, describeDifference(entry.Value, got.Value)),
+			})
+		}
+	}
+
+	sortIssues(issues)
+	return issues, nil
+}
+
+func rejectedWithin(rejected map[int]bool, entry parser.Entry) bool {
+	for line := entry.Line; line <= entry.EndLine; line++ {
+		if rejected[line] {
+			return true
+		}
+	}
+	return false
+}
+
+// describeDifference explains how got differs from expected without
+// revealing either value.
+func describeDifference(expected, got string) string {
+	for _, quote := range []string{`"`, "'", "`"} {
+		if strings.HasPrefix(got, quote) && strings.HasSuffix(got, quote) && len(got) >= 2 {
+			return "keeps the surrounding quotes"
+		}
+	}
+
+	switch {
+	case strings.Contains(got, "$") && !strings.Contains(expected, "$"):
+		return "does not expand variable references"
+	case strings.Contains(expected, "$") && !strings.Contains(got, "$"):
+		return "expands variable references"
+	case strings.HasPrefix(got, expected) && strings.Contains(got[len(expected):], "#"):
+		return "keeps the inline comment"
+	case strings.TrimSpace(got) == strings.TrimSpace(expected):
+		return "handles surrounding whitespace differently"
+	case strings.ReplaceAll(got, `\`, "") == strings.ReplaceAll(expected, `\`, ""):
+		return "handles backslash escapes differently"
+	}
+	return "gets a different value"
+}
+
+func sortIssues(issues []Issue) {
+	sort.SliceStable(issues, func(a, b int) bool {
+		return issues[a].Line < issues[b].Line
+	})
+}
@@ -0,0 +1,57 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/crabest/envguard/internal/parser"
+)
+
+func TestCheckDialectDocker(t *testing.T) {
+	content := []byte(`PORT=3000
+NAME="my app"
+INLINE=abc # note
+export TOKEN=x
+CERT="a
+b"
+`)
+
+	issues, err := CheckDialect(".env", content, parser.DialectDocker)
+	if err != nil {
+		t.Fatalf("Failed to check dialect: %v", err)
+	}
+
+	messages := make([]string, len(issues))
+	for i, issue := range issues {
+		messages[i] = issue.String()
+	}
+	got := strings.Join(messages, "\n")
+
+	for _, expected := range []string{
+		".env:2: docker reads NAME differently: it keeps the surrounding quotes",
+		".env:3: docker reads INLINE differently: it keeps the inline comment",
+		`.env:4: docker rejects "export TOKEN" as a variable name`,
+		".env:5: docker reads CERT differently",
+		`.env:6: docker rejects "b\"" as a variable name`,
+	} {
+		if !strings.Contains(got, expected) {
+			t.Errorf("Expected issue %q, got:\n%s", expected, got)
+		}
+	}
+	if strings.Contains(got, "PORT") {
+		t.Errorf("Expected PORT to be read identically, got:\n%s", got)
+	}
+	if strings.Contains(got, "abc") || strings.Contains(got, "my app") {
+		t.Errorf("Expected messages not to reveal values, got:\n%s", got)
+	}
+}
+
+func TestCheckDialectGodotenv(t *testing.T) {
+	issues, err := CheckDialect(".env", []byte("A=\"quoted\"\nB=${A}\n"), parser.DialectGodotenv)
+	if err != nil {
+		t.Fatalf("Failed to check dialect: %v", err)
+	}
+	if len(issues) != 0 {
+		t.Errorf("Expected no issues against envguard's own dialect, got %+v", issues)
+	}
+}
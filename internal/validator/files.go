@@ -0,0 +1,82 @@
+package validator
+
+import (
+	"fmt"
+
+	"github.com/crabest/envguard/internal/parser"
+)
+
+// FileCheck describes one validation of an env file against its example,
+// as run by the CLI and by the Go library.
+type FileCheck struct {
+	EnvFile     string
+	ExampleFile string
+
+	// Interpolation is how ${VAR} references in both files are resolved.
+	Interpolation parser.Interpolation
+
+	// Environment is the environment @required-in rules are checked
+	// against, or empty when unknown.
+	Environment string
+
+	// Lookup, when set, supplies values for declared keys the env file
+	// does not set, such as variables already in the process environment.
+	// Keys it finds are not reported as missing.
+	Lookup func(key string) (string, bool)
+}
+
+// ValidateFile runs every check envguard applies to an env file: the
+// references in both files, the example's schema rules, its deprecations
+// and the @type and @enum of every value. It returns the result together
+// with the variables parsed from the env file. A broken reference is
+// reported as an issue rather than an error; the variables are then
+// returned unexpanded.
+func ValidateFile(check FileCheck) (ValidationResult, parser.EnvVars, error) {
+	envEntries, err := parser.ParseEnvFileEntries(check.EnvFile)
+	if err != nil {
+		return ValidationResult{}, nil, fmt.Errorf("failed to parse %s: %w", check.EnvFile, err)
+	}
+
+	exampleEntries, err := parser.ParseEnvFileEntries(check.ExampleFile)
+	if err != nil {
+		return ValidationResult{}, nil, fmt.Errorf("failed to parse %s: %w", check.ExampleFile, err)
+	}
+	schema := NewSchema(exampleEntries)
+	schema.File = check.ExampleFile
+
+	issues := CheckReferences(check.EnvFile, envEntries, check.Interpolation)
+	issues = append(issues, CheckReferences(check.ExampleFile, exampleEntries, check.Interpolation)...)
+
+	envVars, err := parser.ParseEnvFileWith(check.EnvFile, check.Interpolation)
+	if err != nil && HasErrors(issues) {
+		// The reference errors explain why expansion failed; report them
+		// instead of stopping at the first one.
+		envVars, err = parser.ParseEnvFile(check.EnvFile)
+	}
+	if err != nil {
+		return ValidationResult{}, nil, fmt.Errorf("failed to parse %s: %w", check.EnvFile, err)
+	}
+
+	present := envVars
+	if check.Lookup != nil {
+		present = make(parser.EnvVars, len(envVars))
+		for key, value := range envVars {
+			present[key] = value
+		}
+		for _, key := range schema.Keys {
+			if _, ok := present[key]; ok {
+				continue
+			}
+			if value, ok := check.Lookup(key); ok {
+				present[key] = value
+			}
+		}
+	}
+
+	result := ValidateWithSchema(present, schema, check.Environment)
+	result.Issues = append(result.Issues, issues...)
+	result.Issues = append(result.Issues, CheckDeprecations(check.EnvFile, envEntries, schema)...)
+	result.Issues = append(result.Issues, CheckValues(check.EnvFile, envEntries, schema)...)
+
+	return result, envVars, nil
+}
@@ -0,0 +1,58 @@
+package validator
+
+import (
+	"fmt"
+
+	"github.com/fatih/color"
+)
+
+// Severity classifies an Issue.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Issue is a problem found at a specific position in a file.
+type Issue struct {
+	Severity Severity `json:"severity"`
+	Code     string   `json:"code"`
+	Key      string   `json:"key"`
+	File     string   `json:"file"`
+	Line     int      `json:"line"`
+	Message  string   `json:"message"`
+}
+
+func (i Issue) String() string {
+	return fmt.Sprintf("%s:%d: %s", i.File, i.Line, i.Message)
+}
+
+// HasErrors reports whether any issue has error severity.
+func HasErrors(issues []Issue) bool {
+	for _, issue := range issues {
+		if issue.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// PrintIssues prints issues grouped as errors then warnings.
+func PrintIssues(issues []Issue) {
+	if len(issues) == 0 {
+		return
+	}
+
+	for _, issue := range issues {
+		if issue.Severity == SeverityError {
+			fmt.Printf("   %s %s\n", color.RedString("❌"), issue)
+		}
+	}
+	for _, issue := range issues {
+		if issue.Severity == SeverityWarning {
+			fmt.Printf("   %s %s\n", color.YellowString("⚠️ "), issue)
+		}
+	}
+	fmt.Println()
+}
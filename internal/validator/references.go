@@ -0,0 +1,155 @@
+package validator
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/crabest/envguard/internal/parser"
+)
+
+// Issue codes reported by CheckReferences.
+const (
+	CodeUndefinedReference = "undefined-reference"
+	CodeForwardReference   = "forward-reference"
+	CodeReferenceCycle     = "reference-cycle"
+)
+
+// CheckReferences reports interpolation problems in entries read from file:
+// references to variables the file does not define, references to keys
+// defined further down (which resolve to empty), and reference cycles.
+// Compose falls back to the process environment, so in that mode a
+// variable set there counts as defined; godotenv only expands keys from
+// the same file.
+func CheckReferences(file string, entries []parser.Entry, mode parser.Interpolation) []Issue {
+	if mode == parser.InterpolateNone {
+		return nil
+	}
+
+	defined := make(map[string]parser.Entry)
+	for _, entry := range entries {
+		if _, seen := defined[entry.Key]; !seen {
+			defined[entry.Key] = entry
+		}
+	}
+
+	var issues []Issue
+	graph := make(map[string][]string)
+
+	for _, entry := range entries {
+		if entry.Quote == '\'' {
+			continue
+		}
+
+		for _, ref := range parser.FindReferences(entry.RawValue, mode) {
+			target, inFile := defined[ref.Name]
+			if inFile {
+				graph[entry.Key] = append(graph[entry.Key], ref.Name)
+			}
+
+			switch {
+			case ref.Name == entry.Key:
+				// Self references are reported as cycles below.
+			case inFile && target.Line > entry.Line:
+				issues = append(issues, Issue{
+					Severity: SeverityWarning,
+					Code:     CodeForwardReference,
+					Key:      entry.Key,
+					File:     file,
+					Line:     entry.Line,
+					Message:  fmt.Sprintf("%s references %s before it is defined (line %d)", entry.Key, ref.Name, target.Line),
+				})
+			case inFile:
+			case mode == parser.InterpolateCompose && isSetInProcess(ref.Name):
+			case ref.Required():
+				message := ref.Argument
+				if message == "" {
+					message = "is required"
+				}
+				issues = append(issues, Issue{
+					Severity: SeverityError,
+					Code:     CodeUndefinedReference,
+					Key:      entry.Key,
+					File:     file,
+					Line:     entry.Line,
+					Message:  fmt.Sprintf("%s references undefined variable %s: %s", entry.Key, ref.Name, message),
+				})
+			case ref.HasFallback():
+			default:
+				issues = append(issues, Issue{
+					Severity: SeverityWarning,
+					Code:     CodeUndefinedReference,
+					Key:      entry.Key,
+					File:     file,
+					Line:     entry.Line,
+					Message:  fmt.Sprintf("%s references undefined variable %s", entry.Key, ref.Name),
+				})
+			}
+		}
+	}
+
+	for _, cycle := range findCycles(parser.EntryKeys(entries), graph) {
+		entry := defined[cycle[0]]
+		issues = append(issues, Issue{
+			Severity: SeverityError,
+			Code:     CodeReferenceCycle,
+			Key:      cycle[0],
+			File:     file,
+			Line:     entry.Line,
+			Message:  "reference cycle: " + strings.Join(cycle, " → "),
+		})
+	}
+
+	return issues
+}
+
+func isSetInProcess(name string) bool {
+	_, ok := os.LookupEnv(name)
+	return ok
+}
+
+// findCycles returns each cycle in graph once, starting at the key that
+// appears first in order and ending where it started.
+func findCycles(order []string, graph map[string][]string) [][]string {
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+
+	state := make(map[string]int)
+	var stack []string
+	var cycles [][]string
+
+	var visit func(string)
+	visit = func(key string) {
+		state[key] = visiting
+		stack = append(stack, key)
+
+		for _, next := range graph[key] {
+			switch state[next] {
+			case unvisited:
+				visit(next)
+			case visiting:
+				for i, k := range stack {
+					if k == next {
+						cycle := append(append([]string{}, stack[i:]...), next)
+						cycles = append(cycles, cycle)
+						break
+					}
+				}
+			}
+		}
+
+		stack = stack[:len(stack)-1]
+		state[key] = done
+	}
+
+	for _, key := range order {
+		if state[key] == unvisited {
+			visit(key)
+		}
+	}
+
+	return cycles
+}
@@ -0,0 +1,91 @@
+package validator
+
+import (
+	"os"
+	"testing"
+
+	"github.com/crabest/envguard/internal/parser"
+)
+
+func TestCheckReferences(t *testing.T) {
+	content := `DB_USER=app
+DATABASE_URL=postgresql://${DB_USER}:${ENVGUARD_TEST_UNDEFINED}@${DB_HOST}/app
+DB_HOST=localhost
+A=${B}
+B=${A}
+LITERAL='${NOT_CHECKED}'
+`
+	entries, err := parser.ParseEntries([]byte(content))
+	if err != nil {
+		t.Fatalf("Failed to parse entries: %v", err)
+	}
+
+	issues := CheckReferences(".env", entries, parser.InterpolateGodotenv)
+
+	codes := map[string]Issue{}
+	for _, issue := range issues {
+		codes[issue.Code+":"+issue.Key] = issue
+	}
+
+	undefined, ok := codes[CodeUndefinedReference+":DATABASE_URL"]
+	if !ok || undefined.Severity != SeverityWarning || undefined.Line != 2 {
+		t.Errorf("Expected undefined reference warning on line 2, got %+v", issues)
+	}
+
+	if _, ok := codes[CodeForwardReference+":DATABASE_URL"]; !ok {
+		t.Errorf("Expected forward reference warning for DB_HOST, got %+v", issues)
+	}
+
+	cycle, ok := codes[CodeReferenceCycle+":A"]
+	if !ok || cycle.Severity != SeverityError {
+		t.Fatalf("Expected reference cycle error, got %+v", issues)
+	}
+	if cycle.String() != ".env:4: reference cycle: A → B → A" {
+		t.Errorf("Unexpected cycle message: %s", cycle)
+	}
+
+	if !HasErrors(issues) {
+		t.Error("Expected issues to contain errors")
+	}
+}
+
+func TestCheckReferencesCompose(t *testing.T) {
+	content := "A=${ENVGUARD_TEST_UNDEFINED:-default}\nB=${ENVGUARD_TEST_UNDEFINED:?B needs it}\n"
+	entries, err := parser.ParseEntries([]byte(content))
+	if err != nil {
+		t.Fatalf("Failed to parse entries: %v", err)
+	}
+
+	issues := CheckReferences(".env", entries, parser.InterpolateCompose)
+	if len(issues) != 1 {
+		t.Fatalf("Expected 1 issue, got %+v", issues)
+	}
+	if issues[0].Severity != SeverityError || issues[0].Line != 2 {
+		t.Errorf("Expected required reference error on line 2, got %+v", issues[0])
+	}
+
+	if issues := CheckReferences(".env", entries, parser.InterpolateNone); len(issues) != 0 {
+		t.Errorf("Expected no issues without interpolation, got %+v", issues)
+	}
+}
+
+func TestCheckReferencesProcessEnvironment(t *testing.T) {
+	os.Setenv("ENVGUARD_TEST_PROCESS", "set")
+	defer os.Unsetenv("ENVGUARD_TEST_PROCESS")
+
+	entries, err := parser.ParseEntries([]byte("DATA_DIR=${ENVGUARD_TEST_PROCESS}/data\n"))
+	if err != nil {
+		t.Fatalf("Failed to parse entries: %v", err)
+	}
+
+	// godotenv only expands keys from the same file, so the reference is
+	// empty however the process is started
+	issues := CheckReferences(".env", entries, parser.InterpolateGodotenv)
+	if len(issues) != 1 || issues[0].Code != CodeUndefinedReference {
+		t.Errorf("Expected an undefined reference warning in godotenv mode, got %+v", issues)
+	}
+
+	if issues := CheckReferences(".env", entries, parser.InterpolateCompose); len(issues) != 0 {
+		t.Errorf("Expected compose to resolve the process variable, got %+v", issues)
+	}
+}
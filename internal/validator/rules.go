@@ -0,0 +1,340 @@
+package validator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/crabest/envguard/internal/parser"
+)
+
+// Rule annotations that make a key's requirement depend on other keys.
+const (
+	// AnnotationRequiredIf makes a key required only when a condition on
+	// another key holds: "KEY" (set and non-empty), "KEY=value",
+	// "KEY=a|b" (any of the values) or "KEY!=value".
+	AnnotationRequiredIf = "required-if"
+	// AnnotationRequiresOneOf groups the annotated key with the listed keys;
+	// at least one key of the group must be set.
+	AnnotationRequiresOneOf = "requires-one-of"
+	// AnnotationOptional marks a key that never has to be set.
+	AnnotationOptional = "optional"
+	// AnnotationRequiredIn limits the requirement to the listed
+	// environments, e.g. "@required-in production,staging".
+	AnnotationRequiredIn = "required-in"
+)
+
+// CodeInvalidRule marks a rule annotation that cannot be parsed.
+const CodeInvalidRule = "invalid-rule"
+
+// Condition is a parsed @required-if expression.
+type Condition struct {
+	Key    string
+	Values []string
+	Negate bool
+}
+
+// ParseCondition parses a @required-if expression.
+func ParseCondition(expr string) (Condition, error) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return Condition{}, fmt.Errorf("empty condition")
+	}
+
+	if idx := strings.Index(expr, "!="); idx >= 0 {
+		return newCondition(expr[:idx], expr[idx+2:], true)
+	}
+	if idx := strings.Index(expr, "="); idx >= 0 {
+		return newCondition(expr[:idx], expr[idx+1:], false)
+	}
+	if strings.ContainsAny(expr, " \t|") {
+		return Condition{}, fmt.Errorf("invalid condition '%s'", expr)
+	}
+	return Condition{Key: expr}, nil
+}
+
+func newCondition(key, values string, negate bool) (Condition, error) {
+	key = strings.TrimSpace(key)
+	if key == "" || strings.ContainsAny(key, " \t") {
+		return Condition{}, fmt.Errorf("invalid condition key '%s'", key)
+	}
+
+	condition := Condition{Key: key, Negate: negate}
+	for _, value := range strings.Split(values, "|") {
+		condition.Values = append(condition.Values, strings.TrimSpace(value))
+	}
+	return condition, nil
+}
+
+// Holds evaluates the condition against envVars.
+func (c Condition) Holds(envVars parser.EnvVars) bool {
+	value, set := envVars[c.Key]
+	if c.Values == nil {
+		return set && value != ""
+	}
+
+	matches := false
+	for _, want := range c.Values {
+		if set && value == want {
+			matches = true
+			break
+		}
+	}
+	return matches != c.Negate
+}
+
+func (c Condition) String() string {
+	if c.Values == nil {
+		return c.Key + " is set"
+	}
+	op := "="
+	if c.Negate {
+		op = "!="
+	}
+	return c.Key + op + strings.Join(c.Values, "|")
+}
+
+// OneOf returns the @requires-one-of group for key, including key itself,
+// or nil when key has no such rule.
+func (s *Schema) OneOf(key string) []string {
+	spec, ok := s.Specs[key]
+	if !ok {
+		return nil
+	}
+	args, ok := spec.Annotations[AnnotationRequiresOneOf]
+	if !ok {
+		return nil
+	}
+
+	group := []string{key}
+	for _, name := range strings.Split(args, ",") {
+		if name = strings.TrimSpace(name); name != "" && name != key {
+			group = append(group, name)
+		}
+	}
+	return group
+}
+
+// inOneOfGroup reports whether key is listed in another key's
+// @requires-one-of group without carrying the rule itself.
+func (s *Schema) inOneOfGroup(key string) bool {
+	if s.Has(key, AnnotationRequiresOneOf) {
+		return false
+	}
+	for _, other := range s.Keys {
+		if other == key {
+			continue
+		}
+		for _, member := range s.OneOf(other) {
+			if member == key {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// RequiredIn returns the environments listed in key's @required-in rule,
+// or nil when it has none.
+func (s *Schema) RequiredIn(key string) []string {
+	spec, ok := s.Specs[key]
+	if !ok {
+		return nil
+	}
+	args, ok := spec.Annotations[AnnotationRequiredIn]
+	if !ok {
+		return nil
+	}
+
+	envs := []string{}
+	for _, env := range strings.Split(args, ",") {
+		if env = strings.TrimSpace(env); env != "" {
+			envs = append(envs, env)
+		}
+	}
+	return envs
+}
+
+// Optional reports whether key may be absent in at least some
+// environment, i.e. it carries any rule that relaxes the default
+// requirement.
+func (s *Schema) Optional(key string) bool {
+	for _, annotation := range []string{AnnotationOptional, AnnotationDeprecated, AnnotationRequiredIn, AnnotationRequiredIf, AnnotationRequiresOneOf} {
+		if s.Has(key, annotation) {
+			return true
+		}
+	}
+	return s.inOneOfGroup(key)
+}
+
+// Requirement reports whether key must be present in envVars when
+// validating environment, which may be empty when unknown. Keys limited
+// with @required-in are only required once the environment is known to be
+// one of those listed. When the requirement comes from a rule, reason
+// explains it for display.
+func (s *Schema) Requirement(key string, envVars parser.EnvVars, environment string) (required bool, reason string, err error) {
+	spec, ok := s.Specs[key]
+	if !ok {
+		return false, "", nil
+	}
+
+	if s.Has(key, AnnotationOptional) || s.Has(key, AnnotationDeprecated) {
+		return false, "", nil
+	}
+
+	if envs := s.RequiredIn(key); envs != nil {
+		if !containsString(envs, environment) {
+			return false, "", nil
+		}
+		reason = "required in " + environment
+	}
+
+	if expr, ok := spec.Annotations[AnnotationRequiredIf]; ok {
+		condition, err := ParseCondition(expr)
+		if err != nil {
+			return false, "", fmt.Errorf("@%s on %s: %w", AnnotationRequiredIf, key, err)
+		}
+		if !condition.Holds(envVars) {
+			return false, "", nil
+		}
+		return true, joinReasons(reason, "required because "+condition.String()), nil
+	}
+
+	if s.inOneOfGroup(key) {
+		// The group is reported once, on the key carrying the rule
+		return false, "", nil
+	}
+
+	if group := s.OneOf(key); group != nil {
+		for _, name := range group {
+			if envVars[name] != "" {
+				return false, "", nil
+			}
+		}
+		return true, joinReasons(reason, "set one of "+strings.Join(group, ", ")), nil
+	}
+
+	return true, reason, nil
+}
+
+// formerNameIn returns the first former name of key, in sorted order, that
+// envVars still sets.
+func formerNameIn(renames map[string]string, key string, envVars parser.EnvVars) string {
+	var olds []string
+	for old, newName := range renames {
+		if newName == key && parser.HasVariable(envVars, old) {
+			olds = append(olds, old)
+		}
+	}
+	sort.Strings(olds)
+	if len(olds) == 0 {
+		return ""
+	}
+	return olds[0]
+}
+
+func joinReasons(first, second string) string {
+	if first == "" {
+		return second
+	}
+	return first + ", " + second
+}
+
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckRules reports rule annotations in the schema that cannot be
+// parsed, whether or not the keys they are on are set: a malformed
+// @required-if condition, or a @required-in or @requires-one-of without
+// names.
+func (s *Schema) CheckRules() []Issue {
+	var issues []Issue
+	invalid := func(key, annotation string, err error) {
+		issues = append(issues, Issue{
+			Severity: SeverityError,
+			Code:     CodeInvalidRule,
+			Key:      key,
+			File:     s.File,
+			Line:     s.Specs[key].Line,
+			Message:  fmt.Sprintf("@%s on %s: %v", annotation, key, err),
+		})
+	}
+
+	for _, key := range s.Keys {
+		spec := s.Specs[key]
+		if expr, ok := spec.Annotations[AnnotationRequiredIf]; ok {
+			if _, err := ParseCondition(expr); err != nil {
+				invalid(key, AnnotationRequiredIf, err)
+			}
+		}
+		if envs := s.RequiredIn(key); envs != nil && len(envs) == 0 {
+			invalid(key, AnnotationRequiredIn, fmt.Errorf("no environments listed"))
+		}
+		if group := s.OneOf(key); group != nil && len(group) < 2 {
+			invalid(key, AnnotationRequiresOneOf, fmt.Errorf("no other keys listed"))
+		}
+	}
+	return issues
+}
+
+// ValidateWithSchema is ValidateEnvFiles with the schema's rules applied
+// for environment (empty when unknown): absent keys that are @optional,
+// not @required-in this environment, whose @required-if condition does
+// not hold, or whose @requires-one-of group is satisfied by another key
+// are listed in OptionalVars instead of MissingVars. Former names declared
+// with @renamed-from are not counted as extra. Malformed rules are
+// reported as issues by CheckRules, for every key whether set or not; a
+// missing key with a malformed rule is treated as required.
+func ValidateWithSchema(envVars parser.EnvVars, schema *Schema, environment string) ValidationResult {
+	exampleVars := make(parser.EnvVars, len(schema.Keys))
+	for _, key := range schema.Keys {
+		exampleVars[key] = schema.Specs[key].Example
+	}
+
+	result := ValidateEnvFiles(envVars, exampleVars)
+	result.Environment = environment
+
+	// Former names are reported by CheckDeprecations, not as extras
+	renames := schema.Renames()
+	extra := []string{}
+	for _, key := range result.ExtraVars {
+		if _, renamed := renames[key]; !renamed {
+			extra = append(extra, key)
+		}
+	}
+	result.ExtraVars = extra
+
+	result.Issues = append(result.Issues, schema.CheckRules()...)
+
+	missing := []string{}
+	for _, key := range result.MissingVars {
+		required, reason, err := schema.Requirement(key, envVars, environment)
+		if err != nil {
+			missing = append(missing, key)
+			continue
+		}
+		if !required {
+			result.OptionalVars = append(result.OptionalVars, key)
+			continue
+		}
+		missing = append(missing, key)
+		if old := formerNameIn(renames, key, envVars); old != "" {
+			reason = joinReasons(reason, "still set as "+old+", run envguard migrate")
+		}
+		if reason != "" {
+			if result.Reasons == nil {
+				result.Reasons = make(map[string]string)
+			}
+			result.Reasons[key] = reason
+		}
+	}
+	result.MissingVars = missing
+
+	return result
+}
@@ -0,0 +1,195 @@
+package validator
+
+import (
+	"testing"
+
+	"github.com/crabest/envguard/internal/parser"
+)
+
+func newTestSchema(t *testing.T, content string) *Schema {
+	t.Helper()
+
+	entries, err := parser.ParseEntries([]byte(content))
+	if err != nil {
+		t.Fatalf("Failed to parse entries: %v", err)
+	}
+	return NewSchema(entries)
+}
+
+func TestValidateWithSchemaRequiredIf(t *testing.T) {
+	schema := newTestSchema(t, `EMAIL_PROVIDER=smtp
+# @required-if EMAIL_PROVIDER=smtp
+SMTP_PASSWORD=
+CACHE=memory
+# @required-if CACHE=redis|valkey
+REDIS_URL=
+# @required-if CACHE!=memory
+CACHE_TTL=
+`)
+
+	result := ValidateWithSchema(parser.EnvVars{"EMAIL_PROVIDER": "ses", "CACHE": "memory"}, schema, "")
+	if len(result.MissingVars) != 0 {
+		t.Errorf("Expected no missing variables when conditions do not hold, got %v", result.MissingVars)
+	}
+
+	result = ValidateWithSchema(parser.EnvVars{"EMAIL_PROVIDER": "smtp", "CACHE": "valkey"}, schema, "")
+	expected := []string{"CACHE_TTL", "REDIS_URL", "SMTP_PASSWORD"}
+	if len(result.MissingVars) != len(expected) {
+		t.Fatalf("Expected missing %v, got %v", expected, result.MissingVars)
+	}
+	for i, key := range expected {
+		if result.MissingVars[i] != key {
+			t.Errorf("Expected missing %v, got %v", expected, result.MissingVars)
+		}
+	}
+	if result.Reasons["SMTP_PASSWORD"] != "required because EMAIL_PROVIDER=smtp" {
+		t.Errorf("Unexpected reason: %q", result.Reasons["SMTP_PASSWORD"])
+	}
+}
+
+func TestValidateWithSchemaRequiresOneOf(t *testing.T) {
+	schema := newTestSchema(t, `# @requires-one-of DB_HOST
+DATABASE_URL=
+DB_HOST=
+`)
+
+	result := ValidateWithSchema(parser.EnvVars{"DB_HOST": "localhost"}, schema, "")
+	if len(result.MissingVars) != 0 {
+		t.Errorf("Expected group to be satisfied by DB_HOST, got %v", result.MissingVars)
+	}
+
+	result = ValidateWithSchema(parser.EnvVars{"DATABASE_URL": "postgres://"}, schema, "")
+	if len(result.MissingVars) != 0 {
+		t.Errorf("Expected group to be satisfied by DATABASE_URL, got %v", result.MissingVars)
+	}
+
+	result = ValidateWithSchema(parser.EnvVars{}, schema, "")
+	if len(result.MissingVars) != 1 || result.MissingVars[0] != "DATABASE_URL" {
+		t.Errorf("Expected the group to be reported once, got %v", result.MissingVars)
+	}
+	if result.Reasons["DATABASE_URL"] != "set one of DATABASE_URL, DB_HOST" {
+		t.Errorf("Unexpected reason: %q", result.Reasons["DATABASE_URL"])
+	}
+}
+
+func TestValidateWithSchemaInvalidRule(t *testing.T) {
+	schema := newTestSchema(t, "# @required-if\nSECRET=\n")
+
+	result := ValidateWithSchema(parser.EnvVars{}, schema, "")
+	if !HasErrors(result.Issues) || result.Issues[0].Code != CodeInvalidRule || result.Issues[0].Line != 2 {
+		t.Errorf("Expected invalid rule error on line 2, got %+v", result.Issues)
+	}
+
+	// Rules are checked even when the key they are on is set
+	result = ValidateWithSchema(parser.EnvVars{"SECRET": "x"}, schema, "")
+	if len(result.Issues) != 1 || result.Issues[0].Message != "@required-if on SECRET: empty condition" {
+		t.Errorf("Expected the rule to be reported for a present key, got %+v", result.Issues)
+	}
+}
+
+func TestCheckRules(t *testing.T) {
+	schema := newTestSchema(t, `# @optional
+# @required-if MODE is on
+A=
+# @required-in
+B=
+# @requires-one-of
+C=
+# @required-if MODE=on
+# @required-in production
+D=
+`)
+
+	issues := schema.CheckRules()
+	if len(issues) != 3 {
+		t.Fatalf("Expected 3 invalid rules, got %+v", issues)
+	}
+	for i, key := range []string{"A", "B", "C"} {
+		if issues[i].Key != key || issues[i].Code != CodeInvalidRule {
+			t.Errorf("Expected invalid rule on %s, got %+v", key, issues[i])
+		}
+	}
+	if issues[1].Message != "@required-in on B: no environments listed" {
+		t.Errorf("Unexpected message: %s", issues[1].Message)
+	}
+}
+
+func TestValidateWithSchemaOptionalAndRequiredIn(t *testing.T) {
+	schema := newTestSchema(t, `# @optional
+SENTRY_DSN=
+# @required-in production,staging
+STRIPE_KEY=
+# @required-in production
+# @required-if BILLING=on
+INVOICE_WEBHOOK=
+APP_NAME=
+`)
+
+	result := ValidateWithSchema(parser.EnvVars{"APP_NAME": "api", "BILLING": "on"}, schema, "development")
+	if len(result.MissingVars) != 0 {
+		t.Errorf("Expected nothing missing in development, got %v", result.MissingVars)
+	}
+	if len(result.OptionalVars) != 3 {
+		t.Errorf("Expected 3 optional variables, got %v", result.OptionalVars)
+	}
+
+	result = ValidateWithSchema(parser.EnvVars{"APP_NAME": "api", "BILLING": "on"}, schema, "production")
+	if len(result.MissingVars) != 2 || result.MissingVars[0] != "INVOICE_WEBHOOK" || result.MissingVars[1] != "STRIPE_KEY" {
+		t.Fatalf("Expected INVOICE_WEBHOOK and STRIPE_KEY missing in production, got %v", result.MissingVars)
+	}
+	if result.Reasons["STRIPE_KEY"] != "required in production" {
+		t.Errorf("Unexpected reason: %q", result.Reasons["STRIPE_KEY"])
+	}
+	if result.Reasons["INVOICE_WEBHOOK"] != "required in production, required because BILLING=on" {
+		t.Errorf("Unexpected reason: %q", result.Reasons["INVOICE_WEBHOOK"])
+	}
+
+	result = ValidateWithSchema(parser.EnvVars{"APP_NAME": "api"}, schema, "")
+	if len(result.MissingVars) != 0 {
+		t.Errorf("Expected @required-in keys to be optional for an unknown environment, got %v", result.MissingVars)
+	}
+
+	if !schema.Optional("SENTRY_DSN") || !schema.Optional("STRIPE_KEY") || schema.Optional("APP_NAME") {
+		t.Error("Unexpected Optional classification")
+	}
+}
+
+func TestDeprecationsAndRenames(t *testing.T) {
+	schema := newTestSchema(t, `# @renamed-from REDIS_URL, CACHE_HOST
+CACHE_URL=
+# @deprecated use FEATURE_FLAGS instead
+LEGACY_FLAG=
+`)
+
+	renames := schema.Renames()
+	if renames["REDIS_URL"] != "CACHE_URL" || renames["CACHE_HOST"] != "CACHE_URL" {
+		t.Errorf("Unexpected renames: %v", renames)
+	}
+
+	envVars := parser.EnvVars{"REDIS_URL": "redis://", "LEGACY_FLAG": "1"}
+	result := ValidateWithSchema(envVars, schema, "")
+	if len(result.ExtraVars) != 0 {
+		t.Errorf("Expected former names not to be extra, got %v", result.ExtraVars)
+	}
+	if result.Reasons["CACHE_URL"] != "still set as REDIS_URL, run envguard migrate" {
+		t.Errorf("Unexpected reason: %q", result.Reasons["CACHE_URL"])
+	}
+
+	entries, err := parser.ParseEntries([]byte("REDIS_URL=redis://\nLEGACY_FLAG=1\n"))
+	if err != nil {
+		t.Fatalf("Failed to parse entries: %v", err)
+	}
+	issues := CheckDeprecations(".env", entries, schema)
+	if len(issues) != 2 {
+		t.Fatalf("Expected 2 issues, got %+v", issues)
+	}
+	if issues[0].String() != ".env:1: REDIS_URL has been renamed to CACHE_URL (run envguard migrate)" {
+		t.Errorf("Unexpected rename issue: %s", issues[0])
+	}
+	if issues[1].String() != ".env:2: LEGACY_FLAG is deprecated: use FEATURE_FLAGS instead" {
+		t.Errorf("Unexpected deprecation issue: %s", issues[1])
+	}
+	if HasErrors(issues) {
+		t.Error("Expected deprecations to be warnings")
+	}
+}
@@ -0,0 +1,156 @@
+package validator
+
+import (
+	"os"
+	"strings"
+
+	"github.com/crabest/envguard/internal/parser"
+)
+
+// Schema describes the keys declared in a .env.example file together with
+// the @annotations found in their comments.
+type Schema struct {
+	File  string
+	Keys  []string
+	Specs map[string]*KeySpec
+}
+
+// KeySpec is the schema entry for a single key.
+type KeySpec struct {
+	Key         string
+	Example     string
+	Description []string
+	Annotations map[string]string
+	Line        int
+}
+
+// Annotation names understood in .env.example comments.
+const (
+	AnnotationEnvSpecific = "env-specific"
+	AnnotationType        = "type"
+	AnnotationEnum        = "enum"
+)
+
+// Value types accepted by the @type annotation.
+const (
+	TypeString   = "string"
+	TypeInt      = "int"
+	TypeFloat    = "float"
+	TypeBool     = "bool"
+	TypeURL      = "url"
+	TypeDuration = "duration"
+	TypeList     = "list"
+)
+
+// ValueTypes lists every type accepted by @type.
+var ValueTypes = []string{TypeString, TypeInt, TypeFloat, TypeBool, TypeURL, TypeDuration, TypeList}
+
+// LoadSchema reads a schema from an example file. A missing file yields an
+// empty schema so callers can treat "no example" as "no rules".
+func LoadSchema(exampleFile string) (*Schema, error) {
+	entries, err := parser.ParseEnvFileEntries(exampleFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return NewSchema(nil), nil
+		}
+		return nil, err
+	}
+	schema := NewSchema(entries)
+	schema.File = exampleFile
+	return schema, nil
+}
+
+// NewSchema builds a schema from parsed example entries.
+func NewSchema(entries []parser.Entry) *Schema {
+	schema := &Schema{Specs: make(map[string]*KeySpec)}
+
+	for _, entry := range entries {
+		spec := &KeySpec{
+			Key:         entry.Key,
+			Example:     entry.Value,
+			Annotations: make(map[string]string),
+			Line:        entry.Line,
+		}
+
+		comments := entry.Comments
+		if entry.Inline != "" {
+			comments = append(append([]string{}, comments...), entry.Inline)
+		}
+
+		for _, comment := range comments {
+			if name, args, ok := parseAnnotation(comment); ok {
+				spec.Annotations[name] = args
+				continue
+			}
+			spec.Description = append(spec.Description, comment)
+		}
+
+		if _, seen := schema.Specs[entry.Key]; !seen {
+			schema.Keys = append(schema.Keys, entry.Key)
+		}
+		schema.Specs[entry.Key] = spec
+	}
+
+	return schema
+}
+
+// Has reports whether key carries the named annotation.
+func (s *Schema) Has(key, annotation string) bool {
+	spec, ok := s.Specs[key]
+	if !ok {
+		return false
+	}
+	_, ok = spec.Annotations[annotation]
+	return ok
+}
+
+// Type returns the @type of key, defaulting to string.
+func (s *Schema) Type(key string) string {
+	if spec, ok := s.Specs[key]; ok {
+		if t := strings.ToLower(spec.Annotations[AnnotationType]); t != "" {
+			return t
+		}
+	}
+	return TypeString
+}
+
+// Enum returns the allowed values declared with @enum, if any.
+func (s *Schema) Enum(key string) []string {
+	spec, ok := s.Specs[key]
+	if !ok || spec.Annotations[AnnotationEnum] == "" {
+		return nil
+	}
+
+	var values []string
+	for _, value := range strings.Split(spec.Annotations[AnnotationEnum], ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// IsEnvSpecific reports whether key must never be copied between
+// environments, e.g. DATABASE_URL.
+func (s *Schema) IsEnvSpecific(key string) bool {
+	return s.Has(key, AnnotationEnvSpecific)
+}
+
+// parseAnnotation splits "@name args" into its parts.
+func parseAnnotation(comment string) (string, string, bool) {
+	if !strings.HasPrefix(comment, "@") {
+		return "", "", false
+	}
+
+	fields := strings.SplitN(strings.TrimPrefix(comment, "@"), " ", 2)
+	name := strings.TrimSpace(fields[0])
+	if name == "" {
+		return "", "", false
+	}
+
+	args := ""
+	if len(fields) == 2 {
+		args = strings.TrimSpace(fields[1])
+	}
+	return name, args, true
+}
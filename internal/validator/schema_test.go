@@ -0,0 +1,50 @@
+package validator
+
+import (
+	"testing"
+
+	"github.com/crabest/envguard/internal/parser"
+)
+
+func TestNewSchema(t *testing.T) {
+	content := `# Primary database connection
+# @env-specific
+DATABASE_URL=postgresql://localhost/app
+FEATURE_CHECKOUT=false # @env-specific
+LOG_LEVEL=info
+`
+
+	entries, err := parser.ParseEntries([]byte(content))
+	if err != nil {
+		t.Fatalf("Failed to parse entries: %v", err)
+	}
+
+	schema := NewSchema(entries)
+
+	if len(schema.Keys) != 3 {
+		t.Fatalf("Expected 3 keys, got %v", schema.Keys)
+	}
+
+	if !schema.IsEnvSpecific("DATABASE_URL") || !schema.IsEnvSpecific("FEATURE_CHECKOUT") {
+		t.Error("Expected annotated keys to be environment-specific")
+	}
+
+	if schema.IsEnvSpecific("LOG_LEVEL") || schema.IsEnvSpecific("UNKNOWN") {
+		t.Error("Expected unannotated keys not to be environment-specific")
+	}
+
+	spec := schema.Specs["DATABASE_URL"]
+	if len(spec.Description) != 1 || spec.Description[0] != "Primary database connection" {
+		t.Errorf("Unexpected description: %v", spec.Description)
+	}
+}
+
+func TestLoadSchemaMissingFile(t *testing.T) {
+	schema, err := LoadSchema("nonexistent.env.example")
+	if err != nil {
+		t.Fatalf("Expected no error for missing example, got %v", err)
+	}
+	if len(schema.Keys) != 0 {
+		t.Errorf("Expected empty schema, got %v", schema.Keys)
+	}
+}
@@ -0,0 +1,41 @@
+package validator
+
+import "testing"
+
+func TestLooksSecret(t *testing.T) {
+	secret := []string{"JWT_SECRET", "DB_PASSWORD", "GITHUB_TOKEN", "STRIPE_API_KEY", "AWS_SECRET_ACCESS_KEY", "apiKey"}
+	for _, key := range secret {
+		if !LooksSecret(key) {
+			t.Errorf("Expected %s to look secret", key)
+		}
+	}
+
+	public := []string{"PORT", "LOG_LEVEL", "STRIPE_PUBLIC_KEY", "KEY", "KEYCLOAK_URL", "PASSTHROUGH_MODE"}
+	for _, key := range public {
+		if LooksSecret(key) {
+			t.Errorf("Expected %s not to look secret", key)
+		}
+	}
+}
+
+func TestSchemaIsSecret(t *testing.T) {
+	schema := newTestSchema(t, `# @secret
+SENTRY_DSN=
+# @secret false
+CACHE_KEY=
+DATABASE_URL=
+`)
+
+	if !schema.IsSecret("SENTRY_DSN", "https://sentry.io/1") {
+		t.Error("Expected @secret to mark the key as secret")
+	}
+	if schema.IsSecret("CACHE_KEY", "v1") {
+		t.Error("Expected @secret false to override the name heuristic")
+	}
+	if !schema.IsSecret("DATABASE_URL", "postgres://app:hunter2@db/app") {
+		t.Error("Expected a URL with a password to be secret")
+	}
+	if schema.IsSecret("DATABASE_URL", "postgres://db/app") {
+		t.Error("Expected a URL without a password not to be secret")
+	}
+}
@@ -0,0 +1,78 @@
+package validator
+
+import (
+	"fmt"
+
+	"github.com/crabest/envguard/internal/parser"
+	"github.com/crabest/envguard/internal/usage"
+)
+
+// Issue codes reported by CheckUsage.
+const (
+	CodeUnusedVariable     = "unused-variable"
+	CodeUndeclaredVariable = "undeclared-variable"
+)
+
+// WellKnownVariables are set by the operating system or the platform and
+// are not expected in .env.example.
+var WellKnownVariables = []string{
+	"PATH", "HOME", "USER", "SHELL", "PWD", "TMPDIR", "TEMP", "TMP", "TERM", "LANG", "HOSTNAME", "CI",
+	// Vite's built-in import.meta.env values.
+	"MODE", "DEV", "PROD", "SSR", "BASE_URL",
+}
+
+// CheckUsage compares the keys declared in exampleFile with the reads found
+// in source code. It reports declared keys no code reads and keys read
+// without a declaration, the latter once per key at its first read. Keys
+// in ignore and WellKnownVariables are never reported as undeclared.
+func CheckUsage(exampleFile string, exampleEntries []parser.Entry, reads []usage.Read, ignore []string) []Issue {
+	declared := firstEntries(exampleEntries)
+
+	readCount := make(map[string]int)
+	var order []usage.Read
+	for _, read := range reads {
+		if readCount[read.Key] == 0 {
+			order = append(order, read)
+		}
+		readCount[read.Key]++
+	}
+
+	var issues []Issue
+	for _, entry := range exampleEntries {
+		if readCount[entry.Key] > 0 || declared[entry.Key].Line != entry.Line || containsString(ignore, entry.Key) {
+			continue
+		}
+		issues = append(issues, Issue{
+			Severity: SeverityWarning,
+			Code:     CodeUnusedVariable,
+			Key:      entry.Key,
+			File:     exampleFile,
+			Line:     entry.Line,
+			Message:  fmt.Sprintf("%s is declared but never read", entry.Key),
+		})
+	}
+
+	for _, read := range order {
+		if _, ok := declared[read.Key]; ok || containsString(ignore, read.Key) || containsString(WellKnownVariables, read.Key) {
+			continue
+		}
+
+		message := fmt.Sprintf("%s is read but not declared in %s", read.Key, exampleFile)
+		switch others := readCount[read.Key] - 1; {
+		case others == 1:
+			message += " (and 1 more read)"
+		case others > 1:
+			message += fmt.Sprintf(" (and %d more reads)", others)
+		}
+		issues = append(issues, Issue{
+			Severity: SeverityWarning,
+			Code:     CodeUndeclaredVariable,
+			Key:      read.Key,
+			File:     read.File,
+			Line:     read.Line,
+			Message:  message,
+		})
+	}
+
+	return issues
+}
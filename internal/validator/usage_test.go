@@ -0,0 +1,37 @@
+package validator
+
+import (
+	"testing"
+
+	"github.com/crabest/envguard/internal/parser"
+	"github.com/crabest/envguard/internal/usage"
+)
+
+func TestCheckUsage(t *testing.T) {
+	exampleEntries, err := parser.ParseEntries([]byte("PORT=\nWEBHOOK_SECRET=\nLEGACY=\n"))
+	if err != nil {
+		t.Fatalf("Failed to parse entries: %v", err)
+	}
+
+	reads := []usage.Read{
+		{Key: "PORT", File: "main.go", Line: 3},
+		{Key: "STRIPE_KEY", File: "billing.go", Line: 10},
+		{Key: "STRIPE_KEY", File: "checkout.go", Line: 4},
+		{Key: "HOME", File: "main.go", Line: 5},
+	}
+
+	issues := CheckUsage(".env.example", exampleEntries, reads, []string{"LEGACY"})
+	if len(issues) != 2 {
+		t.Fatalf("Expected 2 issues, got %+v", issues)
+	}
+
+	if issues[0].Code != CodeUnusedVariable || issues[0].String() != ".env.example:2: WEBHOOK_SECRET is declared but never read" {
+		t.Errorf("Unexpected unused issue: %+v", issues[0])
+	}
+	if issues[1].Code != CodeUndeclaredVariable || issues[1].String() != "billing.go:10: STRIPE_KEY is read but not declared in .env.example (and 1 more read)" {
+		t.Errorf("Unexpected undeclared issue: %+v", issues[1])
+	}
+	if HasErrors(issues) {
+		t.Error("Usage issues should be warnings")
+	}
+}
@@ -10,16 +10,29 @@ import (
 )
 
 type ValidationResult struct {
-	MissingVars []string
-	ExtraVars   []string
-	CommonVars  []string
+	MissingVars []string `json:"missing"`
+	ExtraVars   []string `json:"extra"`
+	CommonVars  []string `json:"common"`
+	Issues      []Issue  `json:"issues"`
+
+	// OptionalVars are example keys absent from the env file that the
+	// schema does not require for this environment.
+	OptionalVars []string `json:"optional"`
+
+	// Environment is the environment the rules were evaluated for, if known.
+	Environment string `json:"environment,omitempty"`
+
+	// Reasons explains why a conditionally required key is missing.
+	Reasons map[string]string `json:"reasons,omitempty"`
 }
 
 func ValidateEnvFiles(envVars, exampleVars parser.EnvVars) ValidationResult {
 	result := ValidationResult{
-		MissingVars: []string{},
-		ExtraVars:   []string{},
-		CommonVars:  []string{},
+		MissingVars:  []string{},
+		ExtraVars:    []string{},
+		CommonVars:   []string{},
+		Issues:       []Issue{},
+		OptionalVars: []string{},
 	}
 
 	exampleNames := parser.GetVariableNames(exampleVars)
@@ -63,11 +76,23 @@ func PrintResults(result ValidationResult, envFile, exampleFile string) {
 	if len(result.MissingVars) > 0 {
 		color.Yellow("⚠️  Missing variables in %s (%d):", envFile, len(result.MissingVars))
 		for _, name := range result.MissingVars {
+			if reason, ok := result.Reasons[name]; ok {
+				fmt.Printf("   • %s (%s)\n", color.YellowString(name), reason)
+				continue
+			}
 			fmt.Printf("   • %s\n", color.YellowString(name))
 		}
 		fmt.Println()
 	}
 
+	if len(result.OptionalVars) > 0 {
+		color.Blue("ℹ️  Optional variables not set%s (%d):", environmentSuffix(result.Environment), len(result.OptionalVars))
+		for _, name := range result.OptionalVars {
+			fmt.Printf("   • %s\n", color.BlueString(name))
+		}
+		fmt.Println()
+	}
+
 	if len(result.ExtraVars) > 0 {
 		color.Red("❌ Extra variables in %s not found in %s (%d):", envFile, exampleFile, len(result.ExtraVars))
 		for _, name := range result.ExtraVars {
@@ -76,9 +101,21 @@ func PrintResults(result ValidationResult, envFile, exampleFile string) {
 		fmt.Println()
 	}
 
+	if len(result.Issues) > 0 {
+		color.Red("🔎 Issues (%d):", len(result.Issues))
+		PrintIssues(result.Issues)
+	}
+
 	PrintSummary(result)
 }
 
+func environmentSuffix(environment string) string {
+	if environment == "" {
+		return ""
+	}
+	return " for " + environment
+}
+
 func PrintSummary(result ValidationResult) {
 	color.Cyan("📈 Summary:")
 	color.Cyan("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
@@ -88,7 +125,9 @@ func PrintSummary(result ValidationResult) {
 	extraCount := len(result.ExtraVars)
 
 	var status string
-	if missingCount == 0 && extraCount == 0 {
+	if HasErrors(result.Issues) {
+		status = color.RedString("❌ Some variables have errors that must be fixed.")
+	} else if missingCount == 0 && extraCount == 0 {
 		status = color.GreenString("🎉 Perfect! All environment variables are properly configured.")
 	} else if missingCount > 0 && extraCount == 0 {
 		status = color.YellowString("⚠️  Some variables are missing from your .env file.")
@@ -113,5 +152,15 @@ func PrintSummary(result ValidationResult) {
 			color.RedString("❌"), extraCount)
 	}
 
+	if len(result.OptionalVars) > 0 {
+		fmt.Printf(" • %s %d optional",
+			color.BlueString("ℹ️"), len(result.OptionalVars))
+	}
+
+	if len(result.Issues) > 0 {
+		fmt.Printf(" • %s %d issues",
+			color.RedString("🔎"), len(result.Issues))
+	}
+
 	fmt.Println()
 }
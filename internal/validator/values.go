@@ -0,0 +1,118 @@
+package validator
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/crabest/envguard/internal/parser"
+)
+
+// Issue codes reported by CheckValues.
+const (
+	CodeInvalidValue     = "invalid-value"
+	CodePlaceholderValue = "placeholder-value"
+)
+
+// placeholders are values copied from a template that nobody filled in.
+var placeholders = map[string]bool{
+	"changeme": true, "change-me": true, "change_me": true, "secret": true, "password": true,
+	"todo": true, "xxx": true, "placeholder": true, "example": true,
+}
+
+// IsPlaceholder reports whether value is a well-known dummy such as
+// "change-me", "<your-token>" or "your-api-key" rather than a real value.
+func IsPlaceholder(value string) bool {
+	value = strings.TrimSpace(value)
+	lower := strings.ToLower(value)
+
+	switch {
+	case placeholders[lower]:
+		return true
+	case len(value) > 2 && strings.HasPrefix(value, "<") && strings.HasSuffix(value, ">"):
+		return true
+	case strings.HasPrefix(lower, "your-") || strings.HasPrefix(lower, "your_"):
+		return true
+	}
+	return false
+}
+
+// CheckValues reports values in an env file that do not match the @type
+// or @enum of their key in schema, and values that are still placeholders.
+// Empty values are left to the required-key checks. Values are never
+// included in messages.
+func CheckValues(file string, entries []parser.Entry, schema *Schema) []Issue {
+	var issues []Issue
+	for _, entry := range entries {
+		if entry.Value == "" {
+			continue
+		}
+
+		if IsPlaceholder(entry.Value) {
+			issues = append(issues, Issue{
+				Severity: SeverityWarning,
+				Code:     CodePlaceholderValue,
+				Key:      entry.Key,
+				File:     file,
+				Line:     entry.Line,
+				Message:  fmt.Sprintf("%s still has a placeholder value", entry.Key),
+			})
+			continue
+		}
+
+		if _, declared := schema.Specs[entry.Key]; !declared {
+			continue
+		}
+
+		if values := schema.Enum(entry.Key); len(values) > 0 && !containsString(values, entry.Value) {
+			issues = append(issues, Issue{
+				Severity: SeverityError,
+				Code:     CodeInvalidValue,
+				Key:      entry.Key,
+				File:     file,
+				Line:     entry.Line,
+				Message:  fmt.Sprintf("%s must be one of %s", entry.Key, strings.Join(values, ", ")),
+			})
+			continue
+		}
+
+		if valueType := schema.Type(entry.Key); !hasType(entry.Value, valueType) {
+			issues = append(issues, Issue{
+				Severity: SeverityError,
+				Code:     CodeInvalidValue,
+				Key:      entry.Key,
+				File:     file,
+				Line:     entry.Line,
+				Message:  fmt.Sprintf("%s is not a valid %s", entry.Key, valueType),
+			})
+		}
+	}
+
+	sortIssues(issues)
+	return issues
+}
+
+// hasType reports whether value parses as valueType, the way the
+// generated config and envguard.LoadInto read it. Unknown types accept
+// anything.
+func hasType(value, valueType string) bool {
+	var err error
+	switch valueType {
+	case TypeInt:
+		_, err = strconv.ParseInt(value, 10, 64)
+	case TypeFloat:
+		_, err = strconv.ParseFloat(value, 64)
+	case TypeBool:
+		_, err = strconv.ParseBool(value)
+	case TypeDuration:
+		_, err = time.ParseDuration(value)
+	case TypeURL:
+		var u *url.URL
+		if u, err = url.Parse(value); err == nil && u.Scheme == "" {
+			err = fmt.Errorf("missing scheme")
+		}
+	}
+	return err == nil
+}
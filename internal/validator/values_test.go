@@ -0,0 +1,75 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/crabest/envguard/internal/parser"
+)
+
+func TestCheckValues(t *testing.T) {
+	schema := newTestSchema(t, `# @type int
+PORT=3000
+# @type bool
+DEBUG=false
+# @type url
+API_URL=https://api.example.com
+# @type duration
+TIMEOUT=5s
+# @enum debug,info,warn
+LOG_LEVEL=info
+API_TOKEN=<your-token>
+`)
+
+	entries, err := parser.ParseEntries([]byte(`PORT=eighty
+DEBUG=yes
+API_URL=api.example.com
+TIMEOUT=30s
+LOG_LEVEL=trace
+API_TOKEN=<your-token>
+EXTRA=change-me
+EMPTY=
+`))
+	if err != nil {
+		t.Fatalf("Failed to parse entries: %v", err)
+	}
+
+	issues := CheckValues(".env", entries, schema)
+
+	expected := []struct {
+		code, key, message string
+		line               int
+	}{
+		{CodeInvalidValue, "PORT", "PORT is not a valid int", 1},
+		{CodeInvalidValue, "DEBUG", "DEBUG is not a valid bool", 2},
+		{CodeInvalidValue, "API_URL", "API_URL is not a valid url", 3},
+		{CodeInvalidValue, "LOG_LEVEL", "LOG_LEVEL must be one of debug, info, warn", 5},
+		{CodePlaceholderValue, "API_TOKEN", "API_TOKEN still has a placeholder value", 6},
+		{CodePlaceholderValue, "EXTRA", "EXTRA still has a placeholder value", 7},
+	}
+	if len(issues) != len(expected) {
+		t.Fatalf("Expected %d issues, got %v", len(expected), issues)
+	}
+	for i, want := range expected {
+		issue := issues[i]
+		if issue.Code != want.code || issue.Key != want.key || issue.Message != want.message || issue.Line != want.line {
+			t.Errorf("Issue %d: expected %s %s at line %d (%q), got %+v", i, want.code, want.key, want.line, want.message, issue)
+		}
+		if strings.Contains(issue.Message, "eighty") || strings.Contains(issue.Message, "trace") {
+			t.Errorf("Expected messages without values, got %q", issue.Message)
+		}
+	}
+}
+
+func TestIsPlaceholder(t *testing.T) {
+	for _, value := range []string{"change-me", "CHANGEME", "<api-key>", "your-token", "YOUR_SECRET", "todo"} {
+		if !IsPlaceholder(value) {
+			t.Errorf("Expected %q to be a placeholder", value)
+		}
+	}
+	for _, value := range []string{"", "none", "3000", "<>", "sk_live_123", "${API_KEY}"} {
+		if IsPlaceholder(value) {
+			t.Errorf("Expected %q not to be a placeholder", value)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package watch
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/crabest/envguard/internal/envmanager"
+)
+
+// Fingerprint hashes the files that decide what watch does: .env,
+// .env.example, .envguard/.active and the config. A batch of changes that
+// leaves it unchanged, such as watch's own sync writing the stored copy of
+// .env, needs no validation or restart.
+func Fingerprint(root string) string {
+	h := sha256.New()
+	for _, name := range []string{
+		".env",
+		".env.example",
+		filepath.Join(envmanager.EnvGuardDir, envmanager.ActiveFile),
+		filepath.Join(envmanager.EnvGuardDir, envmanager.ConfigFile),
+	} {
+		content, err := os.ReadFile(filepath.Join(root, name))
+		if err != nil {
+			content = nil
+		}
+		h.Write([]byte(name))
+		h.Write([]byte{0})
+		h.Write(content)
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Environ returns base, in os.Environ form, with vars added and taking
+// precedence, sorted by key.
+func Environ(base []string, vars map[string]string) []string {
+	merged := make(map[string]string, len(base)+len(vars))
+	for _, entry := range base {
+		if key, value, ok := strings.Cut(entry, "="); ok {
+			merged[key] = value
+		}
+	}
+	for key, value := range vars {
+		merged[key] = value
+	}
+
+	env := make([]string, 0, len(merged))
+	for key, value := range merged {
+		env = append(env, key+"="+value)
+	}
+	sort.Strings(env)
+	return env
+}
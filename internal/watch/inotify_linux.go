@@ -0,0 +1,111 @@
+//go:build linux
+
+package watch
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"golang.org/x/sys/unix"
+)
+
+const inotifyMask = unix.IN_CLOSE_WRITE | unix.IN_CREATE | unix.IN_DELETE | unix.IN_MOVED_FROM | unix.IN_MOVED_TO
+
+// inotify watches directories rather than files, since editors often
+// replace a file by renaming a new one over it.
+type inotify struct {
+	file *os.File
+	fd   int
+
+	mu   sync.Mutex
+	dirs map[int32]string
+
+	events chan string
+	errors chan error
+	done   chan struct{}
+}
+
+func newNotifier() (notifier, error) {
+	fd, err := unix.InotifyInit1(unix.IN_CLOEXEC | unix.IN_NONBLOCK)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start inotify: %w", err)
+	}
+
+	n := &inotify{
+		// A non-blocking descriptor lets the runtime poller wait on it,
+		// so Close interrupts a pending Read.
+		file:   os.NewFile(uintptr(fd), "inotify"),
+		fd:     fd,
+		dirs:   make(map[int32]string),
+		events: make(chan string, 64),
+		errors: make(chan error, 1),
+		done:   make(chan struct{}),
+	}
+	go n.read()
+	return n, nil
+}
+
+func (n *inotify) Add(dir string) error {
+	wd, err := unix.InotifyAddWatch(n.fd, dir, inotifyMask)
+	if err != nil {
+		return fmt.Errorf("failed to watch %s: %w", dir, err)
+	}
+
+	n.mu.Lock()
+	n.dirs[int32(wd)] = dir
+	n.mu.Unlock()
+	return nil
+}
+
+func (n *inotify) Events() <-chan string { return n.events }
+func (n *inotify) Errors() <-chan error  { return n.errors }
+
+func (n *inotify) Close() error {
+	close(n.done)
+	return n.file.Close()
+}
+
+func (n *inotify) read() {
+	buf := make([]byte, 64*(unix.SizeofInotifyEvent+unix.NAME_MAX+1))
+
+	for {
+		count, err := n.file.Read(buf)
+		if err != nil {
+			select {
+			case <-n.done:
+			case n.errors <- fmt.Errorf("failed to read inotify events: %w", err):
+			}
+			return
+		}
+
+		// struct inotify_event { int32 wd; uint32 mask, cookie, len; char name[len]; }
+		for offset := 0; offset+unix.SizeofInotifyEvent <= count; {
+			wd := int32(binary.NativeEndian.Uint32(buf[offset:]))
+			mask := binary.NativeEndian.Uint32(buf[offset+4:])
+			length := int(binary.NativeEndian.Uint32(buf[offset+12:]))
+			name := strings.TrimRight(string(buf[offset+unix.SizeofInotifyEvent:offset+unix.SizeofInotifyEvent+length]), "\x00")
+			offset += unix.SizeofInotifyEvent + length
+
+			n.mu.Lock()
+			dir, ok := n.dirs[wd]
+			if mask&unix.IN_IGNORED != 0 {
+				delete(n.dirs, wd)
+			}
+			n.mu.Unlock()
+
+			if !ok || name == "" {
+				continue
+			}
+
+			select {
+			case n.events <- filepath.Join(dir, name):
+			case <-n.done:
+				return
+			}
+		}
+	}
+}
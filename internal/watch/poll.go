@@ -0,0 +1,129 @@
+//go:build !linux
+
+package watch
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// pollInterval is how often directories are listed where inotify is not
+// available.
+const pollInterval = 250 * time.Millisecond
+
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// poller compares directory listings to find changed files.
+type poller struct {
+	mu   sync.Mutex
+	dirs map[string]map[string]fileState
+
+	events chan string
+	errors chan error
+	done   chan struct{}
+}
+
+func newNotifier() (notifier, error) {
+	p := &poller{
+		dirs:   make(map[string]map[string]fileState),
+		events: make(chan string, 64),
+		errors: make(chan error, 1),
+		done:   make(chan struct{}),
+	}
+	go p.loop()
+	return p, nil
+}
+
+func (p *poller) Add(dir string) error {
+	files, err := list(dir)
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	p.dirs[dir] = files
+	p.mu.Unlock()
+	return nil
+}
+
+func (p *poller) Events() <-chan string { return p.events }
+func (p *poller) Errors() <-chan error  { return p.errors }
+
+func (p *poller) Close() error {
+	close(p.done)
+	return nil
+}
+
+func (p *poller) loop() {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.done:
+			return
+		case <-ticker.C:
+		}
+
+		for _, path := range p.changes() {
+			select {
+			case p.events <- path:
+			case <-p.done:
+				return
+			}
+		}
+	}
+}
+
+// changes lists every watched directory again and returns the paths that
+// appeared, disappeared or changed since the last listing.
+func (p *poller) changes() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var changed []string
+	for dir, before := range p.dirs {
+		after, err := list(dir)
+		if err != nil {
+			// The directory is gone; report it once and stop listing it.
+			changed = append(changed, dir)
+			delete(p.dirs, dir)
+			continue
+		}
+
+		for name, state := range after {
+			if previous, ok := before[name]; !ok || previous != state {
+				changed = append(changed, filepath.Join(dir, name))
+			}
+		}
+		for name := range before {
+			if _, ok := after[name]; !ok {
+				changed = append(changed, filepath.Join(dir, name))
+			}
+		}
+		p.dirs[dir] = after
+	}
+	return changed
+}
+
+func list(dir string) (map[string]fileState, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make(map[string]fileState, len(entries))
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		files[entry.Name()] = fileState{modTime: info.ModTime(), size: info.Size()}
+	}
+	return files, nil
+}
@@ -0,0 +1,122 @@
+package watch
+
+import (
+	"io"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultStopTimeout is how long a child gets to exit after SIGTERM before
+// it is killed.
+const DefaultStopTimeout = 5 * time.Second
+
+// Child runs a command that is restarted whenever the environment
+// changes. On Unix the command gets its own process group so that stopping
+// it also stops what it spawned (npm run dev starts node, for example).
+// Since that takes it out of the terminal's foreground group, its stdin is
+// not connected.
+type Child struct {
+	Args   []string
+	Stdout io.Writer
+	Stderr io.Writer
+
+	// OnExit, when set, is called from another goroutine when the command
+	// exits without being stopped.
+	OnExit func(err error)
+
+	mu  sync.Mutex
+	run *childRun
+}
+
+type childRun struct {
+	cmd      *exec.Cmd
+	done     chan struct{}
+	stopping bool
+}
+
+// String returns the command line.
+func (c *Child) String() string {
+	return strings.Join(c.Args, " ")
+}
+
+// Running reports whether the command has been started and not exited.
+func (c *Child) Running() bool {
+	c.mu.Lock()
+	run := c.run
+	c.mu.Unlock()
+
+	if run == nil {
+		return false
+	}
+	select {
+	case <-run.done:
+		return false
+	default:
+		return true
+	}
+}
+
+// Start runs the command with env, stopping the previous run first.
+func (c *Child) Start(env []string) error {
+	c.Stop(DefaultStopTimeout)
+
+	cmd := exec.Command(c.Args[0], c.Args[1:]...)
+	cmd.Env = env
+	cmd.Stdout = c.Stdout
+	cmd.Stderr = c.Stderr
+	setProcessGroup(cmd)
+
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	run := &childRun{cmd: cmd, done: make(chan struct{})}
+	c.mu.Lock()
+	c.run = run
+	c.mu.Unlock()
+
+	go func() {
+		err := cmd.Wait()
+		c.mu.Lock()
+		stopping := run.stopping
+		c.mu.Unlock()
+		close(run.done)
+
+		if !stopping && c.OnExit != nil {
+			c.OnExit(err)
+		}
+	}()
+	return nil
+}
+
+// Stop terminates the command and waits for it, killing it when it has not
+// exited within timeout. It does nothing when the command is not running.
+func (c *Child) Stop(timeout time.Duration) {
+	c.mu.Lock()
+	run := c.run
+	c.run = nil
+	if run != nil {
+		run.stopping = true
+	}
+	c.mu.Unlock()
+
+	if run == nil {
+		return
+	}
+
+	select {
+	case <-run.done:
+		return
+	default:
+	}
+
+	terminate(run.cmd)
+	select {
+	case <-run.done:
+	case <-time.After(timeout):
+		kill(run.cmd)
+		<-run.done
+	}
+}
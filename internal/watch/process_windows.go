@@ -0,0 +1,17 @@
+//go:build windows
+
+package watch
+
+import "os/exec"
+
+func setProcessGroup(cmd *exec.Cmd) {}
+
+// Windows has no SIGTERM to send, so stopping kills the command straight
+// away.
+func terminate(cmd *exec.Cmd) {
+	cmd.Process.Kill()
+}
+
+func kill(cmd *exec.Cmd) {
+	cmd.Process.Kill()
+}
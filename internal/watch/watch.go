@@ -0,0 +1,105 @@
+// Package watch reports changes to the files envguard works with: the
+// root .env, .env.example and the environments in .envguard/. Changes come
+// from inotify on Linux; elsewhere the directories are polled.
+package watch
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/crabest/envguard/internal/envmanager"
+)
+
+// DefaultDebounce is how long the files must stay quiet before a batch of
+// changes is reported, so an editor's write-rename-chmod sequence or a
+// sync touching several files is handled once.
+const DefaultDebounce = 300 * time.Millisecond
+
+// notifier reports paths created, written, renamed or removed in the
+// directories added to it.
+type notifier interface {
+	Add(dir string) error
+	Events() <-chan string
+	Errors() <-chan error
+	Close() error
+}
+
+// Tracked reports whether path is one of the files watched in root:
+// .env, .env.example, or .active, config.json and stored environments in
+// .envguard/.
+func Tracked(root, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return false
+	}
+
+	switch rel {
+	case ".env", ".env.example":
+		return true
+	}
+
+	dir, name := filepath.Split(rel)
+	if filepath.Clean(dir) != envmanager.EnvGuardDir {
+		return false
+	}
+	return name == envmanager.ActiveFile || name == envmanager.ConfigFile || filepath.Ext(name) == ".env"
+}
+
+// Watch calls onChange with the tracked paths that changed, sorted, once
+// no change has happened for debounce. It returns when ctx is cancelled.
+// onChange runs on the calling goroutine; changes made meanwhile are
+// reported in the next batch.
+func Watch(ctx context.Context, root string, debounce time.Duration, onChange func(paths []string)) error {
+	n, err := newNotifier()
+	if err != nil {
+		return err
+	}
+	defer n.Close()
+
+	if err := n.Add(root); err != nil {
+		return err
+	}
+	store := filepath.Join(root, envmanager.EnvGuardDir)
+	if info, err := os.Stat(store); err == nil && info.IsDir() {
+		if err := n.Add(store); err != nil {
+			return err
+		}
+	}
+
+	pending := make(map[string]bool)
+	var fire <-chan time.Time
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-n.Errors():
+			return err
+		case path := <-n.Events():
+			if path == store {
+				// .envguard/ was created after the watch started.
+				if info, err := os.Stat(store); err == nil && info.IsDir() {
+					n.Add(store)
+				}
+				continue
+			}
+			if !Tracked(root, path) {
+				continue
+			}
+			pending[path] = true
+			fire = time.After(debounce)
+		case <-fire:
+			fire = nil
+			paths := make([]string, 0, len(pending))
+			for path := range pending {
+				paths = append(paths, path)
+			}
+			sort.Strings(paths)
+			pending = make(map[string]bool)
+			onChange(paths)
+		}
+	}
+}
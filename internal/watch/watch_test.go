@@ -0,0 +1,235 @@
+package watch
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newProject(t *testing.T) string {
+	t.Helper()
+	tmpDir, err := os.MkdirTemp("", "envguard-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(tmpDir) })
+
+	if err := os.WriteFile(filepath.Join(tmpDir, ".env"), []byte("PORT=3000\n"), 0644); err != nil {
+		t.Fatalf("Failed to write .env: %v", err)
+	}
+	return tmpDir
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write %s: %v", path, err)
+	}
+}
+
+// startWatch runs Watch in the background and returns the batches it
+// reports.
+func startWatch(t *testing.T, root string) <-chan []string {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	batches := make(chan []string, 10)
+	errs := make(chan error, 1)
+
+	go func() {
+		errs <- Watch(ctx, root, 50*time.Millisecond, func(paths []string) { batches <- paths })
+	}()
+	t.Cleanup(func() {
+		cancel()
+		if err := <-errs; err != nil {
+			t.Errorf("Watch returned %v", err)
+		}
+	})
+
+	// Give the notifier time to add its watches.
+	time.Sleep(100 * time.Millisecond)
+	return batches
+}
+
+func nextBatch(t *testing.T, batches <-chan []string) []string {
+	t.Helper()
+	select {
+	case paths := <-batches:
+		return paths
+	case <-time.After(3 * time.Second):
+		t.Fatal("No change reported")
+		return nil
+	}
+}
+
+func TestTracked(t *testing.T) {
+	root := filepath.Join("project")
+	cases := map[string]bool{
+		".env":                       true,
+		".env.example":               true,
+		".envguard/.active":          true,
+		".envguard/config.json":      true,
+		".envguard/staging.env":      true,
+		".env.local":                 false,
+		"main.go":                    false,
+		".envguard/.snapshots":       false,
+		".envguard/.active.tmp":      false,
+		"sub/.env":                   false,
+		".envguard/.snapshots/a.env": false,
+	}
+
+	for rel, want := range cases {
+		if got := Tracked(root, filepath.Join(root, rel)); got != want {
+			t.Errorf("Tracked(%s) = %v, want %v", rel, got, want)
+		}
+	}
+}
+
+func TestWatchDebouncesChanges(t *testing.T) {
+	root := newProject(t)
+	batches := startWatch(t, root)
+
+	envPath := filepath.Join(root, ".env")
+	examplePath := filepath.Join(root, ".env.example")
+	writeFile(t, envPath, "PORT=3001\n")
+	writeFile(t, envPath, "PORT=3002\n")
+	writeFile(t, examplePath, "PORT=\n")
+	writeFile(t, filepath.Join(root, "main.go"), "package main\n")
+
+	paths := nextBatch(t, batches)
+	if strings.Join(paths, ",") != envPath+","+examplePath {
+		t.Errorf("Expected one batch with .env and .env.example, got %v", paths)
+	}
+
+	select {
+	case paths := <-batches:
+		t.Errorf("Expected a single batch, got another: %v", paths)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+func TestWatchFollowsRenamesAndNewStore(t *testing.T) {
+	root := newProject(t)
+	batches := startWatch(t, root)
+
+	// .envguard/ does not exist yet when the watch starts.
+	store := filepath.Join(root, ".envguard")
+	if err := os.Mkdir(store, 0755); err != nil {
+		t.Fatalf("Failed to create .envguard: %v", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	tmp := filepath.Join(store, ".active.tmp")
+	writeFile(t, tmp, "staging\n")
+	if err := os.Rename(tmp, filepath.Join(store, ".active")); err != nil {
+		t.Fatalf("Failed to rename: %v", err)
+	}
+
+	paths := nextBatch(t, batches)
+	if len(paths) != 1 || paths[0] != filepath.Join(store, ".active") {
+		t.Errorf("Expected .envguard/.active, got %v", paths)
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	root := newProject(t)
+	before := Fingerprint(root)
+
+	// Stored environments are not part of the fingerprint.
+	if err := os.Mkdir(filepath.Join(root, ".envguard"), 0755); err != nil {
+		t.Fatalf("Failed to create .envguard: %v", err)
+	}
+	writeFile(t, filepath.Join(root, ".envguard", "dev.env"), "PORT=3000\n")
+	if Fingerprint(root) != before {
+		t.Error("Expected stored environments not to change the fingerprint")
+	}
+
+	writeFile(t, filepath.Join(root, ".env"), "PORT=4000\n")
+	if Fingerprint(root) == before {
+		t.Error("Expected a .env change to change the fingerprint")
+	}
+}
+
+func TestEnviron(t *testing.T) {
+	env := Environ([]string{"PATH=/bin", "PORT=80", "EMPTY="}, map[string]string{"PORT": "3000", "DEBUG": "true"})
+	want := "DEBUG=true,EMPTY=,PATH=/bin,PORT=3000"
+	if got := strings.Join(env, ","); got != want {
+		t.Errorf("Environ() = %s, want %s", got, want)
+	}
+}
+
+func TestChildRestart(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+
+	root := newProject(t)
+	out := filepath.Join(root, "out")
+	exited := make(chan error, 1)
+	child := &Child{
+		Args:   []string{"sh", "-c", `echo "$PORT" >> "$OUT"; exec sleep 30`},
+		OnExit: func(err error) { exited <- err },
+	}
+
+	if err := child.Start([]string{"PORT=1", "OUT=" + out}); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	if err := child.Start([]string{"PORT=2", "OUT=" + out}); err != nil {
+		t.Fatalf("Restart failed: %v", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	if !child.Running() {
+		t.Error("Expected the child to be running")
+	}
+
+	start := time.Now()
+	child.Stop(time.Second)
+	if time.Since(start) > 900*time.Millisecond {
+		t.Error("Expected SIGTERM to stop the child without waiting for the timeout")
+	}
+	if child.Running() {
+		t.Error("Expected the child to be stopped")
+	}
+
+	content, _ := os.ReadFile(out)
+	if string(content) != "1\n2\n" {
+		t.Errorf("Expected one line per run, got %q", content)
+	}
+
+	select {
+	case err := <-exited:
+		t.Errorf("OnExit called for a stopped child: %v", err)
+	default:
+	}
+}
+
+func TestChildOnExit(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+
+	exited := make(chan error, 1)
+	child := &Child{
+		Args:   []string{"sh", "-c", "exit 3"},
+		OnExit: func(err error) { exited <- err },
+	}
+	if err := child.Start(nil); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	select {
+	case err := <-exited:
+		if err == nil || !strings.Contains(err.Error(), "exit status 3") {
+			t.Errorf("Expected exit status 3, got %v", err)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("OnExit not called")
+	}
+	if child.Running() {
+		t.Error("Expected the child not to be running")
+	}
+}
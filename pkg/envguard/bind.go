@@ -0,0 +1,309 @@
+package envguard
+
+import (
+	"encoding"
+	"fmt"
+	"net/url"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Bind populates the struct pointed to by target from vars using field tags:
+//
+//	type Config struct {
+//		Port    int           `env:"PORT,required"`
+//		Timeout time.Duration `env:"TIMEOUT" default:"30s"`
+//		Hosts   []string      `env:"HOSTS" separator:";"`
+//		DB      DBConfig      `envPrefix:"DB_"`
+//	}
+//
+// Supported field types are strings, integers, floats, bools,
+// time.Duration, url.URL, slices of these, pointers to these and any type
+// implementing encoding.TextUnmarshaler. Nested structs are bound with
+// their envPrefix prepended to every key. A nil pointer to a nested struct
+// is optional: it is only allocated, and its required fields checked, when
+// at least one of its variables is set. All problems are returned together
+// as a *BindError.
+func Bind(vars map[string]string, target interface{}) error {
+	value := reflect.ValueOf(target)
+	if value.Kind() != reflect.Ptr || value.IsNil() || value.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("envguard: Bind target must be a non-nil pointer to a struct, got %T", target)
+	}
+
+	bindErr := &BindError{}
+	bindStruct(vars, value.Elem(), "", "", bindErr)
+
+	if len(bindErr.Fields) > 0 {
+		return bindErr
+	}
+	return nil
+}
+
+// LoadInto loads and validates the environment described by opts and binds
+// it into target. As in validation, fields whose variable the file does
+// not set are bound from the process environment.
+func LoadInto(opts Options, target interface{}) error {
+	vars, err := Load(opts)
+	if err != nil {
+		return err
+	}
+
+	if value := reflect.Indirect(reflect.ValueOf(target)); value.Kind() == reflect.Struct {
+		for _, key := range boundKeys(value.Type(), "") {
+			if _, ok := vars[key]; ok {
+				continue
+			}
+			if processValue, ok := os.LookupEnv(key); ok {
+				vars[key] = processValue
+			}
+		}
+	}
+	return Bind(vars, target)
+}
+
+// FieldError describes why a single struct field could not be bound.
+type FieldError struct {
+	Field string
+	Key   string
+	Err   error
+}
+
+func (e FieldError) Error() string {
+	return fmt.Sprintf("%s (%s): %v", e.Field, e.Key, e.Err)
+}
+
+// BindError collects every field that failed to bind.
+type BindError struct {
+	Fields []FieldError
+}
+
+func (e *BindError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "envguard: failed to bind %d fields", len(e.Fields))
+	for _, field := range e.Fields {
+		fmt.Fprintf(&b, "\n  - %s", field.Error())
+	}
+	return b.String()
+}
+
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	urlType             = reflect.TypeOf(url.URL{})
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+func bindStruct(vars map[string]string, value reflect.Value, prefix, path string, bindErr *BindError) {
+	structType := value.Type()
+
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		fieldValue := value.Field(i)
+		fieldPath := field.Name
+		if path != "" {
+			fieldPath = path + "." + field.Name
+		}
+
+		tag, hasTag := field.Tag.Lookup("env")
+		if !hasTag {
+			if isNestedStruct(field.Type) {
+				nestedPrefix := prefix + field.Tag.Get("envPrefix")
+				if fieldValue.Kind() == reflect.Ptr {
+					if fieldValue.IsNil() {
+						// A nil pointer is an optional section: leave it nil,
+						// and its required fields unchecked, unless one of
+						// its variables is set.
+						if !setsAny(vars, field.Type.Elem(), nestedPrefix) {
+							continue
+						}
+						fieldValue.Set(reflect.New(field.Type.Elem()))
+					}
+					fieldValue = fieldValue.Elem()
+				}
+				bindStruct(vars, fieldValue, nestedPrefix, fieldPath, bindErr)
+			}
+			continue
+		}
+
+		name, options := parseTag(tag)
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		key := prefix + name
+
+		raw, ok := vars[key]
+		if !ok || raw == "" {
+			if def, hasDefault := field.Tag.Lookup("default"); hasDefault {
+				raw, ok = def, true
+			}
+		}
+
+		if !ok {
+			if options["required"] {
+				bindErr.Fields = append(bindErr.Fields, FieldError{Field: fieldPath, Key: key, Err: fmt.Errorf("required variable is not set")})
+			}
+			continue
+		}
+
+		separator := field.Tag.Get("separator")
+		if separator == "" {
+			separator = ","
+		}
+
+		if err := setValue(fieldValue, raw, separator); err != nil {
+			bindErr.Fields = append(bindErr.Fields, FieldError{Field: fieldPath, Key: key, Err: err})
+		}
+	}
+}
+
+// setsAny reports whether vars has a non-empty value for any field of the
+// struct type t, including its nested structs.
+func setsAny(vars map[string]string, t reflect.Type, prefix string) bool {
+	for _, key := range boundKeys(t, prefix) {
+		if vars[key] != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// boundKeys returns the variable names Bind reads for the struct type t,
+// including those of its nested structs.
+func boundKeys(t reflect.Type, prefix string) []string {
+	var keys []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		tag, hasTag := field.Tag.Lookup("env")
+		if !hasTag {
+			if isNestedStruct(field.Type) {
+				nested := field.Type
+				if nested.Kind() == reflect.Ptr {
+					nested = nested.Elem()
+				}
+				keys = append(keys, boundKeys(nested, prefix+field.Tag.Get("envPrefix"))...)
+			}
+			continue
+		}
+
+		name, _ := parseTag(tag)
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		keys = append(keys, prefix+name)
+	}
+	return keys
+}
+
+func parseTag(tag string) (string, map[string]bool) {
+	parts := strings.Split(tag, ",")
+	options := make(map[string]bool)
+	for _, option := range parts[1:] {
+		options[strings.TrimSpace(option)] = true
+	}
+	return strings.TrimSpace(parts[0]), options
+}
+
+func isNestedStruct(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || t == urlType {
+		return false
+	}
+	return !reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
+
+func setValue(value reflect.Value, raw, separator string) error {
+	if value.Kind() == reflect.Ptr {
+		target := reflect.New(value.Type().Elem())
+		if err := setValue(target.Elem(), raw, separator); err != nil {
+			return err
+		}
+		value.Set(target)
+		return nil
+	}
+
+	if value.CanAddr() {
+		if unmarshaler, ok := value.Addr().Interface().(encoding.TextUnmarshaler); ok {
+			return unmarshaler.UnmarshalText([]byte(raw))
+		}
+	}
+
+	switch value.Type() {
+	case durationType:
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q", raw)
+		}
+		value.SetInt(int64(d))
+		return nil
+	case urlType:
+		u, err := url.Parse(raw)
+		if err != nil {
+			return fmt.Errorf("invalid URL %q: %v", raw, err)
+		}
+		value.Set(reflect.ValueOf(*u))
+		return nil
+	}
+
+	switch value.Kind() {
+	case reflect.String:
+		value.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", raw)
+		}
+		value.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, value.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", raw)
+		}
+		value.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(raw, 10, value.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid unsigned integer %q", raw)
+		}
+		value.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(raw, value.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid number %q", raw)
+		}
+		value.SetFloat(f)
+	case reflect.Slice:
+		var parts []string
+		if raw != "" {
+			parts = strings.Split(raw, separator)
+		}
+		slice := reflect.MakeSlice(value.Type(), len(parts), len(parts))
+		for i, part := range parts {
+			if err := setValue(slice.Index(i), strings.TrimSpace(part), separator); err != nil {
+				return fmt.Errorf("element %d: %v", i, err)
+			}
+		}
+		value.Set(slice)
+	default:
+		return fmt.Errorf("unsupported field type %s", value.Type())
+	}
+
+	return nil
+}
@@ -0,0 +1,159 @@
+package envguard
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+type logLevel int
+
+func (l *logLevel) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "debug":
+		*l = 0
+	case "info":
+		*l = 1
+	default:
+		return fmt.Errorf("unknown level %q", string(text))
+	}
+	return nil
+}
+
+type dbConfig struct {
+	Host string `env:"HOST,required"`
+	Port int    `env:"PORT" default:"5432"`
+}
+
+type appConfig struct {
+	Name     string        `env:"APP_NAME,required"`
+	Debug    bool          `env:"DEBUG"`
+	Timeout  time.Duration `env:"TIMEOUT" default:"30s"`
+	Endpoint url.URL       `env:"ENDPOINT"`
+	Callback *url.URL      `env:"CALLBACK"`
+	Hosts    []string      `env:"HOSTS"`
+	Ports    []int         `env:"PORTS" separator:";"`
+	Level    logLevel      `env:"LOG_LEVEL" default:"info"`
+	Ratio    float64       `env:"RATIO"`
+	DB       dbConfig      `envPrefix:"DB_"`
+	Replica  *dbConfig     `envPrefix:"REPLICA_"`
+	Ignored  string        `env:"-"`
+	internal string
+}
+
+func TestBind(t *testing.T) {
+	vars := map[string]string{
+		"APP_NAME":     "api",
+		"DEBUG":        "true",
+		"ENDPOINT":     "https://example.com/v1",
+		"CALLBACK":     "https://example.com/cb",
+		"HOSTS":        "a.example.com, b.example.com",
+		"PORTS":        "80;443",
+		"RATIO":        "0.5",
+		"DB_HOST":      "db.local",
+		"REPLICA_HOST": "replica.local",
+		"REPLICA_PORT": "6543",
+	}
+
+	var cfg appConfig
+	if err := Bind(vars, &cfg); err != nil {
+		t.Fatalf("Failed to bind: %v", err)
+	}
+
+	if cfg.Name != "api" || !cfg.Debug || cfg.Timeout != 30*time.Second || cfg.Ratio != 0.5 {
+		t.Errorf("Unexpected scalar fields: %+v", cfg)
+	}
+	if cfg.Endpoint.Host != "example.com" || cfg.Callback == nil || cfg.Callback.Path != "/cb" {
+		t.Errorf("Unexpected URLs: %v %v", cfg.Endpoint, cfg.Callback)
+	}
+	if len(cfg.Hosts) != 2 || cfg.Hosts[1] != "b.example.com" {
+		t.Errorf("Unexpected hosts: %v", cfg.Hosts)
+	}
+	if len(cfg.Ports) != 2 || cfg.Ports[1] != 443 {
+		t.Errorf("Unexpected ports: %v", cfg.Ports)
+	}
+	if cfg.Level != 1 {
+		t.Errorf("Expected default log level info, got %v", cfg.Level)
+	}
+	if cfg.DB.Host != "db.local" || cfg.DB.Port != 5432 {
+		t.Errorf("Unexpected DB config: %+v", cfg.DB)
+	}
+	if cfg.Replica == nil || cfg.Replica.Port != 6543 {
+		t.Errorf("Unexpected replica config: %+v", cfg.Replica)
+	}
+}
+
+func TestBindReportsAllErrors(t *testing.T) {
+	vars := map[string]string{
+		"DEBUG":     "maybe",
+		"TIMEOUT":   "soon",
+		"LOG_LEVEL": "loud",
+		"DB_PORT":   "x",
+	}
+
+	var cfg appConfig
+	err := Bind(vars, &cfg)
+
+	var bindErr *BindError
+	if !errors.As(err, &bindErr) {
+		t.Fatalf("Expected *BindError, got %v", err)
+	}
+
+	// APP_NAME, DEBUG, TIMEOUT, LOG_LEVEL, DB_HOST, DB_PORT; the unset
+	// replica section is optional
+	if len(bindErr.Fields) != 6 {
+		t.Errorf("Expected 6 field errors, got %d:\n%v", len(bindErr.Fields), err)
+	}
+	if cfg.Replica != nil {
+		t.Errorf("Expected no replica config, got %+v", cfg.Replica)
+	}
+	if !strings.Contains(err.Error(), "DB.Host (DB_HOST)") {
+		t.Errorf("Expected nested field path in error, got %v", err)
+	}
+}
+
+func TestBindPartialOptionalSection(t *testing.T) {
+	vars := map[string]string{"APP_NAME": "api", "DB_HOST": "db.local", "REPLICA_PORT": "6543"}
+
+	var cfg appConfig
+	err := Bind(vars, &cfg)
+
+	var bindErr *BindError
+	if !errors.As(err, &bindErr) {
+		t.Fatalf("Expected *BindError, got %v", err)
+	}
+	if len(bindErr.Fields) != 1 || bindErr.Fields[0].Key != "REPLICA_HOST" {
+		t.Errorf("Expected only REPLICA_HOST to be reported, got %v", err)
+	}
+}
+
+func TestBindRejectsNonStruct(t *testing.T) {
+	var n int
+	if err := Bind(map[string]string{}, &n); err == nil {
+		t.Error("Expected error for non-struct target")
+	}
+}
+
+func TestLoadIntoUsesProcessEnvironment(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeFile(t, filepath.Join(tmpDir, ".env.example"), "APP_NAME=\nENVGUARD_TEST_DB_HOST=\n")
+	writeFile(t, filepath.Join(tmpDir, ".env"), "APP_NAME=api\n")
+	t.Setenv("ENVGUARD_TEST_DB_HOST", "db.internal")
+
+	var cfg struct {
+		Name string `env:"APP_NAME,required"`
+		DB   struct {
+			Host string `env:"DB_HOST,required"`
+		} `envPrefix:"ENVGUARD_TEST_"`
+	}
+	if err := LoadInto(Options{Dir: tmpDir}, &cfg); err != nil {
+		t.Fatalf("Expected a key set only in the process to bind, got %v", err)
+	}
+	if cfg.Name != "api" || cfg.DB.Host != "db.internal" {
+		t.Errorf("Unexpected config: %+v", cfg)
+	}
+}
@@ -0,0 +1,260 @@
+// Package envguard lets Go programs load their dotenv configuration with
+// the same validation rules the envguard CLI enforces, so a service can
+// fail fast at startup instead of running with a half-configured
+// environment.
+//
+//	vars, err := envguard.Load(envguard.Options{Apply: true})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+package envguard
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/crabest/envguard/internal/envmanager"
+	"github.com/crabest/envguard/internal/parser"
+	"github.com/crabest/envguard/internal/validator"
+)
+
+// Options controls what Load reads and how strictly it validates.
+type Options struct {
+	// Dir is the project directory. Defaults to the working directory.
+	Dir string
+
+	// EnvFile is the dotenv file to load, relative to Dir. Defaults to ".env".
+	EnvFile string
+
+	// Environment loads .envguard/<Environment>.env instead of EnvFile.
+	Environment string
+
+	// As is the environment @required-in rules are checked against, like
+	// the CLI's --as flag. It defaults to Environment, then to the active
+	// environment when loading the root .env, then to $ENVGUARD_ENV. When
+	// none of these is known, keys marked @required-in are never required.
+	As string
+
+	// ExampleFile is the example/schema file, relative to Dir. Defaults to
+	// ".env.example". Validation is skipped when the file does not exist.
+	ExampleFile string
+
+	// Interpolation selects how ${VAR} references in values are resolved:
+	// "none", "godotenv" (the default) or "compose".
+	Interpolation string
+
+	// SkipValidation loads the variables without checking them.
+	SkipValidation bool
+
+	// Strict also reports variables that are not declared in the example.
+	Strict bool
+
+	// Apply sets every loaded variable in the process environment.
+	Apply bool
+
+	// Override lets Apply replace variables already set in the process.
+	// By default existing process variables win, as with most dotenv loaders.
+	Override bool
+}
+
+// ProblemKind classifies a validation problem.
+type ProblemKind string
+
+const (
+	ProblemMissing ProblemKind = "missing"
+	ProblemExtra   ProblemKind = "extra"
+	ProblemInvalid ProblemKind = "invalid"
+)
+
+// Problem is a single validation failure.
+type Problem struct {
+	Key     string
+	Kind    ProblemKind
+	Message string
+}
+
+// ValidationError lists every problem found while validating a file.
+type ValidationError struct {
+	File        string
+	ExampleFile string
+	Problems    []Problem
+}
+
+func (e *ValidationError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "envguard: %s failed validation against %s (%d problems)", e.File, e.ExampleFile, len(e.Problems))
+	for _, problem := range e.Problems {
+		fmt.Fprintf(&b, "\n  - %s", problem.Message)
+	}
+	return b.String()
+}
+
+// Load reads and validates the configured dotenv file and returns its
+// variables. Validation runs the same checks as the envguard command,
+// including the @type and @enum of every value, except that declared keys
+// already set in the process environment are not reported as missing.
+// Validation failures are returned as a *ValidationError listing all
+// problems; with Apply, nothing is set unless validation passes.
+func Load(opts Options) (map[string]string, error) {
+	opts = withDefaults(opts)
+
+	envPath, err := resolveEnvPath(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	mode, err := parser.ParseInterpolation(opts.Interpolation)
+	if err != nil {
+		return nil, fmt.Errorf("envguard: %w", err)
+	}
+
+	var envVars parser.EnvVars
+	if opts.SkipValidation {
+		envVars, err = parser.ParseEnvFileWith(envPath, mode)
+		if err != nil {
+			return nil, fmt.Errorf("envguard: failed to parse %s: %w", envPath, err)
+		}
+	} else {
+		if envVars, err = validate(envPath, mode, opts); err != nil {
+			return nil, err
+		}
+	}
+
+	if opts.Apply {
+		if err := apply(envVars, opts.Override); err != nil {
+			return nil, err
+		}
+	}
+
+	return map[string]string(envVars), nil
+}
+
+// MustLoad is like Load but panics on error.
+func MustLoad(opts Options) map[string]string {
+	vars, err := Load(opts)
+	if err != nil {
+		panic(err)
+	}
+	return vars
+}
+
+func withDefaults(opts Options) Options {
+	if opts.Dir == "" {
+		opts.Dir = "."
+	}
+	if opts.EnvFile == "" {
+		opts.EnvFile = ".env"
+	}
+	if opts.ExampleFile == "" {
+		opts.ExampleFile = ".env.example"
+	}
+	if opts.Interpolation == "" {
+		opts.Interpolation = string(parser.InterpolateGodotenv)
+	}
+	return opts
+}
+
+func resolveEnvPath(opts Options) (string, error) {
+	if opts.Environment == "" {
+		return filepath.Join(opts.Dir, opts.EnvFile), nil
+	}
+
+	if err := envmanager.ValidateEnvName(opts.Environment); err != nil {
+		return "", fmt.Errorf("envguard: %w", err)
+	}
+
+	manager, err := envmanager.NewManagerAt(opts.Dir)
+	if err != nil {
+		return "", fmt.Errorf("envguard: %w", err)
+	}
+
+	if !manager.EnvironmentExists(opts.Environment) {
+		return "", fmt.Errorf("envguard: environment '%s' does not exist in %s", opts.Environment, envmanager.EnvGuardDir)
+	}
+	return manager.GetEnvPath(opts.Environment), nil
+}
+
+// validate runs the same checks as the envguard CLI and returns the
+// parsed variables. Declared keys missing from the file but already set in
+// the process environment count as present, since the application will
+// see them either way.
+func validate(envPath string, mode parser.Interpolation, opts Options) (parser.EnvVars, error) {
+	examplePath := filepath.Join(opts.Dir, opts.ExampleFile)
+
+	if _, err := os.Stat(examplePath); os.IsNotExist(err) {
+		envVars, err := parser.ParseEnvFileWith(envPath, mode)
+		if err != nil {
+			return nil, fmt.Errorf("envguard: failed to parse %s: %w", envPath, err)
+		}
+		return envVars, nil
+	}
+
+	result, envVars, err := validator.ValidateFile(validator.FileCheck{
+		EnvFile:       envPath,
+		ExampleFile:   examplePath,
+		Interpolation: mode,
+		Environment:   validationEnvironment(opts),
+		Lookup:        os.LookupEnv,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("envguard: %w", err)
+	}
+
+	var problems []Problem
+	for _, key := range result.MissingVars {
+		message := fmt.Sprintf("%s is missing", key)
+		if reason, ok := result.Reasons[key]; ok {
+			message = fmt.Sprintf("%s is missing (%s)", key, reason)
+		}
+		problems = append(problems, Problem{Key: key, Kind: ProblemMissing, Message: message})
+	}
+	for _, issue := range result.Issues {
+		if issue.Severity == validator.SeverityError {
+			problems = append(problems, Problem{Key: issue.Key, Kind: ProblemInvalid, Message: issue.Message})
+		}
+	}
+	if opts.Strict {
+		for _, key := range result.ExtraVars {
+			problems = append(problems, Problem{Key: key, Kind: ProblemExtra, Message: fmt.Sprintf("%s is not declared in %s", key, opts.ExampleFile)})
+		}
+	}
+
+	if len(problems) == 0 {
+		return envVars, nil
+	}
+	return nil, &ValidationError{File: envPath, ExampleFile: examplePath, Problems: problems}
+}
+
+// validationEnvironment is the environment @required-in rules are checked
+// against, as documented on Options.As.
+func validationEnvironment(opts Options) string {
+	if opts.As != "" {
+		return opts.As
+	}
+	if opts.Environment != "" {
+		return opts.Environment
+	}
+
+	if filepath.Clean(opts.EnvFile) == ".env" {
+		if manager, err := envmanager.NewManagerAt(opts.Dir); err == nil {
+			if active, err := manager.GetActiveEnvironment(); err == nil {
+				return active
+			}
+		}
+	}
+	return os.Getenv(envmanager.EnvironmentEnv)
+}
+
+func apply(envVars parser.EnvVars, override bool) error {
+	for key, value := range envVars {
+		if _, exists := os.LookupEnv(key); exists && !override {
+			continue
+		}
+		if err := os.Setenv(key, value); err != nil {
+			return fmt.Errorf("envguard: failed to set %s: %w", key, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,196 @@
+package envguard
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("Failed to create dir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write %s: %v", path, err)
+	}
+}
+
+func TestLoadValid(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "envguard-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	writeFile(t, filepath.Join(tmpDir, ".env.example"), "ENVGUARD_TEST_PORT=\nENVGUARD_TEST_HOST=\n")
+	writeFile(t, filepath.Join(tmpDir, ".env"), "ENVGUARD_TEST_PORT=8080\nENVGUARD_TEST_HOST=localhost\n")
+
+	os.Setenv("ENVGUARD_TEST_HOST", "from-process")
+	defer os.Unsetenv("ENVGUARD_TEST_HOST")
+	defer os.Unsetenv("ENVGUARD_TEST_PORT")
+
+	vars, err := Load(Options{Dir: tmpDir, Apply: true})
+	if err != nil {
+		t.Fatalf("Expected valid load, got %v", err)
+	}
+
+	if vars["ENVGUARD_TEST_PORT"] != "8080" {
+		t.Errorf("Expected PORT=8080, got %q", vars["ENVGUARD_TEST_PORT"])
+	}
+	if os.Getenv("ENVGUARD_TEST_PORT") != "8080" {
+		t.Error("Apply should set unset variables")
+	}
+	if os.Getenv("ENVGUARD_TEST_HOST") != "from-process" {
+		t.Error("Apply should not override existing variables by default")
+	}
+}
+
+func TestLoadReportsAllProblems(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "envguard-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	writeFile(t, filepath.Join(tmpDir, ".env.example"), "A=\nB=\nC=\n")
+	writeFile(t, filepath.Join(tmpDir, ".env"), "A=1\nEXTRA=1\n")
+
+	_, err = Load(Options{Dir: tmpDir, Strict: true, Apply: true})
+
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Expected *ValidationError, got %v", err)
+	}
+	if len(validationErr.Problems) != 3 {
+		t.Errorf("Expected 3 problems, got %+v", validationErr.Problems)
+	}
+	if os.Getenv("EXTRA") != "" {
+		t.Error("Nothing should be applied when validation fails")
+	}
+
+	if _, err := Load(Options{Dir: tmpDir, SkipValidation: true}); err != nil {
+		t.Errorf("SkipValidation should load anyway, got %v", err)
+	}
+}
+
+func TestLoadStoredEnvironment(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "envguard-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	writeFile(t, filepath.Join(tmpDir, ".envguard", "staging.env"), "MODE=staging\n")
+
+	vars, err := Load(Options{Dir: tmpDir, Environment: "staging"})
+	if err != nil {
+		t.Fatalf("Failed to load stored environment: %v", err)
+	}
+	if vars["MODE"] != "staging" {
+		t.Errorf("Expected MODE=staging, got %q", vars["MODE"])
+	}
+
+	if _, err := Load(Options{Dir: tmpDir, Environment: "missing"}); err == nil {
+		t.Error("Expected error for unknown environment")
+	}
+}
+
+func TestLoadRequiredInActiveEnvironment(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "envguard-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	writeFile(t, filepath.Join(tmpDir, ".env.example"), "APP=\n# @required-in production\nSTRIPE_KEY=\n")
+	writeFile(t, filepath.Join(tmpDir, ".env"), "APP=api\n")
+	writeFile(t, filepath.Join(tmpDir, ".envguard", ".active"), "development\n")
+
+	if _, err := Load(Options{Dir: tmpDir}); err != nil {
+		t.Errorf("Expected STRIPE_KEY to be optional in development, got %v", err)
+	}
+
+	writeFile(t, filepath.Join(tmpDir, ".envguard", ".active"), "production\n")
+
+	var validationErr *ValidationError
+	if _, err := Load(Options{Dir: tmpDir}); !errors.As(err, &validationErr) {
+		t.Fatalf("Expected a ValidationError in production, got %v", err)
+	}
+	if validationErr.Problems[0].Message != "STRIPE_KEY is missing (required in production)" {
+		t.Errorf("Unexpected problem: %s", validationErr.Problems[0].Message)
+	}
+}
+
+func TestLoadSharesCLIValidation(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "envguard-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	writeFile(t, filepath.Join(tmpDir, ".env.example"), "APP=\nENVGUARD_TEST_DATABASE_URL=\n")
+	writeFile(t, filepath.Join(tmpDir, ".env"), "APP=api\n")
+
+	os.Setenv("ENVGUARD_TEST_DATABASE_URL", "postgres://injected")
+	defer os.Unsetenv("ENVGUARD_TEST_DATABASE_URL")
+
+	vars, err := Load(Options{Dir: tmpDir})
+	if err != nil {
+		t.Fatalf("Variables set in the process should count as present, got %v", err)
+	}
+	if _, ok := vars["ENVGUARD_TEST_DATABASE_URL"]; ok {
+		t.Error("Load should only return variables from the file")
+	}
+
+	writeFile(t, filepath.Join(tmpDir, ".env"), "APP=${APP_NAME:?must be set}\n")
+
+	var validationErr *ValidationError
+	if _, err := Load(Options{Dir: tmpDir, Interpolation: "compose"}); !errors.As(err, &validationErr) {
+		t.Fatalf("Expected a ValidationError for the undefined reference, got %v", err)
+	}
+	if len(validationErr.Problems) != 1 || validationErr.Problems[0].Kind != ProblemInvalid || validationErr.Problems[0].Key != "APP" {
+		t.Errorf("Unexpected problems: %+v", validationErr.Problems)
+	}
+
+	writeFile(t, filepath.Join(tmpDir, ".env.example"), "# @type int\nPORT=3000\n# @enum debug,info\nLOG_LEVEL=info\n")
+	writeFile(t, filepath.Join(tmpDir, ".env"), "PORT=eighty\nLOG_LEVEL=info\n")
+
+	if _, err := Load(Options{Dir: tmpDir}); !errors.As(err, &validationErr) {
+		t.Fatalf("Expected a ValidationError for the invalid value, got %v", err)
+	}
+	if len(validationErr.Problems) != 1 || validationErr.Problems[0].Key != "PORT" {
+		t.Errorf("Unexpected problems: %+v", validationErr.Problems)
+	}
+}
+
+func TestLoadAs(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "envguard-test")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	writeFile(t, filepath.Join(tmpDir, ".env.example"), "APP=\n# @required-in production\nENVGUARD_TEST_STRIPE_KEY=\n")
+	writeFile(t, filepath.Join(tmpDir, ".env"), "APP=api\n")
+
+	// Without .envguard/.active the environment is unknown
+	if _, err := Load(Options{Dir: tmpDir}); err != nil {
+		t.Errorf("Expected the key to be optional in an unknown environment, got %v", err)
+	}
+
+	var validationErr *ValidationError
+	if _, err := Load(Options{Dir: tmpDir, As: "production"}); !errors.As(err, &validationErr) {
+		t.Errorf("Expected As to enforce production rules, got %v", err)
+	}
+
+	os.Setenv("ENVGUARD_ENV", "production")
+	defer os.Unsetenv("ENVGUARD_ENV")
+	if _, err := Load(Options{Dir: tmpDir}); !errors.As(err, &validationErr) {
+		t.Errorf("Expected ENVGUARD_ENV to enforce production rules, got %v", err)
+	}
+	if _, err := Load(Options{Dir: tmpDir, As: "development"}); err != nil {
+		t.Errorf("Expected As to take precedence over ENVGUARD_ENV, got %v", err)
+	}
+}